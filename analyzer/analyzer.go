@@ -15,6 +15,7 @@ import "os"
 import "fmt"
 import "path/filepath"
 import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/target"
 import "git.tebibyte.media/arf/arf/parser"
 import "git.tebibyte.media/arf/arf/infoerr"
 
@@ -22,16 +23,77 @@ import "git.tebibyte.media/arf/arf/infoerr"
 type analysisOperation struct {
 	sectionTable SectionTable
 	modulePath   string
+	target       target.Target
 
 	currentPosition locator
 	currentSection  parser.Section
 	currentTree     parser.SyntaxTree
+	currentScopes   types.Stack[*scope]
+
+	// currentWarnings holds the warnings found so far in the current
+	// section. They are kept by the section once it has been analyzed.
+	// This is a pointer, because some analysis methods work on a copy of
+	// the operation.
+	currentWarnings *[]infoerr.Error
+
+	// quiet stops warnings from being printed as they are found.
+	quiet bool
+}
+
+// warn records a warning in the section that is being analyzed, and prints it
+// to stderr unless the operation is quiet.
+func (analyzer *analysisOperation) warn (warning infoerr.Error) {
+	if analyzer.currentWarnings != nil {
+		*analyzer.currentWarnings = append (
+			*analyzer.currentWarnings,
+			warning)
+	}
+	if analyzer.quiet { return }
+	warning.Print()
 }
 
 // Analyze performs a semantic analysis on the module specified by path, and
-// returns a SectionTable that can be translated into C. The result of this is
-// not cached.
-func Analyze (modulePath string, skim bool) (table SectionTable, err error) {
+// returns a SectionTable that can be translated into C. The platform determines
+// the size of machine dependent types such as Int and UInt. The result of this
+// is not cached.
+func Analyze (
+	modulePath string,
+	skim       bool,
+	platform   target.Target,
+) (
+	table SectionTable,
+	err   error,
+) {
+	table, err = analyzeModule(modulePath, platform, false)
+	return
+}
+
+// AnalyzeQuietly is like Analyze, but it does not print warnings to stderr as
+// they are found. The warnings found in each section can still be retrieved
+// with its Warnings method. This is useful for programs
+// that analyze the same code many times over, and report warnings themselves
+// or not at all.
+func AnalyzeQuietly (
+	modulePath string,
+	skim       bool,
+	platform   target.Target,
+) (
+	table SectionTable,
+	err   error,
+) {
+	table, err = analyzeModule(modulePath, platform, true)
+	return
+}
+
+// analyzeModule runs an analysis operation on the module specified by path.
+func analyzeModule (
+	modulePath string,
+	platform   target.Target,
+	quiet      bool,
+) (
+	table SectionTable,
+	err   error,
+) {
 	if modulePath[0] != '/' {
 		cwd, _ := os.Getwd()
 		modulePath = filepath.Join(cwd, modulePath)
@@ -40,6 +102,8 @@ func Analyze (modulePath string, skim bool) (table SectionTable, err error) {
 	analyzer := analysisOperation {
 		sectionTable: make(SectionTable),
 		modulePath:   modulePath,
+		target:       platform,
+		quiet:        quiet,
 	}
 
 	err = analyzer.analyze()
@@ -102,14 +166,21 @@ func (analyzer *analysisOperation) fetchSection (
 	previousPosition := analyzer.currentPosition
 	previousSection  := analyzer.currentSection
 	previousTree     := analyzer.currentTree
+	previousScopes   := analyzer.currentScopes
+	previousWarnings := analyzer.currentWarnings
 	analyzer.currentPosition = where
 	analyzer.currentSection  = parsedSection
 	analyzer.currentTree     = tree
+	analyzer.currentScopes   = nil
+	warnings := []infoerr.Error { }
+	analyzer.currentWarnings = &warnings
 
 	defer func () {
 		analyzer.currentPosition = previousPosition
 		analyzer.currentSection  = previousSection
 		analyzer.currentTree     = previousTree
+		analyzer.currentScopes   = previousScopes
+		analyzer.currentWarnings = previousWarnings
 	} ()
 
 	// analyze section. have analysis methods work on currentPosition
@@ -127,6 +198,8 @@ func (analyzer *analysisOperation) fetchSection (
 		section, err = analyzer.analyzeEnumSection()
 		if err != nil { return}
 	case parser.FaceSection:
+		section, err = analyzer.analyzeFaceSection()
+		if err != nil { return}
 	case parser.DataSection:
 		section, err = analyzer.analyzeDataSection()
 		if err != nil { return}
@@ -134,7 +207,8 @@ func (analyzer *analysisOperation) fetchSection (
 		section, err = analyzer.analyzeFuncSection()
 		if err != nil { return}
 	}
-	
+
+	section.addWarnings(warnings)
 	return
 }
 
@@ -155,8 +229,15 @@ func (analyzer *analysisOperation) resolvePrimitive (
 		return
 	}
 
+	section, exists = primitiveNamed(where.name)
+	return
+}
+
+// primitiveNamed returns a pointer to the primitive or built in type with the
+// specified name, if there is one.
+func primitiveNamed (name string) (section Section, exists bool) {
 	exists = true
-	switch where.name {
+	switch name {
 	case "Int":    section = &PrimitiveInt
 	case "UInt":   section = &PrimitiveUInt
 	case "I8":     section = &PrimitiveI8
@@ -167,10 +248,17 @@ func (analyzer *analysisOperation) resolvePrimitive (
 	case "U16":    section = &PrimitiveU16
 	case "U32":    section = &PrimitiveU32
 	case "U64":    section = &PrimitiveU64
+	case "F32":    section = &PrimitiveF32
+	case "F64":    section = &PrimitiveF64
+	case "Bool":   section = &PrimitiveBool
 	case "Obj":    section = &PrimitiveObj
-	// case "Face":   section = &PrimitiveFace
-	// case "Func":   section = &PrimitiveFunc
+	case "Face":   section = &PrimitiveFace
+	case "Func":   section = &PrimitiveFunc
 	case "String": section = &BuiltInString
+
+	// aliases
+	case "Byte":   section = &PrimitiveU8
+	case "Rune":   section = &PrimitiveU32
 	default:
 		exists = false
 	}
@@ -206,7 +294,12 @@ func (analyzer *analysisOperation) fetchNodeFromIdentifier (
 	var item string
 	item, bitten = which.Bite()
 
-	// TODO: search scopes for variables
+	// search scopes for variables
+	variable := analyzer.lookupVariable(item)
+	if variable != nil {
+		node = variable
+		return
+	}
 
 	// the identifier must be referring to a section
 	var external bool
@@ -275,14 +368,27 @@ func (analyzer *analysisOperation) typeCheck (
 ) (
 	err error,
 ) {
+	// check ranges first, because an integer literal that does not fit is
+	// a more specific problem than a type mismatch
+	err = analyzer.checkIntegerRange(source, destination, 0)
+	if err != nil { return }
+
 	if !source.canBePassedAs(destination) {
 		err = source.NewError (
 			typeMismatchErrorMessage (
 				source.What(),
 				destination),
 			infoerr.ErrorKindError)
+		return
 	}
 
+	analyzer.warnPrecisionLoss(source, destination)
+	return
+}
+
+// wordSize returns the size of Int and UInt in bits on the target platform.
+func (analyzer *analysisOperation) wordSize () (size uint64) {
+	size = uint64(analyzer.target.WordBits())
 	return
 }
 
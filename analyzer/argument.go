@@ -1,20 +1,28 @@
 package analyzer
 
 import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/types"
 import "git.tebibyte.media/arf/arf/parser"
 import "git.tebibyte.media/arf/arf/infoerr"
 
 // Argument represents a value that can be placed anywhere a value goes. This
 // allows things like phrases being arguments to other phrases.
 type Argument interface {
-	// Phrase
+	// OperatorPhrase
+	// CallPhrase
+	// CastPhrase
+	// ReferencePhrase
 	// List
 	// Dereference
-	// Variable
+	// VariableReference
+	// DataReference
+	// MemberAccess
+	// EnumMemberReference
 	// IntLiteral
 	// UIntLiteral
 	// FloatLiteral
 	// StringLiteral
+	// BoolLiteral
 
 	What     () (what Type)
 	Location () (location file.Location)
@@ -56,7 +64,7 @@ type Argument interface {
 //	length is 1
 
 // analyzeArgument analyzes an argument
-func (analyzer analysisOperation) analyzeArgument (
+func (analyzer *analysisOperation) analyzeArgument (
 	inputArgument parser.Argument,
 ) (
 	outputArgument Argument,
@@ -67,19 +75,55 @@ func (analyzer analysisOperation) analyzeArgument (
 		panic("invalid state: attempt to analyze nil argument")
 		
 	case parser.ArgumentKindPhrase:
-		// TODO
+		var phrase Phrase
+		phrase, err = analyzer.analyzePhrase (
+			inputArgument.Value().(parser.Phrase))
+		if err != nil { return }
+
+		var producesValue bool
+		outputArgument, producesValue = phrase.(Argument)
+		if call, isCall := phrase.(CallPhrase); isCall {
+			producesValue = len(call.function.outputs) > 0
+			analyzer.warnDiscarded(call, 1)
+		}
+		
+		if !producesValue {
+			err = phrase.NewError (
+				"this phrase does not produce a value, and " +
+				"cannot be used as an argument",
+				infoerr.ErrorKindError)
+			return
+		}
 		
 	case parser.ArgumentKindDereference:
-		// TODO
+		outputArgument, err = analyzer.analyzeDereference (
+			inputArgument.Value().(parser.Dereference))
 		
 	case parser.ArgumentKindList:
-		// TODO
+		// TODO: analyze lists
+		err = inputArgument.NewError (
+			"list arguments are not supported yet",
+			infoerr.ErrorKindError)
 		
 	case parser.ArgumentKindIdentifier:
-		// TODO
+		outputArgument, err = analyzer.analyzeIdentifierArgument (
+			inputArgument.Value().(parser.Identifier))
 		
 	case parser.ArgumentKindDeclaration:
-		// TODO
+		var variable *Variable
+		variable, err = analyzer.analyzeDeclaration (
+			inputArgument.Value().(parser.Declaration))
+		if err != nil { return }
+		err = analyzer.defineVariable(variable)
+		if err != nil { return }
+
+		outputArgument = VariableReference {
+			locatable:   locatable {
+				location: inputArgument.Location(),
+			},
+			variable:    variable,
+			declaration: true,
+		}
 		
 	case parser.ArgumentKindInt:
 		outputArgument = IntLiteral {
@@ -115,3 +159,192 @@ func (analyzer analysisOperation) analyzeArgument (
 	}
 	return
 }
+
+// analyzeIdentifierArgument analyzes an identifier that is being used as an
+// argument. It must refer to a variable or a data section, optionally followed
+// by the names of members to select.
+func (analyzer *analysisOperation) analyzeIdentifierArgument (
+	identifier parser.Identifier,
+) (
+	outputArgument Argument,
+	err error,
+) {
+	location := locatable { location: identifier.Location() }
+
+	// true and false are constants
+	if identifier.Length() == 1 {
+		switch identifier.Item(0) {
+		case "true":
+			outputArgument = BoolLiteral {
+				locatable: location,
+				value:     true,
+			}
+			return
+		case "false":
+			outputArgument = BoolLiteral { locatable: location }
+			return
+		}
+	}
+
+	var node any
+	var bitten parser.Identifier
+	node, bitten, err = analyzer.fetchNodeFromIdentifier(identifier)
+	if err != nil { return }
+
+	// enum members are selected directly from the enum
+	if section, isEnum := node.(*EnumSection); isEnum {
+		outputArgument, err = analyzer.referenceEnumMember (
+			section,
+			bitten,
+			location)
+		return
+	}
+
+	var isValue bool
+	outputArgument, isValue = analyzer.referenceNode(node, location)
+	if !isValue {
+		err = identifier.NewError (
+			"this must refer to a variable or data section",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	// select members
+	for bitten.Length() > 0 {
+		var name string
+		name, bitten = bitten.Bite()
+		outputArgument, err = analyzer.selectMember (
+			outputArgument,
+			name,
+			location)
+		if err != nil { return }
+	}
+	
+	return
+}
+
+// referenceEnumMember creates an argument that refers to the member of an enum
+// named by the remainder of an identifier.
+func (analyzer *analysisOperation) referenceEnumMember (
+	section  *EnumSection,
+	bitten   parser.Identifier,
+	location locatable,
+) (
+	outputArgument Argument,
+	err error,
+) {
+	if bitten.Length() != 1 {
+		err = location.NewError (
+			"this must refer to a member of " + section.Name(),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	name, _ := bitten.Bite()
+	member, exists := section.Member(name)
+	if !exists {
+		err = location.NewError (
+			section.Name() + " has no member called \"" +
+			name + "\"",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputArgument = EnumMemberReference {
+		locatable: location,
+		section:   section,
+		member:    member,
+	}
+	return
+}
+
+// referenceNode creates an argument that refers to node, if node is a variable
+// or a data section. If it is not, isValue will be false.
+func (analyzer *analysisOperation) referenceNode (
+	node     any,
+	location locatable,
+) (
+	outputArgument Argument,
+	isValue bool,
+) {
+	switch node.(type) {
+	case *Variable:
+		outputArgument = VariableReference {
+			locatable: location,
+			variable:  node.(*Variable),
+		}
+		
+	case *DataSection:
+		section := node.(*DataSection)
+		outputArgument = DataReference {
+			locatable: location,
+			section:   section,
+			external:  !analyzer.inCurrentModule(section),
+		}
+
+	default:
+		return
+	}
+
+	isValue = true
+	return
+}
+
+// selectMember selects the member called name from the object that base
+// refers to. If base is a pointer to an object, the member is selected from
+// the object it points to.
+func (analyzer *analysisOperation) selectMember (
+	base     Argument,
+	name     string,
+	location locatable,
+) (
+	outputArgument Argument,
+	err error,
+) {
+	what := base.What()
+	if what.kind == TypeKindPointer && what.length == 1 {
+		what = *what.points
+	}
+
+	var section *TypeSection
+	isObject := what.kind == TypeKindBasic && what.length == 1
+	if isObject {
+		section, isObject = what.actual.(*TypeSection)
+	}
+	if isObject {
+		isObject = what.underlyingPrimitive() == &PrimitiveObj
+	}
+	if !isObject {
+		err = location.NewError (
+			"cannot select member \"" + name + "\" of " +
+			what.Describe() + ", which is not an object",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	member, exists := section.Member(name)
+	if !exists {
+		err = location.NewError (
+			what.Describe() + " has no member called \"" +
+			name + "\"",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	external := member.modulePath != analyzer.currentPosition.modulePath
+	if external && member.permission == types.PermissionPrivate {
+		err = location.NewError (
+			"member \"" + name + "\" is private (pv), and " +
+			"cannot be accessed outside of its module",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputArgument = MemberAccess {
+		locatable: location,
+		base:      base,
+		member:    member,
+		external:  external,
+	}
+	return
+}
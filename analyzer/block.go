@@ -5,35 +5,120 @@ import "git.tebibyte.media/arf/arf/parser"
 // Block represents a scoped block of phrases.
 type Block struct {
 	phrases []Phrase
-
-	// TODO: create a scope struct and embed it
+	scope
 }
 
 func (block Block) ToString (indent int) (output string) {
 	output += doIndent(indent, "block\n")
 
 	// TODO: variables
-	
+
 	for _, phrase := range block.phrases {
 		output += phrase.ToString(indent + 1)
 	}
 	return
 }
 
-// analyzeBlock analyzes a scoped block of phrases.
-// TODO: have a way to "start out" with a list of variables for things like
-// arguments, and declarations inside of control flow statements
+// Phrases returns the phrases in the block.
+func (block Block) Phrases () (phrases []Phrase) {
+	phrases = block.phrases
+	return
+}
+
+// analyzeBlock analyzes a scoped block of phrases. Variables passed in through
+// seed will be defined in the block's scope before any of its phrases are
+// analyzed. This is useful for things like declarations inside of control flow
+// statements.
 func (analyzer *analysisOperation) analyzeBlock (
 	inputBlock parser.Block,
+	seed ...*Variable,
 ) (
 	block Block,
 	err error,
 ) {
-	for _, inputPhrase := range inputBlock {
+	analyzer.pushScope(&block.scope)
+	defer analyzer.popScope()
+
+	for _, variable := range seed {
+		err = analyzer.defineVariable(variable)
+		if err != nil { return }
+	}
+
+	for index := 0; index < len(inputBlock); index ++ {
+		inputPhrase := inputBlock[index]
+
+		// control flow phrases are analyzed here, because they only
+		// make sense at the level of a block. the ones that are made up
+		// of several phrases in a row are analyzed all at once.
 		var outputPhrase Phrase
-		outputPhrase, err = analyzer.analyzePhrase(inputPhrase)
+		switch inputPhrase.Kind() {
+		case parser.PhraseKindSwitch:
+			var cases []parser.Phrase
+			cases, index = gatherPhrases (
+				inputBlock, index,
+				parser.PhraseKindCase)
+			outputPhrase, err = analyzer.analyzeSwitchPhrase (
+				inputPhrase,
+				cases)
+
+		case parser.PhraseKindIf:
+			var branches []parser.Phrase
+			branches, index = gatherPhrases (
+				inputBlock, index,
+				parser.PhraseKindElseIf,
+				parser.PhraseKindElse)
+			outputPhrase, err = analyzer.analyzeIfPhrase (
+				inputPhrase,
+				branches)
+
+		case parser.PhraseKindWhile:
+			outputPhrase, err = analyzer.analyzeWhilePhrase (
+				inputPhrase)
+
+		case parser.PhraseKindFor:
+			outputPhrase, err = analyzer.analyzeForPhrase (
+				inputPhrase)
+
+		case parser.PhraseKindDefer:
+			outputPhrase, err = analyzer.analyzeDeferPhrase (
+				inputPhrase)
+
+		default:
+			outputPhrase, err = analyzer.analyzePhrase (
+				inputPhrase)
+		}
+		if err != nil { return }
 		block.phrases = append(block.phrases, outputPhrase)
 	}
-	
+
+	return
+}
+
+// gatherPhrases collects the phrases directly after index in a block that are
+// of any of the specified kinds. The index of the last phrase collected is
+// returned, so that the caller can skip over them.
+func gatherPhrases (
+	inputBlock parser.Block,
+	index      int,
+	kinds      ...parser.PhraseKind,
+) (
+	phrases []parser.Phrase,
+	last    int,
+) {
+	last = index
+	for last + 1 < len(inputBlock) {
+		next    := inputBlock[last + 1]
+		matches := false
+		for _, kind := range kinds {
+			if next.Kind() == kind {
+				matches = true
+				break
+			}
+		}
+		if !matches { break }
+		
+		phrases = append(phrases, next)
+		last ++
+	}
 	return
 }
@@ -0,0 +1,359 @@
+package analyzer
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// CallPhrase represents a phrase that calls a function or a method. If it calls
+// a method, receiver will be the value that the method was called on.
+type CallPhrase struct {
+	phraseBase
+	function  *FuncSection
+	receiver  Argument
+	arguments []Argument
+
+	// command is where the name of the function is written.
+	command locatable
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase CallPhrase) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "callPhrase ",
+		phrase.function.where.ToString(), "\n")
+
+	if phrase.receiver != nil {
+		output += doIndent(indent + 1, "receiver\n")
+		output += phrase.receiver.ToString(indent + 2)
+	}
+
+	for _, argument := range phrase.arguments {
+		output += argument.ToString(indent + 1)
+	}
+
+	if len(phrase.returnsTo) > 0 {
+		output += doIndent(indent + 1, "returnsTo\n")
+		for _, returnee := range phrase.returnsTo {
+			output += returnee.ToString(indent + 2)
+		}
+	}
+	return
+}
+
+// What returns the type of the first output of the function being called. When
+// a call is used as an argument, this is the only output that is used.
+func (phrase CallPhrase) What () (what Type) {
+	if len(phrase.function.outputs) > 0 {
+		what = phrase.function.outputs[0].what
+	}
+	return
+}
+
+// Function returns the function or method being called.
+func (phrase CallPhrase) Function () (function *FuncSection) {
+	function = phrase.function
+	return
+}
+
+// Receiver returns the value that a method is being called on. If the phrase
+// calls a function, this is nil.
+func (phrase CallPhrase) Receiver () (receiver Argument) {
+	receiver = phrase.receiver
+	return
+}
+
+// Arguments returns the inputs passed to the function.
+func (phrase CallPhrase) Arguments () (arguments []Argument) {
+	arguments = phrase.arguments
+	return
+}
+
+// ReturnsTo returns where the outputs of the function are stored.
+func (phrase CallPhrase) ReturnsTo () (returnees []Argument) {
+	returnees = phrase.returnsTo
+	return
+}
+
+// Equals returns whether the phrase is equal to the specified value. This is
+// always false, because function calls are not constant.
+func (phrase CallPhrase) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because function calls are not constant.
+func (phrase CallPhrase) Value () (value any) {
+	return
+}
+
+// Resolve returns an error, because function calls are not constant.
+func (phrase CallPhrase) Resolve () (constant Argument, err error) {
+	err = phrase.NewError (
+		"function call cannot be evaluated at compile time",
+		infoerr.ErrorKindError)
+	return
+}
+
+// canBePassedAs returns true if the first output of the function can be passed
+// to the specified type.
+func (phrase CallPhrase) canBePassedAs (what Type) (allowed bool) {
+	allowed = phrase.What().canBePassedAs(what)
+	return
+}
+
+// warnDiscarded prints a warning if only some of the function's outputs are
+// being used. Calls that have all of their outputs discarded are not warned
+// about, because this is usually done on purpose.
+func (analyzer *analysisOperation) warnDiscarded (
+	phrase CallPhrase,
+	used   int,
+) {
+	outputs := len(phrase.function.outputs)
+	if used == 0 || used >= outputs { return }
+
+	analyzer.warn(infoerr.NewError (
+		phrase.location,
+		fmt.Sprint (
+			phrase.function.Name(), " has ", outputs,
+			" outputs, but only ", used, " of them ",
+			plural(used, "is", "are"), " used. the rest will ",
+			"be discarded"),
+		infoerr.ErrorKindWarn))
+}
+
+// analyzeCallPhrase analyzes a phrase that calls a function or a method, along
+// with any values it returns to.
+func (analyzer *analysisOperation) analyzeCallPhrase (
+	base        phraseBase,
+	inputPhrase parser.Phrase,
+	arguments   []Argument,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := CallPhrase {
+		phraseBase: base,
+		arguments:  arguments,
+	}
+	outputPhrase.command.location = inputPhrase.Command().Location()
+
+	outputPhrase.function,
+	outputPhrase.receiver,
+	err = analyzer.fetchCallee(inputPhrase.Command())
+	if err != nil { return }
+	function := outputPhrase.function
+
+	// check inputs
+	if len(arguments) != len(function.inputs) {
+		err = outputPhrase.NewError (
+			fmt.Sprint (
+				function.Name(), " expects ",
+				len(function.inputs), " ",
+				plural(len(function.inputs), "input", "inputs"),
+				", but ", len(arguments), " ",
+				plural(len(arguments), "was", "were"),
+				" given"),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	for index, argument := range arguments {
+		err = analyzer.typeCheck(argument, function.inputs[index].what)
+		if err != nil { return }
+	}
+
+	// check returnees
+	outputPhrase.returnsTo, err = analyzer.analyzeReturnees (
+		inputPhrase,
+		function)
+	if err != nil { return }
+	analyzer.warnDiscarded(outputPhrase, len(outputPhrase.returnsTo))
+
+	phrase = outputPhrase
+	return
+}
+
+// fetchCallee finds the function or method that the command of a call phrase
+// refers to. If it is a method, the value it is being called on is returned as
+// the receiver.
+func (analyzer *analysisOperation) fetchCallee (
+	command parser.Argument,
+) (
+	function *FuncSection,
+	receiver Argument,
+	err      error,
+) {
+	identifier := command.Value().(parser.Identifier)
+	var node any
+	var bitten parser.Identifier
+	node, bitten, err = analyzer.fetchNodeFromIdentifier(identifier)
+	if err != nil { return }
+
+	location := locatable { location: identifier.Location() }
+
+	// the identifier refers directly to a function
+	if section, isFunction := node.(*FuncSection); isFunction {
+		if bitten.Length() > 0 {
+			err = identifier.NewError (
+				"cannot select members of a function",
+				infoerr.ErrorKindError)
+			return
+		}
+		if section.receiver != nil {
+			err = identifier.NewError (
+				section.Name() + " is a method, and must be " +
+				"called on a value",
+				infoerr.ErrorKindError)
+			return
+		}
+
+		function = section
+		return
+	}
+
+	// the identifier must refer to a method of a value
+	var isValue bool
+	receiver, isValue = analyzer.referenceNode(node, location)
+	if !isValue || bitten.Length() == 0 {
+		err = identifier.NewError (
+			"this must refer to a function or method",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	for bitten.Length() > 1 {
+		var name string
+		name, bitten = bitten.Bite()
+		receiver, err = analyzer.selectMember(receiver, name, location)
+		if err != nil { return }
+	}
+
+	name, _ := bitten.Bite()
+	function, err = analyzer.fetchMethod(receiver.What(), name)
+	if err != nil { return }
+
+	if function == nil {
+		err = identifier.NewError (
+			receiver.What().Describe() + " has no method " +
+			"called \"" + name + "\"",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	external := !analyzer.inCurrentModule(function)
+	if external && function.Permission() == types.PermissionPrivate {
+		err = identifier.NewError (
+			"this method is private, and cannot be used " +
+			"outside of its module",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	// methods that take in a pointer to mutable data can modify their
+	// receiver, so they cannot be called on immutable values
+	points  := function.receiver.what.points
+	mutates := points != nil && points.mutable
+	if mutates && !canModifyThrough(receiver) {
+		err = identifier.NewError (
+			name + " modifies its receiver, so it " +
+			"cannot be called on an immutable value",
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
+
+
+// fetchMethod searches for a method called name on the specified type. If the
+// type is a pointer, the type it points to is searched instead. If the method
+// cannot be found on the type itself, the types it inherits from are searched.
+// If no method is found, nil is returned.
+func (analyzer *analysisOperation) fetchMethod (
+	what Type,
+	name string,
+) (
+	method *FuncSection,
+	err error,
+) {
+	if what.kind == TypeKindPointer && what.length == 1 {
+		what = *what.points
+	}
+
+	for what.kind == TypeKindBasic && what.length == 1 {
+		owner, isTypeSection := what.actual.(*TypeSection)
+		if !isTypeSection { return }
+
+		// primitives do not come from any module, and have no methods
+		if owner.where.modulePath == "" { return }
+
+		var section Section
+		section, err = analyzer.fetchSection (locator {
+			modulePath: owner.where.modulePath,
+			name:       owner.where.name + "_" + name,
+		})
+		if err != nil { return }
+
+		var isFunction bool
+		method, isFunction = section.(*FuncSection)
+		if isFunction { return }
+
+		what = owner.what
+	}
+	return
+}
+
+// analyzeReturnees analyzes the arguments after the return direction of a
+// phrase, making sure that each of them can store its respective output of the
+// specified function. Returnees can be declarations, in which case the new
+// variables are defined in the current scope.
+func (analyzer *analysisOperation) analyzeReturnees (
+	inputPhrase parser.Phrase,
+	function    *FuncSection,
+) (
+	returnees []Argument,
+	err error,
+) {
+	outputs := function.outputs
+	amount  := len(outputs)
+	for index := 0; index < inputPhrase.ReturneesLength(); index ++ {
+		inputReturnee := inputPhrase.Returnee(index)
+		if index >= len(outputs) {
+			err = inputReturnee.NewError (
+				fmt.Sprint (
+					function.Name(), " only has ",
+					amount, " ",
+					plural(amount, "output", "outputs")),
+				infoerr.ErrorKindError)
+			return
+		}
+
+		var returnee Argument
+		returnee, err = analyzer.analyzeArgument(inputReturnee)
+		if err != nil { return }
+
+		err = analyzer.checkAssignmentTarget(returnee)
+		if err != nil { return }
+
+		output      := outputs[index].what
+		destination := returnee.What()
+		if !output.canBePassedAs(destination) {
+			err = returnee.NewError (
+				typeMismatchErrorMessage(output, destination),
+				infoerr.ErrorKindError)
+			return
+		}
+
+		returnees = append(returnees, returnee)
+	}
+	return
+}
+
+// plural returns one if amount is one, and many otherwise.
+func plural (amount int, one, many string) (word string) {
+	if amount == 1 {
+		word = one
+	} else {
+		word = many
+	}
+	return
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestCallPhrase (test *testing.T) {
+	checkTree ("../tests/analyzer/callPhrase", false,
+`typeSection ro ../tests/analyzer/callPhrase.aRect
+	type 1 basic Obj
+	member ro width
+		type 1 basic Int
+	member ro height
+		type 1 basic Int
+funcSection ro ../tests/analyzer/callPhrase.aRect_bArea
+	receiver rect
+		type 1 pointer {
+			type 1 basic aRect
+		}
+	output area
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable area
+			operatorPhrase *
+				type 1 basic Int
+				member width
+					variable rect
+				member height
+					variable rect
+funcSection ro ../tests/analyzer/callPhrase.cDivide
+	input dividend
+		type 1 basic Int
+	input divisor
+		type 1 basic Int
+	output quotient
+		type 1 basic Int
+	output remainder
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable quotient
+			operatorPhrase /
+				type 1 basic Int
+				variable dividend
+				variable divisor
+		assignPhrase =
+			variable remainder
+			operatorPhrase %
+				type 1 basic Int
+				variable dividend
+				variable divisor
+funcSection ro ../tests/analyzer/callPhrase.dCalls
+	input rect
+		type 1 basic aRect
+	output area
+		type 1 basic Int
+	block
+		callPhrase ../tests/analyzer/callPhrase.cDivide
+			uintLiteral 10
+			uintLiteral 3
+			returnsTo
+				declaration quotient
+					type 1 basic Int
+				declaration remainder
+					type 1 basic Int
+		callPhrase ../tests/analyzer/callPhrase.cDivide
+			variable area
+			uintLiteral 3
+			returnsTo
+				variable area
+		callPhrase ../tests/analyzer/callPhrase.aRect_bArea
+			receiver
+				variable rect
+			returnsTo
+				variable area
+		assignPhrase =
+			variable area
+			operatorPhrase +
+				type 1 basic Int
+				callPhrase ../tests/analyzer/callPhrase.aRect_bArea
+					receiver
+						variable rect
+				variable quotient
+`, test)
+}
+
+func TestReturneeType (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/returneeType",
+		infoerr.ErrorKindError,
+		"Int cannot be used as U8",
+		10, 10, test)
+}
+
+func TestReturneeCount (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/returneeCount",
+		infoerr.ErrorKindError,
+		"aFunc only has 1 output",
+		10, 16, test)
+}
+
+func TestDiscardedOutputs (test *testing.T) {
+	checkWarnings("../tests/analyzer/discard", []string {
+		"15:1 aDivide has 2 outputs, but only 1 of them is used. " +
+		"the rest will be discarded",
+		"16:13 aDivide has 2 outputs, but only 1 of them is used. " +
+		"the rest will be discarded",
+	}, test)
+}
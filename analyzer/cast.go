@@ -0,0 +1,405 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// CastPhrase represents a phrase that converts a value to a different type,
+// such as [cast value Type].
+type CastPhrase struct {
+	phraseBase
+	value      Argument
+	what       Type
+	conversion Conversion
+
+	// width is the size of the result in bits if it is an integer. it is
+	// used to wrap constant values around when they are evaluated at
+	// compile time.
+	width uint64
+}
+
+// Conversion determines how a cast phrase converts its value.
+type Conversion int
+
+const (
+	// ConversionNone means that the value can already be used as the new
+	// type, and nothing needs to be done to it.
+	ConversionNone Conversion = iota
+
+	// ConversionNumeric converts between numbers of any size, signedness,
+	// or precision. Integers that are too large for their new type wrap
+	// around, and floats are truncated towards zero when they are
+	// converted to integers. Truth values become either zero or one.
+	ConversionNumeric
+
+	// ConversionTruth converts a number into a truth value, which is true
+	// if the number is not zero.
+	ConversionTruth
+
+	// ConversionEnum converts between an enum and the type it is based on.
+	ConversionEnum
+
+	// ConversionPointer converts a pointer to a pointer of a compatible
+	// type, without changing the data that it points to.
+	ConversionPointer
+
+	// ConversionObject reinterprets an object as an object type that it
+	// inherits from. Since inherited members come first, this only reads
+	// the start of the object.
+	ConversionObject
+)
+
+// ToString returns the name of the conversion.
+func (conversion Conversion) ToString () (output string) {
+	switch conversion {
+	case ConversionNone:
+		output = "none"
+	case ConversionNumeric:
+		output = "numeric"
+	case ConversionTruth:
+		output = "truth"
+	case ConversionEnum:
+		output = "enum"
+	case ConversionPointer:
+		output = "pointer"
+	case ConversionObject:
+		output = "object"
+	}
+	return
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase CastPhrase) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "castPhrase ", phrase.conversion.ToString(), "\n")
+	output += phrase.what.ToString(indent + 1)
+	output += phrase.value.ToString(indent + 1)
+	return
+}
+
+// What returns the type that the value is being converted to.
+func (phrase CastPhrase) What () (what Type) {
+	what = phrase.what
+	return
+}
+
+// Operand returns the value that is being converted.
+func (phrase CastPhrase) Operand () (operand Argument) {
+	operand = phrase.value
+	return
+}
+
+// Conversion returns how the value is converted.
+func (phrase CastPhrase) Conversion () (conversion Conversion) {
+	conversion = phrase.conversion
+	return
+}
+
+// Equals returns whether the phrase is equal to the specified value. This is
+// always false, because phrases are not constant.
+func (phrase CastPhrase) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because phrases are not constant.
+func (phrase CastPhrase) Value () (value any) {
+	return
+}
+
+// Resolve evaluates the conversion at compile time, if the value is a constant
+// and it is being converted to a number or a truth value.
+func (phrase CastPhrase) Resolve () (constant Argument, err error) {
+	convertible :=
+		phrase.what.isSingular() &&
+		(phrase.what.isNumeric() || phrase.what.isBoolean())
+	if !convertible {
+		err = phrase.NewError (
+			"conversion to " + phrase.what.Describe() + " cannot " +
+			"be evaluated at compile time",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	value, err := resolveConstant(phrase.value)
+	if err != nil { return }
+
+	result, err := convertConstant (
+		value,
+		phrase.what,
+		phrase.width,
+		phrase.locatable)
+	if err != nil { return }
+
+	constant, err = result.literal(phrase.location)
+	return
+}
+
+// canBePassedAs returns true if the type being converted to can be passed to
+// the specified type.
+func (phrase CastPhrase) canBePassedAs (what Type) (allowed bool) {
+	allowed = phrase.what.canBePassedAs(what)
+	return
+}
+
+// analyzeCastPhrase analyzes a phrase of the form [cast value Type].
+func (analyzer *analysisOperation) analyzeCastPhrase (
+	base        phraseBase,
+	inputPhrase parser.Phrase,
+	arguments   []Argument,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	if len(arguments) != 1 {
+		err = base.NewError (
+			"cast expects exactly one value and one type",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase := CastPhrase {
+		phraseBase: base,
+		value:      arguments[0],
+	}
+
+	inputType := inputPhrase.Argument(inputPhrase.Length() - 1)
+	outputPhrase.what, err = analyzer.analyzeCastType(inputType)
+	if err != nil { return }
+
+	if outputPhrase.what.isSingular() {
+		outputPhrase.width =
+			outputPhrase.what.bitWidth(analyzer.wordSize())
+	}
+
+	outputPhrase.conversion, err = analyzer.checkConversion (
+		outputPhrase.value,
+		outputPhrase.what,
+		base)
+	if err != nil { return }
+
+	phrase = outputPhrase
+	return
+}
+
+// analyzeCastType analyzes the type at the end of a cast phrase. This can
+// either be the name of a type, or the name of a type surrounded by curly
+// braces, which is a pointer to that type.
+func (analyzer *analysisOperation) analyzeCastType (
+	inputType parser.Argument,
+) (
+	what Type,
+	err  error,
+) {
+	what.location = inputType.Location()
+	what.length   = 1
+
+	dereference, isPointer := inputType.Value().(parser.Dereference)
+	if isPointer && dereference.Offset() == 0 {
+		points := Type { }
+		points, err = analyzer.analyzeCastType(dereference.Argument())
+		if err != nil { return }
+
+		what.kind   = TypeKindPointer
+		what.points = &points
+		return
+	}
+
+	name, isIdentifier := inputType.Value().(parser.Identifier)
+	if !isIdentifier {
+		err = inputType.NewError (
+			"the last argument of a cast must be a type",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	what.actual, err = analyzer.analyzeTypeName(name)
+	return
+}
+
+// checkConversion determines how a value can be converted to the specified
+// type, and returns an error if it cannot be.
+func (analyzer *analysisOperation) checkConversion (
+	value       Argument,
+	destination Type,
+	where       phraseBase,
+) (
+	conversion Conversion,
+	err        error,
+) {
+	source := value.What()
+
+	// string literals can be used as pointers to their data
+	_, stringToPointer := value.(StringLiteral)
+	if stringToPointer && isPointer(destination) {
+		reduced, _ := destination.reduce()
+		stringToPointer = reduced.points.isNumeric()
+	} else {
+		stringToPointer = false
+	}
+
+	switch {
+	case value.canBePassedAs(destination):
+		conversion = ConversionNone
+
+	case stringToPointer:
+		conversion = ConversionPointer
+
+	case isEnum(source) || isEnum(destination):
+		if !enumBase(source).Equals(enumBase(destination)) {
+			err = conversionError(source, destination, where)
+			return
+		}
+		conversion = ConversionEnum
+
+	case isScalar(source) && isScalar(destination):
+		if destination.isBoolean() && !source.isBoolean() {
+			conversion = ConversionTruth
+		} else {
+			conversion = ConversionNumeric
+		}
+
+	case isPointer(source) && isPointer(destination):
+		sourcePoints, _      := source.reduce()
+		destinationPoints, _ := destination.reduce()
+		if !analyzer.pointeesCompatible (
+			*sourcePoints.points,
+			*destinationPoints.points,
+		) {
+			err = conversionError(source, destination, where)
+			return
+		}
+
+		mutable :=
+			!sourcePoints.points.mutable &&
+			destinationPoints.points.mutable
+		if mutable {
+			err = where.NewError (
+				"cannot cast a pointer to immutable data " +
+				"into a pointer to mutable data",
+				infoerr.ErrorKindError)
+			return
+		}
+		conversion = ConversionPointer
+
+	case isObject(source) && isObject(destination):
+		if destination.inheritsFrom(source) {
+			// the object does not have the members that the
+			// destination adds, so there would be nothing to read
+			err = where.NewError (
+				"cannot cast object type " + source.Describe() +
+				" down to " + destination.Describe() +
+				", objects can only be cast to types that " +
+				"they inherit from",
+				infoerr.ErrorKindError)
+			return
+		}
+		if !source.inheritsFrom(destination) {
+			err = where.NewError (
+				"cannot cast between unrelated object types " +
+				source.Describe() + " and " +
+				destination.Describe(),
+				infoerr.ErrorKindError)
+			return
+		}
+		conversion = ConversionObject
+
+	default:
+		err = conversionError(source, destination, where)
+	}
+	return
+}
+
+// conversionError returns an error saying that a value of type source cannot
+// be cast to destination.
+func conversionError (
+	source      Type,
+	destination Type,
+	where       phraseBase,
+) (
+	err error,
+) {
+	err = where.NewError (
+		"cannot cast " + source.Describe() + " to " +
+		destination.Describe(),
+		infoerr.ErrorKindError)
+	return
+}
+
+// pointeesCompatible returns whether a pointer to source can be cast to a
+// pointer to destination. This is true if they are the same type, if one is an
+// enum based on the other, if they are integers of the same size, or if they
+// are objects and one inherits from the other.
+func (analyzer *analysisOperation) pointeesCompatible (
+	source      Type,
+	destination Type,
+) (
+	compatible bool,
+) {
+	if enumBase(source).Equals(enumBase(destination)) {
+		compatible = true
+		return
+	}
+
+	if source.isInteger() && destination.isInteger() {
+		wordSize := analyzer.wordSize()
+		compatible =
+			source.isSingular() &&
+			destination.isSingular() &&
+			source.bitWidth(wordSize) ==
+			destination.bitWidth(wordSize)
+		return
+	}
+
+	if isObject(source) && isObject(destination) {
+		compatible =
+			source.inheritsFrom(destination) ||
+			destination.inheritsFrom(source)
+	}
+	return
+}
+
+// isEnum returns whether the type is a single value of an enum.
+func isEnum (what Type) (enum bool) {
+	_, enum = what.actual.(*EnumSection)
+	enum = enum && what.kind == TypeKindBasic && what.length == 1
+	return
+}
+
+// enumBase returns the type that an enum is based on. If the type is not an
+// enum, it is returned unchanged.
+func enumBase (what Type) (base Type) {
+	base = what
+	for isEnum(base) {
+		base = base.actual.(*EnumSection).what
+	}
+	return
+}
+
+// isScalar returns whether the type is a single number or truth value.
+func isScalar (what Type) (scalar bool) {
+	scalar =
+		what.isSingular() &&
+		what.kind == TypeKindBasic &&
+		(what.isNumeric() || what.isBoolean())
+	return
+}
+
+// isPointer returns whether the type is a single pointer, or is a type that
+// inherits from a single pointer.
+func isPointer (what Type) (pointer bool) {
+	reduced, reducible := what.reduce()
+	pointer =
+		reducible &&
+		what.length == 1 &&
+		reduced.kind == TypeKindPointer &&
+		reduced.length == 1
+	return
+}
+
+// isObject returns whether the type is a single object.
+func isObject (what Type) (object bool) {
+	object =
+		what.kind == TypeKindBasic &&
+		what.length == 1 &&
+		what.underlyingPrimitive() == &PrimitiveObj
+	return
+}
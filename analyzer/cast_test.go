@@ -0,0 +1,188 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestCastPhrase (test *testing.T) {
+	checkTree ("../tests/analyzer/castPhrase", false,
+`typeSection ro ../tests/analyzer/castPhrase.aShape
+	type 1 basic Obj
+	member ro sides
+		type 1 basic Int
+typeSection ro ../tests/analyzer/castPhrase.bSquare
+	type 1 basic aShape
+	member ro width
+		type 1 basic Int
+enumSection ro ../tests/analyzer/castPhrase.cColor
+	uintLiteral 0
+	type 1 basic U8
+	member red
+		uintLiteral 0
+	member green
+		uintLiteral 1
+	member blue
+		uintLiteral 2
+typeSection ro ../tests/analyzer/castPhrase.dBytes
+	type 1 pointer {
+		type 1 basic U8
+	}
+funcSection ro ../tests/analyzer/castPhrase.eNumbers
+	input number
+		type 1 basic Int
+	input real
+		type 1 basic F64
+	input truth
+		type 1 basic Bool
+	output small
+		type 1 basic U8
+	output whole
+		type 1 basic I32
+	output fraction
+		type 1 basic F32
+	output nonZero
+		type 1 basic Bool
+	output count
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable small
+			castPhrase numeric
+				type 1 basic U8
+				variable number
+		assignPhrase =
+			variable whole
+			castPhrase numeric
+				type 1 basic I32
+				variable real
+		assignPhrase =
+			variable fraction
+			castPhrase numeric
+				type 1 basic F32
+				variable number
+		assignPhrase =
+			variable nonZero
+			castPhrase truth
+				type 1 basic Bool
+				variable number
+		assignPhrase =
+			variable count
+			castPhrase numeric
+				type 1 basic Int
+				variable truth
+funcSection ro ../tests/analyzer/castPhrase.fEnums
+	input color
+		type 1 basic cColor
+	input number
+		type 1 basic U8
+	output raw
+		type 1 basic U8
+	output back
+		type 1 basic cColor
+	block
+		assignPhrase =
+			variable raw
+			castPhrase enum
+				type 1 basic U8
+				variable color
+		assignPhrase =
+			variable back
+			castPhrase enum
+				type 1 basic cColor
+				variable number
+funcSection ro ../tests/analyzer/castPhrase.gPointers
+	input bytes
+		type 1 basic dBytes
+	input signed
+		type 1 pointer {
+			type 1 basic I8
+		}
+	output unsigned
+		type 1 pointer {
+			type 1 basic U8
+		}
+	output named
+		type 1 basic dBytes
+	block
+		assignPhrase =
+			variable unsigned
+			castPhrase pointer
+				type 1 pointer {
+					type 1 basic U8
+				}
+				variable signed
+		assignPhrase =
+			variable named
+			castPhrase pointer
+				type 1 basic dBytes
+				variable unsigned
+		assignPhrase =
+			variable unsigned
+			castPhrase pointer
+				type 1 pointer {
+					type 1 basic U8
+				}
+				variable bytes
+funcSection ro ../tests/analyzer/castPhrase.hObjects
+	input square
+		type 1 basic bSquare
+	input shape
+		type 1 pointer {
+			type 1 basic aShape
+		}
+	output general
+		type 1 basic aShape
+	output specific
+		type 1 pointer {
+			type 1 basic bSquare
+		}
+	block
+		assignPhrase =
+			variable general
+			castPhrase object
+				type 1 basic aShape
+				variable square
+		assignPhrase =
+			variable specific
+			castPhrase pointer
+				type 1 pointer {
+					type 1 basic bSquare
+				}
+				variable shape
+`, test)
+}
+
+func TestCastUnrelated (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/castUnrelated",
+		infoerr.ErrorKindError,
+		"cannot cast between unrelated object types " +
+		"castUnrelated.aCircle and castUnrelated.bLine",
+		12, 8, test)
+}
+
+func TestCastDowncast (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/castDowncast",
+		infoerr.ErrorKindError,
+		"cannot cast object type castDowncast.aShape down to " +
+		"castDowncast.bSquare, objects can only be cast to types " +
+		"that they inherit from",
+		12, 10, test)
+}
+
+func TestCastMutablePointer (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/castMutablePointer",
+		infoerr.ErrorKindError,
+		"cannot cast a pointer to immutable data into a pointer to " +
+		"mutable data",
+		8, 12, test)
+}
+
+func TestCastInvalid (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/castInvalid",
+		infoerr.ErrorKindError,
+		"cannot cast Int to {U8}",
+		6, 9, test)
+}
@@ -0,0 +1,341 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/file"
+
+// flowNode is a single step in the control flow graph of a function. Most nodes
+// stand for a phrase that does not contain any blocks, but control flow phrases
+// are split up into several nodes, such as one for each of their conditions.
+type flowNode struct {
+	locatable
+
+	// accesses contains every use of a variable made by this node, in the
+	// order that they happen.
+	accesses []access
+
+	next      []*flowNode
+	previous  []*flowNode
+	reachable bool
+}
+
+// flowNote is a warning about a node in the control flow graph that should
+// only be shown if the node can actually be reached.
+type flowNote struct {
+	node    *flowNode
+	message string
+}
+
+// flowGraph is the control flow graph of the body of a function. It is used to
+// work out which code can run, and in what order.
+type flowGraph struct {
+	entry *flowNode
+	exit  *flowNode
+
+	// nodes contains every node in the graph apart from the entry and
+	// the exit, in roughly the order that they appear in the code.
+	nodes []*flowNode
+
+	// starts maps the location of each phrase to the first node that is
+	// run as a part of it.
+	starts map[file.Location] *flowNode
+
+	// deferred contains the blocks of every defer phrase, in the order
+	// that they appear.
+	deferred []Block
+
+	notes []flowNote
+}
+
+// buildFlowGraph builds the control flow graph of a function. Branches that can
+// be decided at compile time are left out of the graph, so that the code they
+// skip over is unreachable.
+func buildFlowGraph (function *FuncSection) (graph *flowGraph) {
+	graph = &flowGraph {
+		starts: make(map[file.Location] *flowNode),
+	}
+	graph.entry = &flowNode { locatable: function.locatable }
+	graph.exit  = &flowNode { locatable: function.locatable }
+
+	ends := graph.block(function.root, []*flowNode { graph.entry })
+
+	// deferred blocks run right before the function returns, starting
+	// with the last one
+	for index := len(graph.deferred) - 1; index >= 0; index -- {
+		ends = graph.block(graph.deferred[index], ends)
+	}
+	graph.link(ends, graph.exit)
+
+	graph.markReachable(graph.entry)
+	return
+}
+
+// node adds a new node to the graph that comes after every node in from.
+func (graph *flowGraph) node (
+	where    locatable,
+	accesses []access,
+	from     []*flowNode,
+) (
+	node *flowNode,
+) {
+	node = &flowNode {
+		locatable: where,
+		accesses:  accesses,
+	}
+	graph.nodes = append(graph.nodes, node)
+	graph.link(from, node)
+	return
+}
+
+// link makes every node in from lead to another node.
+func (graph *flowGraph) link (from []*flowNode, to *flowNode) {
+	for _, node := range from {
+		node.next   = append(node.next, to)
+		to.previous = append(to.previous, node)
+	}
+}
+
+// note adds a warning about a node that will be shown if it is reachable.
+func (graph *flowGraph) note (node *flowNode, message string) {
+	graph.notes = append(graph.notes, flowNote {
+		node:    node,
+		message: message,
+	})
+}
+
+// block adds the phrases of a block to the graph, starting after every node in
+// from. It returns the nodes that the block can end on.
+func (graph *flowGraph) block (
+	block Block,
+	from  []*flowNode,
+) (
+	ends []*flowNode,
+) {
+	ends = from
+	for _, phrase := range block.phrases {
+		ends = graph.phrase(phrase, ends)
+	}
+	return
+}
+
+// phrase adds a phrase to the graph, starting after every node in from. It
+// returns the nodes that the phrase can end on.
+func (graph *flowGraph) phrase (
+	phrase Phrase,
+	from   []*flowNode,
+) (
+	ends []*flowNode,
+) {
+	first := len(graph.nodes)
+	where := locatable { location: phrase.Location() }
+
+	switch phrase.(type) {
+	case IfPhrase:
+		ends = graph.ifPhrase(phrase.(IfPhrase), from)
+
+	case SwitchPhrase:
+		ends = graph.switchPhrase(phrase.(SwitchPhrase), from)
+
+	case WhilePhrase:
+		whilePhrase := phrase.(WhilePhrase)
+		condition := graph.node (
+			where,
+			argumentAccesses(whilePhrase.condition, nil),
+			from)
+
+		value, known := constantTruth(whilePhrase.condition)
+		body := []*flowNode { condition }
+		if known && !value { body = nil }
+		graph.link(graph.block(whilePhrase.block, body), condition)
+
+		// there is no way to break out of a loop, so if the condition
+		// is always true, nothing after it can ever run
+		if known && value {
+			graph.note (
+				condition,
+				"this loop never ends, because its condition " +
+				"is always true")
+		} else {
+			ends = []*flowNode { condition }
+		}
+
+	case ForPhrase:
+		forPhrase := phrase.(ForPhrase)
+		head := graph.node (
+			where,
+			argumentAccesses(forPhrase.collection, nil),
+			from)
+
+		body := []*flowNode { head }
+		if isEmptyConstant(forPhrase.collection) {
+			body = nil
+			graph.note (
+				head,
+				"this loop never runs, because the array it " +
+				"goes over is empty")
+		}
+
+		// the index and element are given a new value at the start
+		// of each time the loop runs
+		step := graph.node(where, forPhrase.loopAccesses(), body)
+		graph.link (
+			graph.block(forPhrase.block, []*flowNode { step }),
+			head)
+		ends = []*flowNode { head }
+
+	case DeferPhrase:
+		deferPhrase := phrase.(DeferPhrase)
+		graph.deferred = append(graph.deferred, deferPhrase.block)
+		ends = []*flowNode { graph.node(where, nil, from) }
+
+	default:
+		ends = []*flowNode {
+			graph.node(where, phraseAccesses(phrase), from),
+		}
+	}
+
+	if len(graph.nodes) > first {
+		graph.starts[phrase.Location()] = graph.nodes[first]
+	}
+	return
+}
+
+// ifPhrase adds an if phrase to the graph. Each condition is only checked if
+// the ones before it were false.
+func (graph *flowGraph) ifPhrase (
+	phrase IfPhrase,
+	from   []*flowNode,
+) (
+	ends []*flowNode,
+) {
+	for _, branch := range phrase.branches {
+		condition := graph.node (
+			branch.locatable,
+			argumentAccesses(branch.condition, nil),
+			from)
+
+		taken   := []*flowNode { condition }
+		skipped := []*flowNode { condition }
+		value, known := constantTruth(branch.condition)
+		if known && value  { skipped = nil }
+		if known && !value { taken   = nil }
+
+		ends = append(ends, graph.block(branch.block, taken)...)
+		from = skipped
+	}
+
+	if phrase.fallback == nil {
+		ends = append(ends, from...)
+	} else {
+		ends = append(ends, graph.block(*phrase.fallback, from)...)
+	}
+	return
+}
+
+// switchPhrase adds a switch phrase to the graph. If the value being switched
+// on is constant, only the case that matches it is reachable.
+func (graph *flowGraph) switchPhrase (
+	phrase SwitchPhrase,
+	from   []*flowNode,
+) (
+	ends []*flowNode,
+) {
+	head := graph.node (
+		locatable { location: phrase.Location() },
+		phrase.headAccesses(),
+		from)
+
+	matched, known := phrase.matchCase()
+	for index, casePhrase := range phrase.cases {
+		entry := []*flowNode { head }
+		if known && index != matched { entry = nil }
+		ends = append(ends, graph.block(casePhrase.block, entry)...)
+	}
+
+	fallsThrough := phrase.canFallThrough()
+	if known { fallsThrough = matched < 0 }
+	if fallsThrough {
+		ends = append(ends, head)
+	}
+	return
+}
+
+// markReachable marks a node and every node that can be reached from it as
+// reachable.
+func (graph *flowGraph) markReachable (node *flowNode) {
+	if node.reachable { return }
+	node.reachable = true
+	for _, next := range node.next {
+		graph.markReachable(next)
+	}
+}
+
+// constantTruth evaluates a condition at compile time. If it cannot be, known
+// will be false.
+func constantTruth (condition Argument) (value bool, known bool) {
+	if !isConstant(condition) { return }
+	literal, err := condition.Resolve()
+	if err != nil { return }
+
+	boolean, isBool := literal.(BoolLiteral)
+	if !isBool { return }
+	value = boolean.value
+	known = true
+	return
+}
+
+// isEmptyConstant returns whether an argument is an array that is known to be
+// empty at compile time.
+func isEmptyConstant (argument Argument) (empty bool) {
+	if !isConstant(argument) { return }
+	literal, err := argument.Resolve()
+	if err != nil { return }
+
+	text, isString := literal.(StringLiteral)
+	empty = isString && len(text.value) == 0
+	return
+}
+
+// matchCase works out which case of a switch phrase will run if the value being
+// switched on is constant. If no case will run, matched is -1. If it cannot be
+// worked out at compile time, known is false.
+func (phrase SwitchPhrase) matchCase () (matched int, known bool) {
+	if !isConstant(phrase.subject) { return }
+	subject, err := phrase.subject.Resolve()
+	if err != nil { return }
+
+	matched = -1
+	for index, casePhrase := range phrase.cases {
+		if casePhrase.IsDefault() {
+			matched = index
+			continue
+		}
+
+		for _, value := range casePhrase.values {
+			if !isConstant(value) { return }
+			literal, err := value.Resolve()
+			if err != nil { return }
+
+			if literal.Equals(subject.Value()) {
+				matched = index
+				known   = true
+				return
+			}
+		}
+	}
+
+	known = true
+	return
+}
+
+// loopAccesses returns the accesses that a for phrase makes when it gives its
+// index and element new values.
+func (phrase ForPhrase) loopAccesses () (accesses []access) {
+	for _, variable := range []*Variable { phrase.index, phrase.element } {
+		if variable == nil { continue }
+		accesses = append(accesses, access {
+			locatable: variable.locatable,
+			kind:      accessWrite,
+			variable:  variable,
+		})
+	}
+	return
+}
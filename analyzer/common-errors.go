@@ -6,3 +6,16 @@ func typeMismatchErrorMessage (source Type, destination Type) (message string) {
 	message += destination.Describe()
 	return
 }
+
+func operandMismatchErrorMessage (
+	info   operatorInfo,
+	first  Type,
+	second Type,
+) (
+	message string,
+) {
+	message += "operator " + info.symbol
+	message += " cannot mix operands of type "
+	message += first.Describe() + " and " + second.Describe()
+	return
+}
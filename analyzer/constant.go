@@ -0,0 +1,527 @@
+package analyzer
+
+import "fmt"
+import "math"
+import "math/big"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// constantKind determines what kind of value a constant holds.
+type constantKind int
+
+const (
+	constantKindInteger constantKind = iota
+	constantKindFloat
+	constantKindBool
+)
+
+// constantValue is a value that is known at compile time. It is used as an
+// intermediate form while folding phrases into literals, because integers can
+// be held at arbitrary precision here.
+type constantValue struct {
+	kind    constantKind
+	integer *big.Int
+	float   float64
+	boolean bool
+}
+
+// isConstant returns whether an argument can be evaluated at compile time.
+// Arguments that are not constant are not necessarily invalid, they just need
+// to be evaluated when the program runs.
+func isConstant (argument Argument) (constant bool) {
+	constant, _ = checkConstant(argument, make(map[*DataSection] bool))
+	return
+}
+
+// checkConstant is like isConstant, but it keeps track of the data sections
+// that are being evaluated. If one of them is reached again, it refers to
+// itself and can never be evaluated, so an error is returned.
+func checkConstant (
+	argument   Argument,
+	evaluating map[*DataSection] bool,
+) (
+	constant bool,
+	err      error,
+) {
+	switch argument.(type) {
+	case IntLiteral, UIntLiteral, FloatLiteral, BoolLiteral, StringLiteral:
+		constant = true
+
+	case OperatorPhrase:
+		for _, operand := range argument.(OperatorPhrase).arguments {
+			constant, err = checkConstant(operand, evaluating)
+			if err != nil || !constant { return }
+		}
+
+	case CastPhrase:
+		phrase := argument.(CastPhrase)
+		constant, err = checkConstant(phrase.value, evaluating)
+		if err != nil { return }
+		constant =
+			constant &&
+			phrase.what.isSingular() &&
+			(phrase.what.isNumeric() || phrase.what.isBoolean())
+
+	case DataReference:
+		section := argument.(DataReference).section
+		if evaluating[section] {
+			err = argument.NewError (
+				"data section refers to itself",
+				infoerr.ErrorKindError)
+			return
+		}
+		if section.what.mutable || section.argument == nil { return }
+
+		evaluating[section] = true
+		constant, err = checkConstant(section.argument, evaluating)
+		delete(evaluating, section)
+
+	case EnumMemberReference:
+		reference := argument.(EnumMemberReference)
+		constant = reference.member.argument != nil
+	}
+	return
+}
+
+// fold evaluates an argument at compile time if it is constant, and returns the
+// resulting literal. If it is not constant, it is returned unchanged.
+func (analyzer *analysisOperation) fold (
+	argument Argument,
+) (
+	folded Argument,
+	err    error,
+) {
+	constant, err := checkConstant(argument, make(map[*DataSection] bool))
+	if err != nil { return }
+	if !constant {
+		folded = argument
+		return
+	}
+	folded, err = argument.Resolve()
+	return
+}
+
+// resolveConstant evaluates an argument at compile time into a constant.
+func resolveConstant (argument Argument) (value constantValue, err error) {
+	var literal Argument
+	literal, err = argument.Resolve()
+	if err != nil { return }
+
+	switch literal.(type) {
+	case IntLiteral:
+		value.integer = big.NewInt(literal.(IntLiteral).value)
+	case UIntLiteral:
+		value.integer = new(big.Int).SetUint64 (
+			literal.(UIntLiteral).value)
+	case FloatLiteral:
+		value.kind  = constantKindFloat
+		value.float = literal.(FloatLiteral).value
+	case BoolLiteral:
+		value.kind    = constantKindBool
+		value.boolean = literal.(BoolLiteral).value
+	default:
+		err = argument.NewError (
+			"this cannot be used in a constant expression",
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// toFloat returns the constant as a floating point number.
+func (value constantValue) toFloat () (float float64) {
+	if value.kind == constantKindFloat {
+		float = value.float
+	} else {
+		float, _ = new(big.Float).SetInt(value.integer).Float64()
+	}
+	return
+}
+
+// literal converts the constant into a literal at the specified location.
+func (value constantValue) literal (
+	location file.Location,
+) (
+	literal Argument,
+	err     error,
+) {
+	where := locatable { location: location }
+	switch value.kind {
+	case constantKindFloat:
+		literal = FloatLiteral { locatable: where, value: value.float }
+
+	case constantKindBool:
+		literal = BoolLiteral { locatable: where, value: value.boolean }
+
+	case constantKindInteger:
+		switch {
+		case value.integer.IsInt64() && value.integer.Sign() < 0:
+			literal = IntLiteral {
+				locatable: where,
+				value:     value.integer.Int64(),
+			}
+		case value.integer.IsUint64():
+			literal = UIntLiteral {
+				locatable: where,
+				value:     value.integer.Uint64(),
+			}
+		default:
+			err = where.NewError (
+				fmt.Sprint (
+					"constant ", value.integer, " is too ",
+					"large to be stored in any integer ",
+					"type"),
+				infoerr.ErrorKindError)
+		}
+	}
+	return
+}
+
+// relocate returns a copy of a literal that is placed at the specified
+// location. This is used when a constant is pulled in from somewhere else, so
+// that errors concerning it point to where it is being used.
+func relocate (literal Argument, location file.Location) (moved Argument) {
+	where := locatable { location: location }
+	switch literal.(type) {
+	case IntLiteral:
+		moved = IntLiteral { where, literal.(IntLiteral).value }
+	case UIntLiteral:
+		moved = UIntLiteral { where, literal.(UIntLiteral).value }
+	case FloatLiteral:
+		moved = FloatLiteral { where, literal.(FloatLiteral).value }
+	case BoolLiteral:
+		moved = BoolLiteral { where, literal.(BoolLiteral).value }
+	case StringLiteral:
+		moved = StringLiteral { where, literal.(StringLiteral).value }
+	default:
+		moved = literal
+	}
+	return
+}
+
+// checkOverflow makes sure that an integer constant fits within the type of
+// the phrase that produced it, which is width bits wide. If width is zero, the
+// phrase is untyped and the check is left to whatever the result is passed to.
+func checkOverflow (
+	value constantValue,
+	what  Type,
+	width uint64,
+	where locatable,
+) (
+	err error,
+) {
+	if width == 0 || value.kind != constantKindInteger { return }
+
+	minimum, maximum := what.integerRange(width)
+	if value.integer.Cmp(minimum) >= 0 && value.integer.Cmp(maximum) <= 0 {
+		return
+	}
+
+	err = where.NewError (
+		fmt.Sprint (
+			"constant overflow: result ", value.integer,
+			" does not fit in ", what.Describe(), ", which can ",
+			"only hold values from ", minimum, " to ", maximum),
+		infoerr.ErrorKindError)
+	return
+}
+
+// evaluateOperator performs an operation on a list of constants.
+func evaluateOperator (
+	operator lexer.TokenKind,
+	operands []constantValue,
+	what     Type,
+	width    uint64,
+	where    locatable,
+) (
+	result constantValue,
+	err    error,
+) {
+	info := operators[operator]
+	switch info.kind {
+	case operatorKindLogical:
+		result.kind    = constantKindBool
+		result.boolean = operands[0].boolean
+		for _, operand := range operands[1:] {
+			switch operator {
+			case lexer.TokenKindLogicalAnd:
+				result.boolean =
+					result.boolean && operand.boolean
+			case lexer.TokenKindLogicalOr:
+				result.boolean =
+					result.boolean || operand.boolean
+			}
+		}
+		if operator == lexer.TokenKindExclamation {
+			result.boolean = !result.boolean
+		}
+		return
+
+	case operatorKindEquality, operatorKindOrdering:
+		result.kind    = constantKindBool
+		result.boolean = compareConstants (
+			operator,
+			operands[0],
+			operands[1])
+		return
+	}
+
+	// arithmetic is done on floats if any of the operands is a float, or
+	// if the result is supposed to be one
+	isFloat := what.isFloat()
+	for _, operand := range operands {
+		if operand.kind == constantKindFloat { isFloat = true }
+	}
+
+	if isFloat {
+		result, err = evaluateFloat(operator, operands, where)
+	} else {
+		result, err = evaluateInteger (
+			operator,
+			operands,
+			what,
+			width,
+			where)
+	}
+	return
+}
+
+// evaluateFloat performs an arithmetic operation on floating point constants.
+func evaluateFloat (
+	operator lexer.TokenKind,
+	operands []constantValue,
+	where    locatable,
+) (
+	result constantValue,
+	err    error,
+) {
+	result.kind  = constantKindFloat
+	result.float = operands[0].toFloat()
+	if operator == lexer.TokenKindMinus && len(operands) == 1 {
+		result.float = -result.float
+		return
+	}
+
+	for _, operand := range operands[1:] {
+		value := operand.toFloat()
+		switch operator {
+		case lexer.TokenKindPlus:
+			result.float += value
+		case lexer.TokenKindMinus:
+			result.float -= value
+		case lexer.TokenKindAsterisk:
+			result.float *= value
+		case lexer.TokenKindSlash:
+			if value == 0 {
+				err = where.NewError (
+					"division by zero",
+					infoerr.ErrorKindError)
+				return
+			}
+			result.float /= value
+		}
+	}
+
+	if math.IsInf(result.float, 0) {
+		err = where.NewError (
+			"constant overflow: result is too large to be " +
+			"represented by a floating point number",
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// evaluateInteger performs an arithmetic, bitwise, or shift operation on
+// integer constants. If width is non-zero, each step of the operation is
+// checked for overflow.
+func evaluateInteger (
+	operator lexer.TokenKind,
+	operands []constantValue,
+	what     Type,
+	width    uint64,
+	where    locatable,
+) (
+	result constantValue,
+	err    error,
+) {
+	result.integer = new(big.Int).Set(operands[0].integer)
+
+	if len(operands) == 1 {
+		switch operator {
+		case lexer.TokenKindMinus:
+			result.integer.Neg(result.integer)
+		case lexer.TokenKindTilde:
+			if width > 0 && !what.isSignedNumeric() {
+				_, maximum := what.integerRange(width)
+				result.integer.Xor(result.integer, maximum)
+			} else {
+				result.integer.Not(result.integer)
+			}
+		}
+		err = checkOverflow(result, what, width, where)
+		return
+	}
+
+	for _, operand := range operands[1:] {
+		value := operand.integer
+		switch operator {
+		case lexer.TokenKindPlus:
+			result.integer.Add(result.integer, value)
+		case lexer.TokenKindMinus:
+			result.integer.Sub(result.integer, value)
+		case lexer.TokenKindAsterisk:
+			result.integer.Mul(result.integer, value)
+		case lexer.TokenKindSlash, lexer.TokenKindPercent:
+			if value.Sign() == 0 {
+				err = where.NewError (
+					"division by zero",
+					infoerr.ErrorKindError)
+				return
+			}
+			if operator == lexer.TokenKindSlash {
+				result.integer.Quo(result.integer, value)
+			} else {
+				result.integer.Rem(result.integer, value)
+			}
+		case lexer.TokenKindBinaryOr:
+			result.integer.Or(result.integer, value)
+		case lexer.TokenKindBinaryAnd:
+			result.integer.And(result.integer, value)
+		case lexer.TokenKindBinaryXor:
+			result.integer.Xor(result.integer, value)
+		case lexer.TokenKindLShift, lexer.TokenKindRShift:
+			if !value.IsUint64() || value.Uint64() > 1024 {
+				err = where.NewError (
+					fmt.Sprint("cannot shift by ", value),
+					infoerr.ErrorKindError)
+				return
+			}
+			amount := uint(value.Uint64())
+			if operator == lexer.TokenKindLShift {
+				result.integer.Lsh(result.integer, amount)
+			} else {
+				result.integer.Rsh(result.integer, amount)
+			}
+		}
+
+		err = checkOverflow(result, what, width, where)
+		if err != nil { return }
+	}
+	return
+}
+
+// compareConstants compares two constants using an equality or ordering
+// operator.
+func compareConstants (
+	operator    lexer.TokenKind,
+	left, right constantValue,
+) (
+	result bool,
+) {
+	var comparison int
+	switch {
+	case left.kind == constantKindBool:
+		if left.boolean != right.boolean { comparison = 1 }
+	case left.kind == constantKindFloat || right.kind == constantKindFloat:
+		leftFloat  := left.toFloat()
+		rightFloat := right.toFloat()
+		switch {
+		case leftFloat < rightFloat:
+			comparison = -1
+		case leftFloat > rightFloat:
+			comparison = 1
+		}
+	default:
+		comparison = left.integer.Cmp(right.integer)
+	}
+
+	switch operator {
+	case lexer.TokenKindEqualTo:
+		result = comparison == 0
+	case lexer.TokenKindNotEqualTo:
+		result = comparison != 0
+	case lexer.TokenKindLessThan:
+		result = comparison < 0
+	case lexer.TokenKindLessThanEqualTo:
+		result = comparison <= 0
+	case lexer.TokenKindGreaterThan:
+		result = comparison > 0
+	case lexer.TokenKindGreaterThanEqualTo:
+		result = comparison >= 0
+	}
+	return
+}
+
+// convertConstant converts a constant to the specified type, the way a cast
+// would at runtime. Integers that do not fit are wrapped around, and floats are
+// truncated towards zero when they are converted to integers.
+func convertConstant (
+	value constantValue,
+	what  Type,
+	width uint64,
+	where locatable,
+) (
+	result constantValue,
+	err    error,
+) {
+	switch {
+	case what.isBoolean():
+		result.kind = constantKindBool
+		switch value.kind {
+		case constantKindBool:
+			result.boolean = value.boolean
+		case constantKindFloat:
+			result.boolean = value.float != 0
+		case constantKindInteger:
+			result.boolean = value.integer.Sign() != 0
+		}
+
+	case what.isFloat():
+		result.kind  = constantKindFloat
+		result.float = value.toFloat()
+		if value.kind == constantKindBool && value.boolean {
+			result.float = 1
+		}
+		if what.underlyingPrimitive() == &PrimitiveF32 {
+			result.float = float64(float32(result.float))
+		}
+
+	default:
+		switch value.kind {
+		case constantKindBool:
+			result.integer = big.NewInt(0)
+			if value.boolean { result.integer.SetInt64(1) }
+		case constantKindFloat:
+			invalid :=
+				math.IsNaN(value.float) ||
+				math.IsInf(value.float, 0)
+			if invalid {
+				err = where.NewError (
+					fmt.Sprint (
+						"cannot convert ", value.float,
+						" to ", what.Describe()),
+					infoerr.ErrorKindError)
+				return
+			}
+			result.integer, _ = big.NewFloat (
+				math.Trunc(value.float)).Int(nil)
+		default:
+			result.integer = new(big.Int).Set(value.integer)
+		}
+		result.integer = wrapInteger(result.integer, what, width)
+	}
+	return
+}
+
+// wrapInteger wraps an integer around so that it fits within an integer type
+// that is width bits wide, the same way it would if it were stored in memory.
+func wrapInteger (value *big.Int, what Type, width uint64) (wrapped *big.Int) {
+	modulus := new(big.Int).Lsh(big.NewInt(1), uint(width))
+	wrapped  = new(big.Int).Mod(value, modulus)
+
+	_, maximum := what.integerRange(width)
+	if wrapped.Cmp(maximum) > 0 {
+		wrapped.Sub(wrapped, modulus)
+	}
+	return
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestConstants (test *testing.T) {
+	checkTree ("../tests/analyzer/constants", false,
+`dataSection ro ../tests/analyzer/constants.aSum
+	type 1 basic Int
+	uintLiteral 6
+dataSection ro ../tests/analyzer/constants.bShift
+	type 1 basic U8
+	uintLiteral 128
+dataSection ro ../tests/analyzer/constants.cDerived
+	type 1 basic Int
+	uintLiteral 12
+dataSection ro ../tests/analyzer/constants.dWrapped
+	type 1 basic U8
+	uintLiteral 44
+dataSection ro ../tests/analyzer/constants.eSigned
+	type 1 basic I8
+	intLiteral -1
+dataSection ro ../tests/analyzer/constants.fTruncated
+	type 1 basic Int
+	uintLiteral 3
+dataSection ro ../tests/analyzer/constants.gQuarter
+	type 1 basic F64
+	floatLiteral 0.25
+dataSection ro ../tests/analyzer/constants.hCompare
+	type 1 basic Bool
+	boolLiteral true
+enumSection ro ../tests/analyzer/constants.iFlags
+	uintLiteral 1
+	type 1 basic U8
+	member first
+		uintLiteral 1
+	member second
+		uintLiteral 2
+	member both
+		uintLiteral 3
+dataSection ro ../tests/analyzer/constants.jBoth
+	type 1 basic U8
+	uintLiteral 3
+typeSection ro ../tests/analyzer/constants.kObject
+	type 1 basic Obj
+	member ro area
+		type 1 basic Int
+		uintLiteral 144
+`, test)
+}
+
+func TestConstantOverflow (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/constantOverflow",
+		infoerr.ErrorKindError,
+		"constant overflow: result 200 does not fit in I8, which " +
+		"can only hold values from -128 to 127",
+		5, 19, test)
+}
+
+func TestDivisionByZero (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/divisionByZero",
+		infoerr.ErrorKindError,
+		"division by zero",
+		3, 19, test)
+}
+
+func TestEnumNotConstant (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumNotConstant",
+		infoerr.ErrorKindError,
+		"enum member value must be known at compile time",
+		6, 7, test)
+}
+
+func TestConstantCycle (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/dataCycle",
+		infoerr.ErrorKindError,
+		"data section refers to itself",
+		3, 17, test)
+}
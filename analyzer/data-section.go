@@ -24,6 +24,25 @@ func (section DataSection) ToString (indent int) (output string) {
 	return
 }
 
+// What returns the type of the data section.
+func (section DataSection) What () (what Type) {
+	what = section.what
+	return
+}
+
+// Argument returns the initial value of the data section. If it does not have
+// one, this is nil.
+func (section DataSection) Argument () (argument Argument) {
+	argument = section.argument
+	return
+}
+
+// External returns whether the data section is defined outside of ARF.
+func (section DataSection) External () (external bool) {
+	external = section.external
+	return
+}
+
 // analyzeDataSection analyzes a data section.
 func (analyzer analysisOperation) analyzeDataSection () (
 	section Section,
@@ -46,8 +65,12 @@ func (analyzer analysisOperation) analyzeDataSection () (
 	// data sections are only allowed to inherit type, enum, and face sections
 	_, inheritsFromTypeSection := outputSection.what.actual.(*TypeSection)
 	_, inheritsFromEnumSection := outputSection.what.actual.(*EnumSection)
-	// _, inheritsFromFaceSection := outputSection.what.actual.(*FaceSection)
-	if !inheritsFromTypeSection && inheritsFromEnumSection {
+	_, inheritsFromFaceSection := outputSection.what.actual.(*FaceSection)
+	inheritsFromValid :=
+		inheritsFromTypeSection ||
+		inheritsFromEnumSection ||
+		inheritsFromFaceSection
+	if outputSection.what.kind == TypeKindBasic && !inheritsFromValid {
 		err = inputSection.Type().NewError (
 			"type sections can only inherit from type, enum, and " +
 			"face sections",
@@ -63,6 +86,11 @@ func (analyzer analysisOperation) analyzeDataSection () (
 		err = analyzer.analyzeArgument(inputSection.Argument())
 		if err != nil { return }
 
+		// evaluate the value ahead of time if possible
+		outputSection.argument,
+		err = analyzer.fold(outputSection.argument)
+		if err != nil { return }
+
 		// type check default value
 		err = analyzer.typeCheck (
 			outputSection.argument,
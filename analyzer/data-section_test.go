@@ -1,6 +1,8 @@
 package analyzer
 
 import "testing"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/infoerr"
 
 func TestDataSection (test *testing.T) {
 	checkTree ("../tests/analyzer/dataSection", false,
@@ -16,5 +18,65 @@ dataSection ro ../tests/analyzer/dataSection.cString
 dataSection ro ../tests/analyzer/dataSection.dCharBuffer
 	type 32 basic U8
 	stringLiteral 'A very large bird` + "\000" + `'
+dataSection ro ../tests/analyzer/dataSection.eFloat
+	type 1 basic F32
+	floatLiteral 0.5
+dataSection ro ../tests/analyzer/dataSection.fDouble
+	type 1 basic F64
+	floatLiteral -3.25
+dataSection ro ../tests/analyzer/dataSection.gWholeFloat
+	type 1 basic F64
+	uintLiteral 7
+dataSection ro ../tests/analyzer/dataSection.hNegativeFloat
+	type 1 basic F32
+	intLiteral -2
+dataSection ro ../tests/analyzer/dataSection.iRounded
+	type 1 basic F32
+	floatLiteral 0.1
 `, test)
 }
+
+func TestFloatToInt (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/floatToInt",
+		infoerr.ErrorKindError,
+		"F64 cannot be used as Int",
+		3, 19, test)
+}
+
+func TestLiteralRange (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/literalRange",
+		infoerr.ErrorKindError,
+		"literal 100000 is out of range for I8, which can only hold " +
+		"values from -128 to 127",
+		3, 18, test)
+}
+
+func TestNegativeUnsigned (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/negativeUnsigned",
+		infoerr.ErrorKindError,
+		"literal -1 is out of range for U8, which can only hold " +
+		"values from 0 to 255",
+		3, 17, test)
+}
+
+func TestWordRange (test *testing.T) {
+	checkErrorOn (
+		target.I386,
+		"../tests/analyzer/error/wordRange",
+		infoerr.ErrorKindError,
+		"literal 3000000000 is out of range for Int, which can only " +
+		"hold values from -2147483648 to 2147483647",
+		3, 19, test)
+}
+
+func TestPrecisionLoss (test *testing.T) {
+	checkWarnings("../tests/analyzer/precision", []string {
+		"3:21 literal cannot be represented exactly by F32, and " +
+		"will be rounded to 0.10000000149011612",
+		"10:10 literal cannot be represented exactly by F64, and " +
+		"will be rounded to 9.007199254740992e+15",
+	}, test)
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// DeferPhrase represents a phrase that runs the block under it right before
+// the function returns, instead of where it is. If there are several, they are
+// run in reverse order.
+type DeferPhrase struct {
+	phraseBase
+	block Block
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase DeferPhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "deferPhrase\n")
+	output += phrase.block.ToString(indent + 1)
+	return
+}
+
+// Block returns the block that is run when the function returns.
+func (phrase DeferPhrase) Block () (block Block) {
+	block = phrase.block
+	return
+}
+
+// analyzeDeferPhrase analyzes a phrase of the form [defer].
+func (analyzer *analysisOperation) analyzeDeferPhrase (
+	inputPhrase parser.Phrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := DeferPhrase { }
+	outputPhrase.location = inputPhrase.Location()
+
+	if inputPhrase.Length() > 0 {
+		err = outputPhrase.NewError (
+			"defer does not take any arguments",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase.block, err = analyzer.analyzeBlock(inputPhrase.Block())
+	if err != nil { return }
+
+	phrase = outputPhrase
+	return
+}
@@ -0,0 +1,124 @@
+package analyzer
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// Dereference represents a pointer dereference or array subscript.
+type Dereference struct {
+	locatable
+	argument Argument
+	offset   uint64
+	what     Type
+}
+
+// ToString outputs the data in the argument as a string.
+func (dereference Dereference) ToString (indent int) (output string) {
+	output += doIndent(indent, "dereference ", dereference.offset, "\n")
+	output += dereference.what.ToString(indent + 1)
+	output += dereference.argument.ToString(indent + 1)
+	return
+}
+
+// What returns the type of the value being pointed to.
+func (dereference Dereference) What () (what Type) {
+	what = dereference.what
+	return
+}
+
+// Operand returns the pointer or array being dereferenced.
+func (dereference Dereference) Operand () (operand Argument) {
+	operand = dereference.argument
+	return
+}
+
+// Offset returns the index of the element being selected.
+func (dereference Dereference) Offset () (offset uint64) {
+	offset = dereference.offset
+	return
+}
+
+// Equals returns whether the argument is equal to the specified value. This
+// is always false, because dereferences are not constant.
+func (dereference Dereference) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because dereferences are not constant.
+func (dereference Dereference) Value () (value any) {
+	return
+}
+
+// Resolve returns an error, because dereferences are not constant.
+func (dereference Dereference) Resolve () (constant Argument, err error) {
+	err = dereference.NewError (
+		"dereference cannot be evaluated at compile time",
+		infoerr.ErrorKindError)
+	return
+}
+
+// canBePassedAs returns true if the type of the value being pointed to can be
+// passed to the specified type.
+func (dereference Dereference) canBePassedAs (what Type) (allowed bool) {
+	allowed = dereference.What().canBePassedAs(what)
+	return
+}
+
+// writable returns whether the value being pointed to can be modified. Elements
+// of fixed length arrays are only writable if the array is, and everything
+// else is only writable if the pointer points to mutable data.
+func (dereference Dereference) writable () (writable bool) {
+	if dereference.argument.What().length > 1 {
+		writable = isWritable(dereference.argument)
+	} else {
+		writable = dereference.what.mutable
+	}
+	return
+}
+
+// analyzeDereference analyzes a pointer dereference or array subscript.
+func (analyzer *analysisOperation) analyzeDereference (
+	inputDereference parser.Dereference,
+) (
+	outputDereference Dereference,
+	err error,
+) {
+	outputDereference.location = inputDereference.Location()
+	outputDereference.offset   = inputDereference.Offset()
+	outputDereference.argument,
+	err = analyzer.analyzeArgument(inputDereference.Argument())
+	if err != nil { return }
+
+	what := outputDereference.argument.What()
+	if what.length > 1 {
+		// this is a fixed length array
+		if outputDereference.offset >= what.length {
+			err = outputDereference.NewError (
+				fmt.Sprint (
+					"offset ", outputDereference.offset,
+					" is out of bounds for ",
+					what.Describe()),
+				infoerr.ErrorKindError)
+			return
+		}
+
+		outputDereference.what = what
+		outputDereference.what.length = 1
+		return
+	}
+
+	reduced, _ := what.reduce()
+	isPointer :=
+		reduced.kind == TypeKindPointer ||
+		reduced.kind == TypeKindVariableArray
+	if !isPointer || reduced.points == nil {
+		err = outputDereference.NewError (
+			"cannot dereference " + what.Describe() + ", which " +
+			"is not a pointer or array",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputDereference.what = *reduced.points
+	return
+}
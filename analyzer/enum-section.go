@@ -1,5 +1,6 @@
 package analyzer
 
+import "math/big"
 import "git.tebibyte.media/arf/arf/types"
 import "git.tebibyte.media/arf/arf/parser"
 import "git.tebibyte.media/arf/arf/infoerr"
@@ -29,6 +30,19 @@ func (member EnumMember) ToString (indent int) (output string) {
 	return
 }
 
+// Name returns the name of the member.
+func (member EnumMember) Name () (name string) {
+	name = member.name
+	return
+}
+
+// Value returns the value of the member as a constant literal. If the value was
+// not specified, it will have been filled in automatically.
+func (member EnumMember) Value () (value Argument) {
+	value = member.argument
+	return
+}
+
 // ToString returns all data stored within the type section, in string form.
 func (section EnumSection) ToString (indent int) (output string) {
 	output += doIndent(indent, "enumSection ")
@@ -45,6 +59,102 @@ func (section EnumSection) ToString (indent int) (output string) {
 	return
 }
 
+// What returns the type that the enum is based on.
+func (section EnumSection) What () (what Type) {
+	what = section.what
+	return
+}
+
+// Argument returns the default value of the enum, which is the value of its
+// first member.
+func (section EnumSection) Argument () (argument Argument) {
+	argument = section.argument
+	return
+}
+
+// Member returns the member with the specified name.
+func (section EnumSection) Member (
+	name string,
+) (
+	member EnumMember,
+	exists bool,
+) {
+	for _, currentMember := range section.members {
+		if currentMember.name == name {
+			member = currentMember
+			exists = true
+			return
+		}
+	}
+	return
+}
+
+// EnumMemberReference is an argument that refers to a member of an enum, such
+// as aWeekday.monday.
+type EnumMemberReference struct {
+	locatable
+	section *EnumSection
+	member  EnumMember
+}
+
+// ToString outputs the data in the argument as a string.
+func (reference EnumMemberReference) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "enumMember ",
+		reference.section.where.ToString(), ".",
+		reference.member.name, "\n")
+	return
+}
+
+// What returns the type of the argument, which is the enum itself.
+func (reference EnumMemberReference) What () (what Type) {
+	what = Type { actual: reference.section, length: 1 }
+	return
+}
+
+// Member returns the member being referred to.
+func (reference EnumMemberReference) Member () (member EnumMember) {
+	member = reference.member
+	return
+}
+
+// Equals returns whether the value of the member is equal to the specified
+// value.
+func (reference EnumMemberReference) Equals (value any) (equal bool) {
+	if reference.member.argument == nil { return }
+	equal = reference.member.argument.Equals(value)
+	return
+}
+
+// Value returns the value of the member.
+func (reference EnumMemberReference) Value () (value any) {
+	if reference.member.argument == nil { return }
+	value = reference.member.argument.Value()
+	return
+}
+
+// Resolve returns the value of the member as a literal.
+func (reference EnumMemberReference) Resolve () (constant Argument, err error) {
+	if reference.member.argument == nil {
+		err = reference.NewError (
+			"value of enum member " + reference.member.name +
+			" is not known at compile time",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	constant, err = reference.member.argument.Resolve()
+	if err != nil { return }
+	constant = relocate(constant, reference.location)
+	return
+}
+
+// canBePassedAs returns true if the enum can be passed to the specified type.
+func (reference EnumMemberReference) canBePassedAs (what Type) (allowed bool) {
+	allowed = reference.What().canBePassedAs(what)
+	return
+}
+
 // analyzeEnumSection analyzes an enumerated type section.
 func (analyzer analysisOperation) analyzeEnumSection () (
 	section Section,
@@ -104,16 +214,24 @@ func (analyzer analysisOperation) analyzeEnumSection () (
 
 			// attempt to resolve the argument to a single constant
 			// literal
+			if !isConstant(outputMember.argument) {
+				err = outputMember.argument.NewError (
+					"enum member value must be known at " +
+					"compile time",
+					infoerr.ErrorKindError)
+				return
+			}
 			outputMember.argument, err =
 				outputMember.argument.Resolve()
 			if err != nil { return }
-	
-			// type check value
-			err = analyzer.typeCheck (
-				outputMember.argument,
-				outputSection.what)
+		} else if isNumeric {
+			// fill in the value by counting up from the previous
+			// member, or from zero if this is the first one
+			outputMember.argument, err = nextEnumValue (
+				outputMember,
+				outputSection.members)
 			if err != nil { return }
-		} else if !isNumeric {
+		} else {
 			// non-numeric enums must have filled in values
 			err = inputMember.NewError (
 				"member value must be specified manually for " +
@@ -121,6 +239,12 @@ func (analyzer analysisOperation) analyzeEnumSection () (
 				infoerr.ErrorKindError)
 			return
 		}
+	
+		// type check value
+		err = analyzer.typeCheck (
+			outputMember.argument,
+			outputSection.what)
+		if err != nil { return }
 
 		for _, compareMember := range outputSection.members {
 			if compareMember.name == outputMember.name {
@@ -130,9 +254,6 @@ func (analyzer analysisOperation) analyzeEnumSection () (
 				return
 			}
 
-			if outputMember.argument  == nil { continue }
-			if compareMember.argument == nil { continue }
-
 			if compareMember.argument.Equals (
 				outputMember.argument.Value(),
 			) {
@@ -148,56 +269,6 @@ func (analyzer analysisOperation) analyzeEnumSection () (
 			outputMember)
 	}
 
-	// fill in members that do not have values
-	if isNumeric {
-		for index, fillInMember := range outputSection.members {
-			if fillInMember.argument != nil { continue }
-
-			max := uint64(0)
-			for _, compareMember := range outputSection.members {
-			
-				compareValue := compareMember.argument
-				switch compareValue.(type) {
-				case IntLiteral:
-					number := uint64 (
-						compareValue.(IntLiteral).value)
-					if number > max {
-						max = number
-					}
-				case UIntLiteral:
-					number := uint64 (
-						compareValue.(UIntLiteral).value)
-					if number > max {
-						max = number
-					}
-				case FloatLiteral:
-					number := uint64 (
-						compareValue.(FloatLiteral).value)
-					if number > max {
-						max = number
-					}
-				case nil:
-					// do nothing
-				default:
-					panic (
-						"invalid state: illegal " +
-						"argument type while " +
-						"attempting to fill in enum " +
-						"member value for " +
-						fillInMember.name + " in " +
-						outputSection.location.Describe())
-				}
-			}
-			
-			// fill in
-			fillInMember.argument = UIntLiteral {
-				locatable: fillInMember.locatable,
-				value: max + 1,
-			}
-			outputSection.members[index] = fillInMember
-		}
-	}
-
 	if len(outputSection.members) < 1 {
 		err = outputSection.NewError (
 			"cannot create an enum with no members",
@@ -210,3 +281,31 @@ func (analyzer analysisOperation) analyzeEnumSection () (
 	outputSection.complete = true
 	return
 }
+
+// nextEnumValue returns the value that a numeric enum member without a value
+// of its own should be given. This is one more than the value of the previous
+// member, or zero if there is no previous member.
+func nextEnumValue (
+	member   EnumMember,
+	previous []EnumMember,
+) (
+	value Argument,
+	err   error,
+) {
+	if len(previous) == 0 {
+		value = UIntLiteral { locatable: member.locatable }
+		return
+	}
+
+	last, err := resolveConstant(previous[len(previous) - 1].argument)
+	if err != nil { return }
+
+	if last.kind == constantKindFloat {
+		last.float ++
+	} else {
+		last.integer.Add(last.integer, big.NewInt(1))
+	}
+
+	value, err = last.literal(member.location)
+	return
+}
@@ -1,26 +1,27 @@
 package analyzer
 
 import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
 
 func TestEnumSection (test *testing.T) {
 	checkTree ("../tests/analyzer/enumSection", false,
 `enumSection ro ../tests/analyzer/enumSection.aWeekday
-	uintLiteral 1
+	uintLiteral 0
 	type 1 basic Int
 	member sunday
-		uintLiteral 1
+		uintLiteral 0
 	member monday
-		uintLiteral 2
+		uintLiteral 1
 	member tuesday
-		uintLiteral 3
+		uintLiteral 2
 	member wednesday
-		uintLiteral 4
+		uintLiteral 3
 	member thursday
-		uintLiteral 5
+		uintLiteral 4
 	member friday
-		uintLiteral 6
+		uintLiteral 5
 	member saturday
-		uintLiteral 7
+		uintLiteral 6
 typeSection ro ../tests/analyzer/enumSection.bColor
 	type 1 basic U32
 enumSection ro ../tests/analyzer/enumSection.cNamedColor
@@ -33,15 +34,69 @@ enumSection ro ../tests/analyzer/enumSection.cNamedColor
 	member blue
 		uintLiteral 255
 enumSection ro ../tests/analyzer/enumSection.dFromFarAway
-	uintLiteral 5
+	uintLiteral 0
 	type 1 basic dInheritFromOther
 	member bird
-		uintLiteral 5
+		uintLiteral 0
 	member bread
 		uintLiteral 4
+enumSection ro ../tests/analyzer/enumSection.eCounting
+	intLiteral -2
+	type 1 basic I8
+	member lowest
+		intLiteral -2
+	member lower
+		intLiteral -1
+	member middle
+		uintLiteral 5
+	member higher
+		uintLiteral 6
 typeSection ro ../tests/analyzer/typeSection/required.aBasic
 	type 1 basic Int
 typeSection ro ../tests/analyzer/typeSection.dInheritFromOther
 	type 1 basic aBasic
 `, test)
 }
+
+func TestEnumRange (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumRange",
+		infoerr.ErrorKindError,
+		"literal 256 is out of range for U8, which can only hold " +
+		"values from 0 to 255",
+		5, 8, test)
+}
+
+func TestEnumDuplicateValue (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumDuplicateValue",
+		infoerr.ErrorKindError,
+		"enum member values must be unique",
+		6, 3, test)
+}
+
+func TestEnumDuplicateName (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumDuplicateName",
+		infoerr.ErrorKindError,
+		"enum member names must be unique",
+		5, 3, test)
+}
+
+func TestEnumNonNumeric (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumNonNumeric",
+		infoerr.ErrorKindError,
+		"member value must be specified manually for non-numeric " +
+		"enums",
+		5, 3, test)
+}
+
+func TestEnumAutoRange (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/enumAutoRange",
+		infoerr.ErrorKindError,
+		"literal 256 is out of range for U8, which can only hold " +
+		"values from 0 to 255",
+		5, 3, test)
+}
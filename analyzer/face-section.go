@@ -0,0 +1,238 @@
+package analyzer
+
+import "sort"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// FaceKind determines if an interface is an object interface or a function
+// interface.
+type FaceKind int
+
+const (
+	// FaceKindType means the interface describes a set of behaviors that
+	// an object must have.
+	FaceKindType FaceKind = iota
+
+	// FaceKindFunc means the interface describes the inputs and outputs of
+	// a function.
+	FaceKindFunc
+)
+
+// FaceSection represents an interface type section.
+type FaceSection struct {
+	sectionBase
+	what      Type
+	kind      FaceKind
+	behaviors map[string] FaceBehavior
+
+	// only applicable for function interfaces
+	signature FaceBehavior
+}
+
+// FaceBehavior represents a behavior of an interface section. Function
+// interfaces have a single unnamed behavior, which is their signature.
+type FaceBehavior struct {
+	locatable
+	name    string
+	inputs  []*Variable
+	outputs []*Variable
+}
+
+// ToString returns all data stored within the behavior, in string form.
+func (behavior FaceBehavior) ToString (indent int) (output string) {
+	output += doIndent(indent, "behavior ", behavior.name, "\n")
+	output += behavior.argumentsToString(indent + 1)
+	return
+}
+
+// argumentsToString returns the inputs and outputs of the behavior, in string
+// form.
+func (behavior FaceBehavior) argumentsToString (indent int) (output string) {
+	for _, input := range behavior.inputs {
+		output += doIndent(indent, "input ", input.name, "\n")
+		output += input.what.ToString(indent + 1)
+	}
+
+	for _, funcOutput := range behavior.outputs {
+		output += doIndent(indent, "output ", funcOutput.name, "\n")
+		output += funcOutput.what.ToString(indent + 1)
+	}
+	return
+}
+
+// ToString returns all data stored within the interface section, in string
+// form.
+func (section FaceSection) ToString (indent int) (output string) {
+	output += doIndent(indent, "faceSection ")
+	output += section.permission.ToString() + " "
+	output += section.where.ToString()
+	output += "\n"
+	output += section.what.ToString(indent + 1)
+
+	if section.kind == FaceKindType {
+		names := make([]string, 0, len(section.behaviors))
+		for name := range section.behaviors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			output += section.behaviors[name].ToString(indent + 1)
+		}
+	} else {
+		output += section.signature.argumentsToString(indent + 1)
+	}
+	return
+}
+
+// Behavior returns the behavior with the specified name. If the interface does
+// not have it, the interface it inherits from is searched.
+func (section FaceSection) Behavior (
+	name string,
+) (
+	behavior FaceBehavior,
+	exists bool,
+) {
+	behavior, exists = section.behaviors[name]
+	if exists { return }
+
+	actual, isFaceSection := section.what.actual.(*FaceSection)
+	if !isFaceSection { return }
+	behavior, exists = actual.Behavior(name)
+	return
+}
+
+// analyzeFaceSection analyzes an interface section.
+func (analyzer analysisOperation) analyzeFaceSection () (
+	section Section,
+	err error,
+) {
+	outputSection := FaceSection { }
+	outputSection.where = analyzer.currentPosition
+
+	section = &outputSection
+	analyzer.addSection(section)
+
+	inputSection := analyzer.currentSection.(parser.FaceSection)
+	outputSection.location = analyzer.currentSection.Location()
+
+	if inputSection.Permission() == types.PermissionReadWrite {
+		err = inputSection.NewError (
+			"read-write (rw) permission not understood in this " +
+			"context, try read-only (ro)",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputSection.permission = inputSection.Permission()
+
+	// get inherited interface
+	var node any
+	var bitten parser.Identifier
+	node, bitten, err = analyzer.fetchNodeFromIdentifier (
+		inputSection.Inherits())
+	if err != nil { return }
+
+	inherits, isFaceSection := node.(*FaceSection)
+	if bitten.Length() > 0 || !isFaceSection {
+		err = inputSection.Inherits().NewError (
+			"interfaces can only inherit from other interfaces",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputSection.what = Type { actual: inherits, length: 1 }
+
+	// interfaces without any behaviors or arguments take on the kind of
+	// the interface they inherit from
+	outputSection.kind = inherits.kind
+
+	inputKind := inputSection.Kind()
+	isType    := inherits.kind == FaceKindType
+	mismatched :=
+		inputKind == parser.FaceKindType && !isType ||
+		inputKind == parser.FaceKindFunc && isType
+	if mismatched {
+		err = inputSection.NewError (
+			"object interfaces and function interfaces cannot " +
+			"inherit from each other",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	switch inputKind {
+	case parser.FaceKindType:
+		err = analyzer.analyzeFaceBehaviors(&outputSection, inputSection)
+		if err != nil { return }
+
+	case parser.FaceKindFunc:
+		outputSection.signature,
+		err = analyzer.analyzeFaceBehavior(inputSection.FaceBehavior)
+		if err != nil { return }
+	}
+
+	outputSection.complete = true
+	return
+}
+
+// analyzeFaceBehaviors analyzes the behaviors of a parser object interface
+// into a semantic interface.
+func (analyzer *analysisOperation) analyzeFaceBehaviors (
+	into *FaceSection,
+	from parser.FaceSection,
+) (
+	err error,
+) {
+	into.behaviors = make(map[string] FaceBehavior)
+	inherits := into.what.actual.(*FaceSection)
+
+	behaviors := from.Behaviors()
+	for !behaviors.End() {
+		inputBehavior := behaviors.Value()
+		behaviors.Next()
+
+		_, exists := inherits.Behavior(inputBehavior.Name())
+		if exists {
+			err = inputBehavior.NewError (
+				"cannot redefine inherited behavior",
+				infoerr.ErrorKindError)
+			return
+		}
+
+		var outputBehavior FaceBehavior
+		outputBehavior, err = analyzer.analyzeFaceBehavior(inputBehavior)
+		if err != nil { return }
+		into.behaviors[outputBehavior.name] = outputBehavior
+	}
+	return
+}
+
+// analyzeFaceBehavior analyzes a single interface behavior. Its inputs and
+// outputs are not defined in any scope.
+func (analyzer *analysisOperation) analyzeFaceBehavior (
+	inputBehavior parser.FaceBehavior,
+) (
+	outputBehavior FaceBehavior,
+	err error,
+) {
+	outputBehavior.location = inputBehavior.Location()
+	outputBehavior.name     = inputBehavior.Name()
+
+	for index := 0; index < inputBehavior.InputsLength(); index ++ {
+		var input *Variable
+		input, err = analyzer.analyzeDeclaration (
+			inputBehavior.Input(index))
+		if err != nil { return }
+		outputBehavior.inputs = append(outputBehavior.inputs, input)
+	}
+
+	for index := 0; index < inputBehavior.OutputsLength(); index ++ {
+		var output *Variable
+		output, err = analyzer.analyzeDeclaration (
+			inputBehavior.Output(index))
+		if err != nil { return }
+		outputBehavior.outputs = append(outputBehavior.outputs, output)
+	}
+	return
+}
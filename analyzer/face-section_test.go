@@ -0,0 +1,59 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestFaceSection (test *testing.T) {
+	checkTree ("../tests/analyzer/faceSection", false,
+`faceSection ro ../tests/analyzer/faceSection.aReader
+	type 1 basic Face
+	behavior read
+		input into
+			type 1 dynamicArray {
+				type 1 basic U8
+			}
+		output read
+			type 1 basic Int
+faceSection ro ../tests/analyzer/faceSection.bReadWriter
+	type 1 basic aReader
+	behavior write
+		input data
+			type 1 dynamicArray {
+				type 1 basic U8
+			}
+		output wrote
+			type 1 basic Int
+faceSection ro ../tests/analyzer/faceSection.cDestroyer
+	type 1 basic Face
+	behavior destroy
+faceSection ro ../tests/analyzer/faceSection.dCallback
+	type 1 basic Func
+	input something
+		type 1 basic U32
+	output done
+		type 1 basic Bool
+dataSection ro ../tests/analyzer/faceSection.eTrue
+	type 1 basic Bool
+	boolLiteral true
+dataSection ro ../tests/analyzer/faceSection.fFalse
+	type 1 basic Bool
+	boolLiteral false
+`, test)
+}
+
+func TestFaceKindMismatch (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/faceKindMismatch",
+		infoerr.ErrorKindError,
+		"object interfaces and function interfaces cannot inherit " +
+		"from each other",
+		4, 0, test)
+}
+
+func TestFaceRedefine (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/faceRedefine",
+		infoerr.ErrorKindError,
+		"cannot redefine inherited behavior",
+		6, 1, test)
+}
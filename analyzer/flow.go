@@ -0,0 +1,598 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// accessKind determines how a phrase uses a variable.
+type accessKind int
+
+const (
+	// accessRead means that the value of the variable is used.
+	accessRead accessKind = iota
+
+	// accessWrite means that the variable is given an entirely new value.
+	accessWrite
+
+	// accessPartial means that only part of the variable is given a new
+	// value, or that its location is taken and it could be changed through
+	// a pointer. Since there is no way to know what happens to the
+	// variable after this, it is treated as both a read and a write.
+	accessPartial
+)
+
+// access is a single use of a variable by a phrase.
+type access struct {
+	locatable
+	kind     accessKind
+	variable *Variable
+}
+
+// variableSet is a set of variables.
+type variableSet map[*Variable] bool
+
+// copy returns a copy of the set.
+func (set variableSet) copy () (copied variableSet) {
+	copied = make(variableSet, len(set))
+	for variable := range set {
+		copied[variable] = true
+	}
+	return
+}
+
+// intersect returns the variables that are in both sets.
+func (set variableSet) intersect (other variableSet) (result variableSet) {
+	result = make(variableSet)
+	for variable := range set {
+		if other[variable] { result[variable] = true }
+	}
+	return
+}
+
+// union returns the variables that are in either set.
+func (set variableSet) union (other variableSet) (result variableSet) {
+	result = set.copy()
+	for variable := range other {
+		result[variable] = true
+	}
+	return
+}
+
+// equals returns whether both sets contain the same variables.
+func (set variableSet) equals (other variableSet) (equal bool) {
+	if len(set) != len(other) { return }
+	for variable := range set {
+		if !other[variable] { return }
+	}
+	equal = true
+	return
+}
+
+// flowChecker performs flow sensitive checks on the body of a function using
+// its control flow graph. It finds code that can never run, variables that are
+// read before they are given a value, outputs that might not be set by the
+// time the function returns, variables that are never used, and values that
+// are stored in variables but never read.
+type flowChecker struct {
+	function *FuncSection
+	graph    *flowGraph
+
+	// locals contains every variable declared within the function body,
+	// in the order that they are declared.
+	locals []*Variable
+
+	// used contains variables whose values are read somewhere, and written
+	// contains variables that are given a value somewhere. partial contains
+	// variables that have been accessed partially.
+	used    variableSet
+	written variableSet
+	partial variableSet
+
+	warnings []infoerr.Error
+}
+
+// checkFlow performs flow sensitive checks on the body of a function. Reading
+// a variable before it is given a value is an error, and everything else is
+// returned as a list of warnings.
+func checkFlow (function *FuncSection) (warnings []infoerr.Error, err error) {
+	if function.external { return }
+
+	checker := flowChecker {
+		function: function,
+		graph:    buildFlowGraph(function),
+		used:     make(variableSet),
+		written:  make(variableSet),
+		partial:  make(variableSet),
+	}
+	checker.collectAccesses()
+
+	final, err := checker.checkAssignment()
+	if err != nil { return }
+
+	checker.checkReachable()
+	if checker.graph.exit.reachable {
+		checker.checkOutputs(final)
+	}
+	checker.checkUnused()
+	checker.checkDeadStores()
+
+	warnings = checker.warnings
+	return
+}
+
+// warn adds a warning to the list of warnings that the checker has found.
+func (checker *flowChecker) warn (location file.Location, message string) {
+	checker.warnings = append (
+		checker.warnings,
+		infoerr.NewError(location, message, infoerr.ErrorKindWarn))
+}
+
+// collectAccesses goes through every access in the graph, and records which
+// variables are declared in the function body, and how each is used.
+func (checker *flowChecker) collectAccesses () {
+	declared := make(variableSet)
+	if checker.function.receiver != nil {
+		declared[checker.function.receiver] = true
+	}
+	for _, input := range checker.function.inputs {
+		declared[input] = true
+	}
+	for _, output := range checker.function.outputs {
+		declared[&output.Variable] = true
+	}
+
+	for _, node := range checker.graph.nodes {
+		for _, current := range node.accesses {
+			variable := current.variable
+			if !declared[variable] {
+				declared[variable] = true
+				checker.locals = append (
+					checker.locals,
+					variable)
+			}
+
+			switch current.kind {
+			case accessRead:
+				checker.used[variable] = true
+			case accessWrite:
+				checker.written[variable] = true
+			case accessPartial:
+				checker.used[variable]    = true
+				checker.written[variable] = true
+				checker.partial[variable] = true
+			}
+		}
+	}
+}
+
+// checkAssignment makes sure that no variable is read before it is definitely
+// given a value, no matter which path is taken to get there. It returns the
+// set of variables that definitely have a value when the function returns.
+func (checker *flowChecker) checkAssignment () (final variableSet, err error) {
+	graph := checker.graph
+
+	// inputs and the receiver start off with a value, as do outputs that
+	// have a default value
+	initial := make(variableSet)
+	if checker.function.receiver != nil {
+		initial[checker.function.receiver] = true
+	}
+	for _, input := range checker.function.inputs {
+		initial[input] = true
+	}
+	for _, output := range checker.function.outputs {
+		if output.argument == nil { continue }
+		initial[&output.Variable] = true
+	}
+
+	// work out which variables have a value at the start of each node. a
+	// node that has not been visited yet has no set, and is ignored.
+	in  := map[*flowNode] variableSet { }
+	out := map[*flowNode] variableSet { graph.entry: initial }
+	nodes := append([]*flowNode { }, graph.nodes...)
+	nodes  = append(nodes, graph.exit)
+
+	changed := true
+	for changed {
+		changed = false
+		for _, node := range nodes {
+			if !node.reachable { continue }
+
+			var assigned variableSet
+			for _, previous := range node.previous {
+				set, visited := out[previous]
+				if !previous.reachable || !visited { continue }
+				if assigned == nil {
+					assigned = set
+				} else {
+					assigned = assigned.intersect(set)
+				}
+			}
+			if assigned == nil { continue }
+			in[node] = assigned
+
+			result := assigned.copy()
+			for _, current := range node.accesses {
+				if current.kind == accessRead { continue }
+				result[current.variable] = true
+			}
+
+			previous, visited := out[node]
+			if !visited || !result.equals(previous) {
+				out[node] = result
+				changed = true
+			}
+		}
+	}
+
+	for _, node := range graph.nodes {
+		if !node.reachable { continue }
+
+		assigned := in[node].copy()
+		for _, current := range node.accesses {
+			variable := current.variable
+			if current.kind != accessRead {
+				assigned[variable] = true
+				continue
+			}
+
+			// outputs without a default value start off as zero,
+			// so they can always be read
+			if !assigned[variable] && !variable.output {
+				err = current.NewError (
+					variable.name + " is used before it " +
+					"has been given a value",
+					infoerr.ErrorKindError)
+				return
+			}
+		}
+	}
+
+	final = in[graph.exit]
+	return
+}
+
+// checkReachable warns about code that can never run, and about loops that
+// never end or never run.
+func (checker *flowChecker) checkReachable () {
+	checker.checkReachableBlock(checker.function.root)
+	for _, note := range checker.graph.notes {
+		if !note.node.reachable { continue }
+		checker.warn(note.node.location, note.message)
+	}
+}
+
+// checkReachableBlock warns about the first phrase in a block that can never
+// run, and looks inside the blocks of the phrases before it.
+func (checker *flowChecker) checkReachableBlock (block Block) {
+	for _, phrase := range block.phrases {
+		start, exists := checker.graph.starts[phrase.Location()]
+		if exists && !start.reachable {
+			checker.warn (
+				phrase.Location(),
+				"this code will never run")
+			return
+		}
+
+		switch phrase.(type) {
+		case IfPhrase:
+			ifPhrase := phrase.(IfPhrase)
+			for _, branch := range ifPhrase.branches {
+				checker.checkReachableBlock(branch.block)
+			}
+			if ifPhrase.fallback != nil {
+				checker.checkReachableBlock(*ifPhrase.fallback)
+			}
+
+		case SwitchPhrase:
+			for _, casePhrase := range phrase.(SwitchPhrase).cases {
+				checker.checkReachableBlock(casePhrase.block)
+			}
+
+		case WhilePhrase:
+			checker.checkReachableBlock(phrase.(WhilePhrase).block)
+
+		case ForPhrase:
+			// loops over empty arrays are already warned about
+			forPhrase := phrase.(ForPhrase)
+			if !isEmptyConstant(forPhrase.collection) {
+				checker.checkReachableBlock(forPhrase.block)
+			}
+
+		case DeferPhrase:
+			checker.checkReachableBlock(phrase.(DeferPhrase).block)
+		}
+	}
+}
+
+// checkOutputs warns about outputs that might not be given a value by the time
+// the function returns, given the set of variables that definitely have a
+// value at the end of the function.
+func (checker *flowChecker) checkOutputs (final variableSet) {
+	for _, output := range checker.function.outputs {
+		if output.argument != nil { continue }
+		variable := &output.Variable
+		if final[variable] { continue }
+
+		if checker.written[variable] {
+			checker.warn (
+				output.location,
+				"output " + output.name + " is not set on " +
+				"every path through the function")
+		} else {
+			checker.warn (
+				output.location,
+				"output " + output.name + " is never set")
+		}
+	}
+}
+
+// checkUnused warns about inputs and local variables whose values are never
+// used.
+func (checker *flowChecker) checkUnused () {
+	for _, input := range checker.function.inputs {
+		if checker.used[input] { continue }
+		checker.warn (
+			input.location,
+			"input " + input.name + " is never used")
+	}
+	for _, local := range checker.locals {
+		if checker.used[local] { continue }
+		checker.warn (
+			local.location,
+			"variable " + local.name + " is never used")
+	}
+}
+
+// checkDeadStores warns about values that are stored in a variable, but are
+// then overwritten or forgotten about before they are ever read. Outputs are
+// always read by the caller, so storing a value in one is never pointless.
+// Variables that are never used at all are already warned about, and are
+// skipped here.
+func (checker *flowChecker) checkDeadStores () {
+	graph := checker.graph
+
+	// work out which variables might be read after each node
+	exit := make(variableSet)
+	for _, output := range checker.function.outputs {
+		exit[&output.Variable] = true
+	}
+	live := map[*flowNode] variableSet { graph.exit: exit }
+
+	changed := true
+	for changed {
+		changed = false
+		for index := len(graph.nodes) - 1; index >= 0; index -- {
+			node := graph.nodes[index]
+			result := checker.liveBefore(node, live, false)
+			if !result.equals(live[node]) {
+				live[node] = result
+				changed = true
+			}
+		}
+	}
+
+	for _, node := range graph.nodes {
+		if !node.reachable { continue }
+		checker.liveBefore(node, live, true)
+	}
+}
+
+// liveBefore returns the set of variables that might be read after the start
+// of a node, given the sets that have been worked out for the nodes after it.
+// If warn is true, values that are stored by the node and never read are
+// warned about.
+func (checker *flowChecker) liveBefore (
+	node *flowNode,
+	live map[*flowNode] variableSet,
+	warn bool,
+) (
+	result variableSet,
+) {
+	result = make(variableSet)
+	for _, next := range node.next {
+		result = result.union(live[next])
+	}
+
+	var found []infoerr.Error
+	for index := len(node.accesses) - 1; index >= 0; index -- {
+		current  := node.accesses[index]
+		variable := current.variable
+
+		switch current.kind {
+		case accessRead, accessPartial:
+			result[variable] = true
+
+		case accessWrite:
+			tracked :=
+				checker.used[variable] &&
+				!checker.partial[variable]
+			if warn && tracked && !result[variable] {
+				found = append(found, infoerr.NewError (
+					current.location,
+					"value stored in " + variable.name +
+					" is never read",
+					infoerr.ErrorKindWarn))
+			}
+			delete(result, variable)
+		}
+	}
+
+	// the accesses were gone through backwards, so the warnings need to
+	// be put back in order
+	for index := len(found) - 1; index >= 0; index -- {
+		checker.warnings = append(checker.warnings, found[index])
+	}
+	return
+}
+
+// headAccesses returns the accesses made by the subject and case values of a
+// switch phrase, which are all evaluated before any case is run.
+func (phrase SwitchPhrase) headAccesses () (accesses []access) {
+	accesses = argumentAccesses(phrase.subject, accesses)
+	for _, casePhrase := range phrase.cases {
+		for _, value := range casePhrase.values {
+			accesses = argumentAccesses(value, accesses)
+		}
+	}
+	return
+}
+
+// canFallThrough returns whether it is possible for none of the cases in a
+// switch phrase to run. This is impossible if there is a default case, or if
+// the switch is over an enum, because every member of the enum must then be
+// handled.
+func (phrase SwitchPhrase) canFallThrough () (canFallThrough bool) {
+	for _, casePhrase := range phrase.cases {
+		if casePhrase.IsDefault() { return }
+	}
+	canFallThrough = !isEnum(phrase.subject.What())
+	return
+}
+
+// phraseAccesses returns the accesses a non control flow phrase makes to
+// variables, in the order that they happen.
+func phraseAccesses (phrase Phrase) (accesses []access) {
+	switch phrase.(type) {
+	case AssignPhrase:
+		assign := phrase.(AssignPhrase)
+		for _, argument := range assign.arguments {
+			accesses = argumentAccesses(argument, accesses)
+		}
+		if assign.operator != lexer.TokenKindAssignment {
+			// operators like ++ and += read their target first
+			accesses = argumentAccesses(assign.target, accesses)
+		}
+		accesses = targetAccesses(assign.target, accesses)
+
+	case ArbitraryPhrase:
+		for _, argument := range phrase.(ArbitraryPhrase).arguments {
+			accesses = argumentAccesses(argument, accesses)
+		}
+
+	case Argument:
+		accesses = argumentAccesses(phrase.(Argument), accesses)
+	}
+	return
+}
+
+// argumentAccesses appends the accesses that evaluating an argument makes to
+// variables.
+func argumentAccesses (
+	argument Argument,
+	previous []access,
+) (
+	accesses []access,
+) {
+	accesses = previous
+	switch argument.(type) {
+	case VariableReference:
+		reference := argument.(VariableReference)
+		accesses = append(accesses, access {
+			locatable: reference.locatable,
+			kind:      accessRead,
+			variable:  reference.variable,
+		})
+
+	case MemberAccess:
+		accesses = argumentAccesses (
+			argument.(MemberAccess).base,
+			accesses)
+
+	case Dereference:
+		accesses = argumentAccesses (
+			argument.(Dereference).argument,
+			accesses)
+
+	case ReferencePhrase:
+		accesses = partialAccesses (
+			argument.(ReferencePhrase).value,
+			accesses)
+
+	case CastPhrase:
+		accesses = argumentAccesses (
+			argument.(CastPhrase).value,
+			accesses)
+
+	case OperatorPhrase:
+		for _, operand := range argument.(OperatorPhrase).arguments {
+			accesses = argumentAccesses(operand, accesses)
+		}
+
+	case CallPhrase:
+		call := argument.(CallPhrase)
+		if call.receiver != nil {
+			// methods are called on the location of their receiver
+			accesses = partialAccesses(call.receiver, accesses)
+		}
+		for _, input := range call.arguments {
+			accesses = argumentAccesses(input, accesses)
+		}
+		for _, returnee := range call.returnsTo {
+			accesses = targetAccesses(returnee, accesses)
+		}
+	}
+	return
+}
+
+// targetAccesses appends the accesses that storing a value in an argument makes
+// to variables.
+func targetAccesses (
+	target   Argument,
+	previous []access,
+) (
+	accesses []access,
+) {
+	accesses = previous
+	reference, isVariable := target.(VariableReference)
+	if isVariable {
+		accesses = append(accesses, access {
+			locatable: reference.locatable,
+			kind:      accessWrite,
+			variable:  reference.variable,
+		})
+	} else {
+		accesses = partialAccesses(target, accesses)
+	}
+	return
+}
+
+// partialAccesses appends the accesses that modifying part of an argument, or
+// taking its location, makes to variables. Anything accessed through a pointer
+// only reads the pointer.
+func partialAccesses (
+	argument Argument,
+	previous []access,
+) (
+	accesses []access,
+) {
+	accesses = previous
+	if argument.What().kind == TypeKindPointer {
+		accesses = argumentAccesses(argument, accesses)
+		return
+	}
+
+	switch argument.(type) {
+	case VariableReference:
+		reference := argument.(VariableReference)
+		accesses = append(accesses, access {
+			locatable: reference.locatable,
+			kind:      accessPartial,
+			variable:  reference.variable,
+		})
+
+	case MemberAccess:
+		accesses = partialAccesses (
+			argument.(MemberAccess).base,
+			accesses)
+
+	case Dereference:
+		accesses = partialAccesses (
+			argument.(Dereference).argument,
+			accesses)
+
+	default:
+		accesses = argumentAccesses(argument, accesses)
+	}
+	return
+}
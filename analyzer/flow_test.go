@@ -0,0 +1,79 @@
+package analyzer
+
+import "os"
+import "fmt"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// checkWarnings analyzes a module, and makes sure that the correct warnings are
+// recorded in its sections. Each warning is written as row:column message,
+// with the row and column starting at zero.
+func checkWarnings (modulePath string, correct []string, test *testing.T) {
+	cwd, _ := os.Getwd()
+	modulePath = filepath.Join(cwd, modulePath)
+	table, err := AnalyzeQuietly(modulePath, false, target.Default)
+	if err != nil {
+		test.Log("returned error:")
+		test.Log(err)
+		test.Fail()
+		return
+	}
+
+	warnings := []string { }
+	for _, section := range table.Sorted() {
+		if section.ModulePath() != modulePath { continue }
+		for _, warning := range section.Warnings() {
+			warnings = append(warnings, fmt.Sprint (
+				warning.Row(), ":", warning.Column(), " ",
+				warning.Message()))
+		}
+	}
+
+	mismatch := len(warnings) != len(correct)
+	for index := 0; !mismatch && index < len(warnings); index ++ {
+		mismatch = warnings[index] != correct[index]
+	}
+	if mismatch {
+		test.Log("CORRECT:")
+		for _, warning := range correct { test.Log(warning) }
+		test.Log("RESULT:")
+		for _, warning := range warnings { test.Log(warning) }
+		test.Fail()
+	}
+}
+
+func TestFlow (test *testing.T) {
+	checkWarnings("../tests/analyzer/flow", []string {
+		"15:1 output result is not set on every path through the " +
+		"function",
+		"21:1 output result is never set",
+		"26:1 input ignored is never used",
+		"28:3 variable unread is never used",
+		"34:3 value stored in temporary is never read",
+	}, test)
+}
+
+func TestReachability (test *testing.T) {
+	checkWarnings("../tests/analyzer/reachability", []string {
+		"7:2 this code will never run",
+		"16:1 this code will never run",
+		"14:1 this loop never ends, because its condition is always " +
+		"true",
+		"22:1 this loop never runs, because the array it goes over " +
+		"is empty",
+		"22:5 variable character is never used",
+		"30:2 this code will never run",
+		"34:2 this code will never run",
+		"42:2 this code will never run",
+	}, test)
+}
+
+func TestReadBeforeAssign (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/readBeforeAssign",
+		infoerr.ErrorKindError,
+		"count is used before it has been given a value",
+		5, 13, test)
+}
@@ -7,19 +7,90 @@ import "git.tebibyte.media/arf/arf/infoerr"
 // FuncSection represents a type definition section.
 type FuncSection struct {
 	sectionBase
-	root Block
+	receiver *Variable
+	inputs   []*Variable
+	outputs  []*FuncOutput
+	root     Block
 	external bool
 }
 
+// FuncOutput represents an output of a function. It can have a default value.
+type FuncOutput struct {
+	Variable
+	argument Argument
+}
+
 // ToString returns all data stored within the function section, in string form.
 func (section FuncSection) ToString (indent int) (output string) {
 	output += doIndent(indent, "funcSection ")
 	output += section.permission.ToString() + " "
 	output += section.where.ToString()
 	output += "\n"
-	
-	// TODO: arguments
-	output += section.root.ToString(indent + 1)
+
+	if section.receiver != nil {
+		output += doIndent (
+			indent + 1, "receiver ",
+			section.receiver.name, "\n")
+		output += section.receiver.what.ToString(indent + 2)
+	}
+
+	for _, input := range section.inputs {
+		output += doIndent(indent + 1, "input ", input.name, "\n")
+		output += input.what.ToString(indent + 2)
+	}
+
+	for _, funcOutput := range section.outputs {
+		output += doIndent(indent + 1, "output ", funcOutput.name, "\n")
+		output += funcOutput.what.ToString(indent + 2)
+		if funcOutput.argument != nil {
+			output += funcOutput.argument.ToString(indent + 2)
+		}
+	}
+
+	if section.external {
+		output += doIndent(indent + 1, "external\n")
+	} else {
+		output += section.root.ToString(indent + 1)
+	}
+	return
+}
+
+// Receiver returns the receiver of the function if it is a method, and nil if
+// it is not.
+func (section FuncSection) Receiver () (receiver *Variable) {
+	receiver = section.receiver
+	return
+}
+
+// Inputs returns the inputs of the function.
+func (section FuncSection) Inputs () (inputs []*Variable) {
+	inputs = section.inputs
+	return
+}
+
+// Outputs returns the outputs of the function.
+func (section FuncSection) Outputs () (outputs []*FuncOutput) {
+	outputs = section.outputs
+	return
+}
+
+// Root returns the block of phrases that make up the body of the function.
+func (section FuncSection) Root () (root Block) {
+	root = section.root
+	return
+}
+
+// External returns whether the function is defined outside of ARF, and so has
+// no body.
+func (section FuncSection) External () (external bool) {
+	external = section.external
+	return
+}
+
+// Argument returns the default value of the output. If it does not have one,
+// this is nil.
+func (output FuncOutput) Argument () (argument Argument) {
+	argument = output.argument
 	return
 }
 
@@ -37,8 +108,10 @@ func (analyzer *analysisOperation) analyzeFuncSection () (
 	inputSection := analyzer.currentSection.(parser.FuncSection)
 	outputSection.location = analyzer.currentSection.Location()
 
-	// TODO: do not do this if it is a method
-	if inputSection.Permission() == types.PermissionReadWrite {
+	// only methods can be read-write, because that decides whether they can
+	// be overridden
+	isMethod := inputSection.Receiver() != nil
+	if !isMethod && inputSection.Permission() == types.PermissionReadWrite {
 		err = inputSection.NewError (
 			"read-write (rw) permission not understood in this " +
 			"context, try read-only (ro)",
@@ -48,19 +121,161 @@ func (analyzer *analysisOperation) analyzeFuncSection () (
 
 	outputSection.permission = inputSection.Permission()
 
-	// TODO: analyze inputs and outputs and reciever
-	
+	// the receiver, inputs, and outputs all live in a scope that encloses
+	// the root block
+	arguments := scope { }
+	analyzer.pushScope(&arguments)
+	defer analyzer.popScope()
+
+	err = analyzer.analyzeFuncArguments(&outputSection, inputSection)
+	if err != nil { return }
+
+	if isMethod {
+		err = analyzer.checkMethod(&outputSection, inputSection)
+		if err != nil { return }
+	}
+
 	if inputSection.External() {
 		outputSection.external = true
 		if inputSection.Root() != nil {
 			panic("invalid state: input func is external with non-nil root")
 		}
-			
+
 	} else {
 		outputSection.root, err = analyzer.analyzeBlock(inputSection.Root())
 		if err != nil { return }
-		// TODO: analyze root block if not nil
+
+		var warnings []infoerr.Error
+		warnings, err = checkFlow(&outputSection)
+		if err != nil { return }
+		for _, warning := range warnings {
+			analyzer.warn(warning)
+		}
 	}
-	
+
+	outputSection.complete = true
+	return
+}
+
+// analyzeFuncArguments analyzes the receiver, inputs, and outputs of a parser
+// function section into a semantic function section, defining each of them as
+// a variable in the current scope.
+func (analyzer *analysisOperation) analyzeFuncArguments (
+	into *FuncSection,
+	from parser.FuncSection,
+) (
+	err error,
+) {
+	if from.Receiver() != nil {
+		into.receiver,
+		err = analyzer.analyzeDeclaration(*from.Receiver())
+		if err != nil { return }
+		err = analyzer.defineVariable(into.receiver)
+		if err != nil { return }
+	}
+
+	for index := 0; index < from.InputsLength(); index ++ {
+		var input *Variable
+		input, err = analyzer.analyzeDeclaration(from.Input(index))
+		if err != nil { return }
+		err = analyzer.defineVariable(input)
+		if err != nil { return }
+
+		into.inputs = append(into.inputs, input)
+	}
+
+	for index := 0; index < from.OutputsLength(); index ++ {
+		inputOutput := from.Output(index)
+
+		var variable *Variable
+		variable,
+		err = analyzer.analyzeDeclaration(inputOutput.Declaration)
+		if err != nil { return }
+
+		funcOutput := &FuncOutput { Variable: *variable }
+		funcOutput.output = true
+		err = analyzer.defineVariable(&funcOutput.Variable)
+		if err != nil { return }
+
+		// apply default value
+		if !inputOutput.Argument().Nil() {
+			funcOutput.argument,
+			err = analyzer.analyzeArgument(inputOutput.Argument())
+			if err != nil { return }
+
+			// type check default value
+			err = analyzer.typeCheck (
+				funcOutput.argument,
+				funcOutput.what)
+			if err != nil { return }
+		}
+
+		into.outputs = append(into.outputs, funcOutput)
+	}
+
+	return
+}
+
+// checkMethod makes sure that if a method overrides a method inherited from a
+// parent type, it is allowed to do so.
+func (analyzer *analysisOperation) checkMethod (
+	method *FuncSection,
+	from   parser.FuncSection,
+) (
+	err error,
+) {
+	// the parser makes sure that the receiver is a pointer, but it may
+	// point to something other than an object type
+	receiver := method.receiver.what
+	owner, isTypeSection := receiver.points.actual.(*TypeSection)
+	if !isTypeSection { return }
+
+	var overridden *FuncSection
+	overridden, err = analyzer.fetchMethod(owner.what, from.Name())
+	if err != nil || overridden == nil { return }
+
+	canOverride :=
+		analyzer.inCurrentModule(overridden) ||
+		overridden.permission == types.PermissionReadWrite
+	if !canOverride {
+		err = from.NewError (
+			"inherited method " + from.Name() + " is not " +
+			"read-write (rw) in parent type, and cannot be " +
+			"overridden here",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if method.permission > overridden.permission {
+		err = from.NewError (
+			"cannot relax permission of inherited method",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if !method.sameSignature(overridden) {
+		err = from.NewError (
+			"method must have the same inputs and outputs as the " +
+			"inherited method it overrides",
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
+
+// sameSignature returns whether or not two functions have inputs and outputs
+// of the same types.
+func (section FuncSection) sameSignature (other *FuncSection) (same bool) {
+	if len(section.inputs)  != len(other.inputs)  { return }
+	if len(section.outputs) != len(other.outputs) { return }
+
+	for index, input := range section.inputs {
+		if !input.what.Equals(other.inputs[index].what) { return }
+	}
+	for index, output := range section.outputs {
+		if !output.what.Equals(other.outputs[index].what) { return }
+	}
+
+	same = true
 	return
 }
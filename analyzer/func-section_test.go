@@ -12,8 +12,8 @@ funcSection ro ../tests/analyzer/funcSection.bArbitrary
 	block
 		arbitraryPhrase
 			command 'puts'
-			castPhrase
-				type aCString
-				arg string 'hellorld` + "\000" + `'
+			castPhrase pointer
+				type 1 basic aCString
+				stringLiteral 'hellorld` + "\000" + `'
 `, test)
 }
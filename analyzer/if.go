@@ -0,0 +1,146 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// IfPhrase represents a chain of conditional branches. It is made up of an if
+// phrase, along with any elseif and else phrases that come directly after it.
+// The first branch whose condition is true is run, and if none of them are,
+// the fallback is run.
+type IfPhrase struct {
+	phraseBase
+	branches []IfBranch
+
+	// fallback is the block under the else phrase, and is nil if there is
+	// no else phrase.
+	fallback *Block
+}
+
+// IfBranch is a single if or elseif phrase within an if phrase.
+type IfBranch struct {
+	locatable
+	condition Argument
+	block     Block
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase IfPhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "ifPhrase\n")
+	for _, branch := range phrase.branches {
+		output += doIndent(indent + 1, "branch\n")
+		output += branch.condition.ToString(indent + 2)
+		output += branch.block.ToString(indent + 2)
+	}
+	if phrase.fallback != nil {
+		output += doIndent(indent + 1, "else\n")
+		output += phrase.fallback.ToString(indent + 2)
+	}
+	return
+}
+
+// Branches returns the if and elseif branches of the phrase, in order.
+func (phrase IfPhrase) Branches () (branches []IfBranch) {
+	branches = phrase.branches
+	return
+}
+
+// Fallback returns the block that is run if none of the branches are. If there
+// is no else phrase, this is nil.
+func (phrase IfPhrase) Fallback () (fallback *Block) {
+	fallback = phrase.fallback
+	return
+}
+
+// Condition returns the condition of the branch.
+func (branch IfBranch) Condition () (condition Argument) {
+	condition = branch.condition
+	return
+}
+
+// Block returns the block that is run if the condition is true.
+func (branch IfBranch) Block () (block Block) {
+	block = branch.block
+	return
+}
+
+// analyzeIfPhrase analyzes a phrase of the form [if condition], along with the
+// elseif and else phrases that come directly after it within the same block.
+func (analyzer *analysisOperation) analyzeIfPhrase (
+	inputPhrase   parser.Phrase,
+	inputBranches []parser.Phrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := IfPhrase { }
+	outputPhrase.location = inputPhrase.Location()
+
+	inputBranches = append (
+		[]parser.Phrase { inputPhrase },
+		inputBranches...)
+	for index, inputBranch := range inputBranches {
+		if inputBranch.Kind() == parser.PhraseKindElse {
+			if index != len(inputBranches) - 1 {
+				err = inputBranches[index + 1].NewError (
+					"else must be the last branch of an if",
+					infoerr.ErrorKindError)
+				return
+			}
+			if inputBranch.Length() > 0 {
+				err = inputBranch.NewError (
+					"else does not take a condition",
+					infoerr.ErrorKindError)
+				return
+			}
+
+			var fallback Block
+			fallback, err = analyzer.analyzeBlock (
+				inputBranch.Block())
+			if err != nil { return }
+			outputPhrase.fallback = &fallback
+			break
+		}
+
+		if inputBranch.Length() != 1 {
+			err = inputBranch.NewError (
+				"if and elseif expect exactly one condition",
+				infoerr.ErrorKindError)
+			return
+		}
+
+		outputBranch := IfBranch { }
+		outputBranch.location = inputBranch.Location()
+		outputBranch.condition, err = analyzer.analyzeArgument (
+			inputBranch.Argument(0))
+		if err != nil { return }
+
+		err = checkCondition(outputBranch.condition)
+		if err != nil { return }
+
+		outputBranch.block, err = analyzer.analyzeBlock (
+			inputBranch.Block())
+		if err != nil { return }
+
+		outputPhrase.branches = append (
+			outputPhrase.branches,
+			outputBranch)
+	}
+
+	phrase = outputPhrase
+	return
+}
+
+// checkCondition makes sure that an argument can be used as a truth value.
+func checkCondition (condition Argument) (err error) {
+	if isUntyped(condition) {
+		if condition.canBePassedAs(truthType()) { return }
+	} else if condition.What().isBoolean() {
+		return
+	}
+
+	err = condition.NewError (
+		"condition must be a truth value, but " +
+		condition.What().Describe() + " is not",
+		infoerr.ErrorKindError)
+	return
+}
@@ -0,0 +1,38 @@
+package analyzer
+
+import "testing"
+
+func TestIfPhrase (test *testing.T) {
+	checkTree ("../tests/analyzer/ifPhrase", false,
+`funcSection ro ../tests/analyzer/ifPhrase.aSign
+	input number
+		type 1 basic Int
+	output sign
+		type 1 basic Int
+	block
+		ifPhrase
+			branch
+				operatorPhrase <
+					type 1 basic Bool
+					variable number
+					uintLiteral 0
+				block
+					assignPhrase =
+						variable sign
+						intLiteral -1
+			branch
+				operatorPhrase >
+					type 1 basic Bool
+					variable number
+					uintLiteral 0
+				block
+					assignPhrase =
+						variable sign
+						uintLiteral 1
+			else
+				block
+					assignPhrase =
+						variable sign
+						uintLiteral 0
+`, test)
+}
@@ -0,0 +1,680 @@
+package analyzer
+
+import "fmt"
+import "sort"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/types"
+
+// JSONVersion is the version of the JSON schema that section tables are
+// encoded with. It is increased whenever the schema changes in a way that
+// would break existing decoders, and tables encoded with a different version
+// cannot be decoded.
+const JSONVersion = 1
+
+// The JSON schema describes the interface of each section: its permission, its
+// resolved type, its members, and its signature. Types refer to the sections
+// they are based on by module path and name, and primitives have an empty
+// module path. Default values are only kept if they are constant literals, and
+// the bodies of functions are left out, so a decoded table can be inspected but
+// not translated. 64 bit integers are encoded as strings, so that they survive
+// being read by programs that store all numbers as floats.
+
+type jsonLocation struct {
+	File   string `json:"file"`
+	Row    int    `json:"row"`
+	Column int    `json:"column"`
+	Width  int    `json:"width"`
+}
+
+type jsonTable struct {
+	Version  int           `json:"version"`
+	Sections []jsonSection `json:"sections"`
+}
+
+type jsonReference struct {
+	Module string `json:"module"`
+	Name   string `json:"name"`
+}
+
+type jsonSection struct {
+	Kind       string        `json:"kind"`
+	Module     string        `json:"module"`
+	Name       string        `json:"name"`
+	Location   *jsonLocation `json:"location,omitempty"`
+	Permission string        `json:"permission"`
+
+	// data, type, enum, and face sections
+	Type     *jsonType    `json:"type,omitempty"`
+	Value    *jsonValue   `json:"value,omitempty"`
+	External bool         `json:"external,omitempty"`
+	Members  []jsonMember `json:"members,omitempty"`
+
+	// face sections
+	Face      string         `json:"face,omitempty"`
+	Behaviors []jsonBehavior `json:"behaviors,omitempty"`
+
+	// function interfaces and func sections
+	Receiver *jsonVariable  `json:"receiver,omitempty"`
+	Inputs   []jsonVariable `json:"inputs,omitempty"`
+	Outputs  []jsonVariable `json:"outputs,omitempty"`
+}
+
+type jsonMember struct {
+	Location   *jsonLocation `json:"location,omitempty"`
+	Name       string        `json:"name"`
+	Module     string        `json:"module,omitempty"`
+	Permission string        `json:"permission,omitempty"`
+	Type       *jsonType     `json:"type,omitempty"`
+	Value      *jsonValue    `json:"value,omitempty"`
+	BitWidth   uint64        `json:"bitWidth,omitempty,string"`
+}
+
+type jsonBehavior struct {
+	Location *jsonLocation  `json:"location,omitempty"`
+	Name     string         `json:"name"`
+	Inputs   []jsonVariable `json:"inputs,omitempty"`
+	Outputs  []jsonVariable `json:"outputs,omitempty"`
+}
+
+type jsonVariable struct {
+	Location *jsonLocation `json:"location,omitempty"`
+	Name     string        `json:"name"`
+	Type     *jsonType     `json:"type"`
+	Value    *jsonValue    `json:"value,omitempty"`
+}
+
+type jsonType struct {
+	Location *jsonLocation  `json:"location,omitempty"`
+	Kind     string         `json:"kind"`
+	Mutable  bool           `json:"mutable,omitempty"`
+	Length   uint64         `json:"length,omitempty,string"`
+	Section  *jsonReference `json:"section,omitempty"`
+	Points   *jsonType      `json:"points,omitempty"`
+}
+
+type jsonValue struct {
+	Location *jsonLocation `json:"location,omitempty"`
+	Kind     string        `json:"kind"`
+	Int      int64         `json:"int,omitempty,string"`
+	UInt     uint64        `json:"uint,omitempty,string"`
+	Float    float64       `json:"float,omitempty"`
+	Bool     bool          `json:"bool,omitempty"`
+	String   string        `json:"string,omitempty"`
+}
+
+// typeKindNames and faceKindNames hold the names that each kind is encoded
+// with.
+var typeKindNames = map[TypeKind] string {
+	TypeKindBasic:         "basic",
+	TypeKindPointer:       "pointer",
+	TypeKindVariableArray: "variableArray",
+}
+
+var faceKindNames = map[FaceKind] string {
+	FaceKindType: "type",
+	FaceKindFunc: "func",
+}
+
+// kindFromName looks up the kind that a name was encoded from.
+func kindFromName[KIND comparable] (
+	names map[KIND] string,
+	name  string,
+	what  string,
+) (
+	kind KIND,
+	err  error,
+) {
+	for candidate, candidateName := range names {
+		if candidateName == name {
+			kind = candidate
+			return
+		}
+	}
+	err = fmt.Errorf("unknown %s kind %q", what, name)
+	return
+}
+
+// MarshalJSON encodes the table as JSON. Sections are sorted by module path and
+// then by name, so encoding the same table twice produces the same output.
+func (table SectionTable) MarshalJSON () (data []byte, err error) {
+	encoded := jsonTable {
+		Version:  JSONVersion,
+		Sections: []jsonSection { },
+	}
+	for _, section := range table.Sorted() {
+		encoded.Sections = append (
+			encoded.Sections,
+			encodeSection(section))
+	}
+	data, err = json.Marshal(encoded)
+	return
+}
+
+// encodeLocation encodes a location, or nil if it is not in a file.
+func encodeLocation (location file.Location) (encoded *jsonLocation) {
+	if location.File() == nil { return }
+	encoded = &jsonLocation {
+		File:   location.File().Path(),
+		Row:    location.Row(),
+		Column: location.Column(),
+		Width:  location.Width(),
+	}
+	return
+}
+
+// encodeSection encodes a section along with its type and members.
+func encodeSection (section Section) (encoded jsonSection) {
+	encoded.Module     = section.ModulePath()
+	encoded.Name       = section.Name()
+	encoded.Location   = encodeLocation(section.Location())
+	encoded.Permission = section.Permission().ToString()
+
+	switch section.(type) {
+	case *DataSection:
+		data := section.(*DataSection)
+		encoded.Kind     = "data"
+		encoded.Type     = encodeType(data.what)
+		encoded.Value    = encodeValue(data.argument)
+		encoded.External = data.external
+
+	case *TypeSection:
+		typeSection := section.(*TypeSection)
+		encoded.Kind  = "type"
+		encoded.Type  = encodeType(typeSection.what)
+		encoded.Value = encodeValue(typeSection.argument)
+		for _, member := range typeSection.members {
+			encoded.Members = append(encoded.Members, jsonMember {
+				Location:   encodeLocation(member.location),
+				Name:       member.name,
+				Module:     member.modulePath,
+				Permission: member.permission.ToString(),
+				Type:       encodeType(member.what),
+				Value:      encodeValue(member.argument),
+				BitWidth:   member.bitWidth,
+			})
+		}
+
+	case *EnumSection:
+		enum := section.(*EnumSection)
+		encoded.Kind  = "enum"
+		encoded.Type  = encodeType(enum.what)
+		encoded.Value = encodeValue(enum.argument)
+		for _, member := range enum.members {
+			encoded.Members = append(encoded.Members, jsonMember {
+				Location: encodeLocation(member.location),
+				Name:     member.name,
+				Value:    encodeValue(member.argument),
+			})
+		}
+
+	case *FaceSection:
+		face := section.(*FaceSection)
+		encoded.Kind = "face"
+		encoded.Type = encodeType(face.what)
+		encoded.Face = faceKindNames[face.kind]
+		names := make([]string, 0, len(face.behaviors))
+		for name := range face.behaviors {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			behavior := face.behaviors[name]
+			encodedBehavior := jsonBehavior {
+				Location: encodeLocation(behavior.location),
+				Name:     behavior.name,
+				Inputs:   encodeVariables(behavior.inputs),
+				Outputs:  encodeVariables(behavior.outputs),
+			}
+			encoded.Behaviors = append (
+				encoded.Behaviors,
+				encodedBehavior)
+		}
+		encoded.Inputs  = encodeVariables(face.signature.inputs)
+		encoded.Outputs = encodeVariables(face.signature.outputs)
+
+	case *FuncSection:
+		function := section.(*FuncSection)
+		encoded.Kind     = "func"
+		encoded.External = function.external
+		if function.receiver != nil {
+			receiver := encodeVariable(function.receiver)
+			encoded.Receiver = &receiver
+		}
+		encoded.Inputs = encodeVariables(function.inputs)
+		for _, output := range function.outputs {
+			variable := encodeVariable(&output.Variable)
+			variable.Value = encodeValue(output.argument)
+			encoded.Outputs = append(encoded.Outputs, variable)
+		}
+	}
+	return
+}
+
+// encodeType encodes a type, and the types that it points to.
+func encodeType (what Type) (encoded *jsonType) {
+	encoded = &jsonType {
+		Location: encodeLocation(what.location),
+		Kind:     typeKindNames[what.kind],
+		Mutable:  what.mutable,
+		Length:   what.length,
+	}
+	if what.actual != nil {
+		encoded.Section = &jsonReference {
+			Module: what.actual.ModulePath(),
+			Name:   what.actual.Name(),
+		}
+	}
+	if what.points != nil {
+		encoded.Points = encodeType(*what.points)
+	}
+	return
+}
+
+// encodeVariable encodes a variable and its type.
+func encodeVariable (variable *Variable) (encoded jsonVariable) {
+	encoded = jsonVariable {
+		Location: encodeLocation(variable.location),
+		Name:     variable.name,
+		Type:     encodeType(variable.what),
+	}
+	return
+}
+
+// encodeVariables encodes a list of variables.
+func encodeVariables (variables []*Variable) (encoded []jsonVariable) {
+	for _, variable := range variables {
+		encoded = append(encoded, encodeVariable(variable))
+	}
+	return
+}
+
+// encodeValue encodes a value if it is a constant literal. Otherwise, it
+// returns nil.
+func encodeValue (argument Argument) (encoded *jsonValue) {
+	switch argument.(type) {
+	case IntLiteral:
+		literal := argument.(IntLiteral)
+		encoded = &jsonValue { Kind: "int", Int: literal.value }
+	case UIntLiteral:
+		literal := argument.(UIntLiteral)
+		encoded = &jsonValue { Kind: "uint", UInt: literal.value }
+	case FloatLiteral:
+		literal := argument.(FloatLiteral)
+		encoded = &jsonValue { Kind: "float", Float: literal.value }
+	case BoolLiteral:
+		literal := argument.(BoolLiteral)
+		encoded = &jsonValue { Kind: "bool", Bool: literal.value }
+	case StringLiteral:
+		literal := argument.(StringLiteral)
+		encoded = &jsonValue { Kind: "string", String: literal.value }
+	default:
+		return
+	}
+	encoded.Location = encodeLocation(argument.Location())
+	return
+}
+
+// jsonDecoder holds the state of a table that is being decoded from JSON.
+type jsonDecoder struct {
+	table SectionTable
+
+	// files holds a file for each path that a location refers to. These
+	// files are empty, because only the locations are stored in the JSON.
+	files map[string] *file.File
+}
+
+// UnmarshalJSON decodes a table from JSON that was produced by MarshalJSON.
+// Locations in the decoded table refer to files that are empty, so errors
+// created on its nodes will not show the line that they are on.
+func (table *SectionTable) UnmarshalJSON (data []byte) (err error) {
+	var encoded jsonTable
+	err = json.Unmarshal(data, &encoded)
+	if err != nil { return }
+	if encoded.Version != JSONVersion {
+		err = fmt.Errorf (
+			"cannot decode section table with schema version %d, " +
+			"expected version %d",
+			encoded.Version, JSONVersion)
+		return
+	}
+
+	decoder := jsonDecoder {
+		table: make(SectionTable),
+		files: make(map[string] *file.File),
+	}
+
+	// every section is created before any of them are filled in, so that
+	// types can refer to sections that come after them.
+	for _, encodedSection := range encoded.Sections {
+		var section Section
+		section, err = decoder.newSection(encodedSection)
+		if err != nil { return }
+		decoder.table[section.locator()] = section
+	}
+
+	for _, encodedSection := range encoded.Sections {
+		where := locator {
+			modulePath: encodedSection.Module,
+			name:       encodedSection.Name,
+		}
+		err = decoder.section(decoder.table[where], encodedSection)
+		if err != nil { return }
+	}
+
+	*table = decoder.table
+	return
+}
+
+// location decodes a location. Nil decodes into a location with no file.
+func (decoder jsonDecoder) location (
+	encoded *jsonLocation,
+) (
+	location file.Location,
+) {
+	if encoded == nil { return }
+	sourceFile, exists := decoder.files[encoded.File]
+	if !exists {
+		sourceFile = file.Load(encoded.File, "")
+		decoder.files[encoded.File] = sourceFile
+	}
+	location = file.NewLocation (
+		sourceFile,
+		encoded.Row, encoded.Column, encoded.Width)
+	return
+}
+
+// newSection creates an empty section of the kind that was encoded.
+func (decoder jsonDecoder) newSection (
+	encoded jsonSection,
+) (
+	section Section,
+	err     error,
+) {
+	base := sectionBase {
+		where: locator {
+			modulePath: encoded.Module,
+			name:       encoded.Name,
+		},
+		complete: true,
+	}
+	base.location = decoder.location(encoded.Location)
+	base.permission, err = decodePermission(encoded.Permission)
+	if err != nil { return }
+
+	switch encoded.Kind {
+	case "data": section = &DataSection { sectionBase: base }
+	case "type": section = &TypeSection { sectionBase: base }
+	case "enum": section = &EnumSection { sectionBase: base }
+	case "face": section = &FaceSection { sectionBase: base }
+	case "func": section = &FuncSection { sectionBase: base }
+	default:
+		err = fmt.Errorf("unknown section kind %q", encoded.Kind)
+	}
+	return
+}
+
+// decodePermission decodes a permission from its two letter name.
+func decodePermission (
+	encoded string,
+) (
+	permission types.Permission,
+	err        error,
+) {
+	permission, worked := types.PermissionFrom(encoded)
+	if !worked {
+		err = fmt.Errorf("unknown permission %q", encoded)
+	}
+	return
+}
+
+// section fills in a section that was created by newSection.
+func (decoder jsonDecoder) section (
+	section Section,
+	encoded jsonSection,
+) (
+	err error,
+) {
+	switch section.(type) {
+	case *DataSection:
+		data := section.(*DataSection)
+		data.external = encoded.External
+		data.argument = decoder.value(encoded.Value)
+		data.what, err = decoder.what(encoded.Type)
+
+	case *TypeSection:
+		typeSection := section.(*TypeSection)
+		typeSection.argument = decoder.value(encoded.Value)
+		typeSection.what, err = decoder.what(encoded.Type)
+		if err != nil { return }
+		for _, encodedMember := range encoded.Members {
+			member := ObjectMember {
+				name:       encodedMember.Name,
+				modulePath: encodedMember.Module,
+				bitWidth:   encodedMember.BitWidth,
+				argument:   decoder.value(encodedMember.Value),
+			}
+			member.location =
+				decoder.location(encodedMember.Location)
+			member.permission, err =
+				decodePermission(encodedMember.Permission)
+			if err != nil { return }
+			member.what, err = decoder.what(encodedMember.Type)
+			if err != nil { return }
+			typeSection.members = append (
+				typeSection.members,
+				member)
+		}
+
+	case *EnumSection:
+		enum := section.(*EnumSection)
+		enum.argument = decoder.value(encoded.Value)
+		enum.what, err = decoder.what(encoded.Type)
+		if err != nil { return }
+		for _, encodedMember := range encoded.Members {
+			member := EnumMember {
+				name:     encodedMember.Name,
+				argument: decoder.value(encodedMember.Value),
+			}
+			member.location =
+				decoder.location(encodedMember.Location)
+			enum.members = append(enum.members, member)
+		}
+
+	case *FaceSection:
+		err = decoder.face(section.(*FaceSection), encoded)
+
+	case *FuncSection:
+		err = decoder.function(section.(*FuncSection), encoded)
+	}
+	return
+}
+
+// face fills in the behaviors or signature of an interface section.
+func (decoder jsonDecoder) face (
+	face    *FaceSection,
+	encoded jsonSection,
+) (
+	err error,
+) {
+	face.what, err = decoder.what(encoded.Type)
+	if err != nil { return }
+
+	face.kind, err = kindFromName(faceKindNames, encoded.Face, "face")
+	if err != nil { return }
+
+	switch face.kind {
+	case FaceKindType:
+		face.behaviors = make(map[string] FaceBehavior)
+		for _, encodedBehavior := range encoded.Behaviors {
+			var behavior FaceBehavior
+			behavior.name = encodedBehavior.Name
+			behavior.location =
+				decoder.location(encodedBehavior.Location)
+			behavior.inputs, err =
+				decoder.variables(encodedBehavior.Inputs)
+			if err != nil { return }
+			behavior.outputs, err =
+				decoder.variables(encodedBehavior.Outputs)
+			if err != nil { return }
+			face.behaviors[behavior.name] = behavior
+		}
+
+	case FaceKindFunc:
+		face.signature.location = face.location
+		face.signature.inputs, err = decoder.variables(encoded.Inputs)
+		if err != nil { return }
+		face.signature.outputs, err = decoder.variables(encoded.Outputs)
+	}
+	return
+}
+
+// function fills in the receiver, inputs, and outputs of a function section.
+func (decoder jsonDecoder) function (
+	function *FuncSection,
+	encoded  jsonSection,
+) (
+	err error,
+) {
+	function.external = encoded.External
+
+	if encoded.Receiver != nil {
+		function.receiver, err = decoder.variable(*encoded.Receiver)
+		if err != nil { return }
+	}
+
+	function.inputs, err = decoder.variables(encoded.Inputs)
+	if err != nil { return }
+
+	for _, encodedOutput := range encoded.Outputs {
+		var variable *Variable
+		variable, err = decoder.variable(encodedOutput)
+		if err != nil { return }
+		variable.output = true
+		function.outputs = append(function.outputs, &FuncOutput {
+			Variable: *variable,
+			argument: decoder.value(encodedOutput.Value),
+		})
+	}
+	return
+}
+
+// what decodes a type, and the types that it points to.
+func (decoder jsonDecoder) what (encoded *jsonType) (what Type, err error) {
+	if encoded == nil {
+		err = fmt.Errorf("missing type")
+		return
+	}
+
+	what.location = decoder.location(encoded.Location)
+	what.mutable  = encoded.Mutable
+	what.length   = encoded.Length
+
+	what.kind, err = kindFromName(typeKindNames, encoded.Kind, "type")
+	if err != nil { return }
+
+	if encoded.Section != nil {
+		what.actual, err = decoder.reference(*encoded.Section)
+		if err != nil { return }
+	}
+
+	if encoded.Points != nil {
+		var points Type
+		points, err = decoder.what(encoded.Points)
+		what.points = &points
+	}
+	return
+}
+
+// reference finds the section that a type refers to. It is either in the
+// table, or it is a primitive.
+func (decoder jsonDecoder) reference (
+	encoded jsonReference,
+) (
+	section Section,
+	err     error,
+) {
+	if encoded.Module == "" {
+		var exists bool
+		section, exists = primitiveNamed(encoded.Name)
+		if !exists {
+			err = fmt.Errorf("unknown primitive %q", encoded.Name)
+		}
+		return
+	}
+
+	section, exists := decoder.table[locator {
+		modulePath: encoded.Module,
+		name:       encoded.Name,
+	}]
+	if !exists {
+		err = fmt.Errorf (
+			"type refers to %s.%s, which is not in the table",
+			encoded.Module, encoded.Name)
+	}
+	return
+}
+
+// variable decodes a variable and its type.
+func (decoder jsonDecoder) variable (
+	encoded jsonVariable,
+) (
+	variable *Variable,
+	err      error,
+) {
+	variable = &Variable { name: encoded.Name }
+	variable.location = decoder.location(encoded.Location)
+	variable.what, err = decoder.what(encoded.Type)
+	return
+}
+
+// variables decodes a list of variables.
+func (decoder jsonDecoder) variables (
+	encoded []jsonVariable,
+) (
+	variables []*Variable,
+	err       error,
+) {
+	for _, encodedVariable := range encoded {
+		var variable *Variable
+		variable, err = decoder.variable(encodedVariable)
+		if err != nil { return }
+		variables = append(variables, variable)
+	}
+	return
+}
+
+// value decodes a constant literal. If there is no value, it returns nil.
+func (decoder jsonDecoder) value (encoded *jsonValue) (argument Argument) {
+	if encoded == nil { return }
+	location := locatable { location: decoder.location(encoded.Location) }
+
+	switch encoded.Kind {
+	case "int":
+		argument = IntLiteral {
+			locatable: location,
+			value:     encoded.Int,
+		}
+	case "uint":
+		argument = UIntLiteral {
+			locatable: location,
+			value:     encoded.UInt,
+		}
+	case "float":
+		argument = FloatLiteral {
+			locatable: location,
+			value:     encoded.Float,
+		}
+	case "bool":
+		argument = BoolLiteral {
+			locatable: location,
+			value:     encoded.Bool,
+		}
+	case "string":
+		argument = StringLiteral {
+			locatable: location,
+			value:     encoded.String,
+		}
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package analyzer
+
+import "os"
+import "testing"
+import "path/filepath"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/target"
+
+// checkJSON checks that a table decodes from JSON into a table that encodes to
+// the same JSON.
+func checkJSON (table SectionTable, test *testing.T) (decoded SectionTable) {
+	data, err := json.Marshal(table)
+	if err != nil {
+		test.Log("encoding returned error:", err)
+		test.Fail()
+		return
+	}
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		test.Log("decoding returned error:", err)
+		test.Fail()
+		return
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		test.Log("encoding again returned error:", err)
+		test.Fail()
+		return
+	}
+	if string(again) != string(data) {
+		test.Log("BEFORE:\n" + string(data))
+		test.Log("AFTER:\n"  + string(again))
+		test.Fail()
+	}
+	return
+}
+
+func TestJSON (test *testing.T) {
+	cwd, _ := os.Getwd()
+	pattern := filepath.Join(cwd, "../tests/analyzer/*")
+	modulePaths, _ := filepath.Glob(pattern)
+	for _, modulePath := range modulePaths {
+		table, err := Analyze(modulePath, false, target.Default)
+		// modules that are meant to fail analysis have nothing to
+		// encode
+		if err != nil { continue }
+		test.Log("encoding", modulePath)
+		checkJSON(table, test)
+	}
+}
+
+func TestJSONReferences (test *testing.T) {
+	cwd, _ := os.Getwd()
+	modulePath := filepath.Join(cwd, "../tests/analyzer/typeSection")
+	table, err := Analyze(modulePath, false, target.Default)
+	if err != nil {
+		test.Log("returned error:", err)
+		test.FailNow()
+	}
+	decoded := checkJSON(table, test)
+
+	for where, section := range table {
+		decodedSection, exists := decoded[where]
+		if !exists {
+			test.Log("section", where.ToString(), "was not decoded")
+			test.Fail()
+			continue
+		}
+
+		typeSection, isTypeSection := section.(*TypeSection)
+		if !isTypeSection { continue }
+		what        := typeSection.What()
+		decodedWhat := decodedSection.(*TypeSection).What()
+
+		// types must refer to the same primitives, and to sections
+		// within the decoded table
+		if what.Primitive() != decodedWhat.Primitive() {
+			test.Log("wrong primitive in", where.ToString())
+			test.Fail()
+		}
+		actual := decodedWhat.Actual()
+		if actual != nil && actual.ModulePath() != "" &&
+			decoded[actual.locator()] != actual {
+			test.Log(where.ToString(), "refers outside of table")
+			test.Fail()
+		}
+	}
+}
+
+func TestJSONVersion (test *testing.T) {
+	var table SectionTable
+	err := json.Unmarshal([]byte(`{ "version": 2 }`), &table)
+	correct :=
+		"cannot decode section table with schema version 2, " +
+		"expected version 1"
+	if err == nil || err.Error() != correct {
+		test.Log("CORRECT:", correct)
+		test.Log("RESULT: ", err)
+		test.Fail()
+	}
+}
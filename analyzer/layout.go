@@ -0,0 +1,268 @@
+package analyzer
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/target"
+
+// Layout describes how a value of a certain type is stored in memory on a
+// specific target. All sizes and offsets are measured in bytes. Layouts match
+// the way a C compiler would lay out the equivalent struct on that target.
+type Layout struct {
+	Size      int
+	Alignment int
+
+	// Members is only filled in for object types.
+	Members []MemberLayout
+}
+
+// MemberLayout describes where a member of an object is stored within it.
+type MemberLayout struct {
+	Layout
+	Name string
+
+	// Offset is the position of the member within the object. For bit
+	// fields, this is the position of the storage unit that the member is
+	// packed into.
+	Offset int
+
+	// BitOffset and BitWidth are only used for bit fields. BitOffset is
+	// the position of the member within its storage unit, starting at the
+	// least significant bit.
+	BitOffset int
+	BitWidth  int
+}
+
+// ToString returns all data stored within the layout, in string form.
+func (layout Layout) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "size ", layout.Size,
+		" align ", layout.Alignment, "\n")
+	for _, member := range layout.Members {
+		output += member.ToString(indent + 1)
+	}
+	return
+}
+
+// ToString returns all data stored within the member layout, in string form.
+func (member MemberLayout) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "member ", member.Name,
+		" offset ", member.Offset)
+	if member.BitWidth > 0 {
+		output += fmt.Sprint (
+			" bit ", member.BitOffset,
+			" width ", member.BitWidth)
+	}
+	output += "\n"
+	output += member.Layout.ToString(indent + 1)
+	return
+}
+
+// Layout computes the memory layout of the type on the specified target.
+func (what Type) Layout (platform target.Target) (layout Layout) {
+	switch what.kind {
+	case TypeKindPointer:
+		layout = pointerLayout(platform)
+
+	case TypeKindVariableArray:
+		// variable arrays are stored as a pointer to their elements,
+		// followed by their length
+		layout = structLayout([]Layout {
+			pointerLayout(platform),
+			wordLayout(platform),
+		})
+
+	case TypeKindBasic:
+		layout, _ = SectionLayout(what.actual, platform)
+	}
+
+	if what.length > 1 {
+		layout.Size   *= int(what.length)
+		layout.Members = nil
+	}
+	return
+}
+
+// Layout computes the memory layout of the type section on the specified
+// target. Objects are laid out member by member, including the members they
+// inherit.
+func (section *TypeSection) Layout (platform target.Target) (layout Layout) {
+	size, isPrimitive := primitiveSize(section, platform)
+	if isPrimitive {
+		layout.Size      = size
+		layout.Alignment = platform.Alignment(size)
+		return
+	}
+
+	isObject :=
+		section.what.kind == TypeKindBasic &&
+		section.what.length == 1 &&
+		section.what.underlyingPrimitive() == &PrimitiveObj
+	if !isObject {
+		layout = section.what.Layout(platform)
+		return
+	}
+
+	layout.Alignment = 1
+	bit := 0
+	for _, member := range section.allMembers() {
+		memberLayout := MemberLayout {
+			Layout: member.what.Layout(platform),
+			Name:   member.name,
+		}
+		if memberLayout.Alignment > layout.Alignment {
+			layout.Alignment = memberLayout.Alignment
+		}
+
+		if member.bitWidth > 0 {
+			// bit fields are packed into storage units the size of
+			// their type. a bit field that would cross the boundary
+			// of a unit is moved to the start of the next one.
+			width    := int(member.bitWidth)
+			unitBits := memberLayout.Alignment * 8
+			if bit / unitBits != (bit + width - 1) / unitBits {
+				bit = alignTo(bit, unitBits)
+			}
+
+			unit := bit / unitBits * unitBits
+			memberLayout.Offset    = unit / 8
+			memberLayout.BitOffset = bit - unit
+			memberLayout.BitWidth  = width
+			bit += width
+		} else {
+			offset := alignTo(bit, 8) / 8
+			offset  = alignTo(offset, memberLayout.Alignment)
+			memberLayout.Offset = offset
+			bit = (offset + memberLayout.Size) * 8
+		}
+
+		layout.Members = append(layout.Members, memberLayout)
+	}
+
+	layout.Size = alignTo(alignTo(bit, 8) / 8, layout.Alignment)
+	return
+}
+
+// allMembers returns every member of the object, including the ones it
+// inherits. Inherited members come first, and members that are redefined take
+// the place of the originals.
+func (section *TypeSection) allMembers () (members []ObjectMember) {
+	parent, inherits := section.what.actual.(*TypeSection)
+	if inherits {
+		members = append(members, parent.allMembers()...)
+	}
+
+	for _, member := range section.members {
+		redefined := false
+		for index, inherited := range members {
+			if inherited.name == member.name {
+				members[index] = member
+				redefined = true
+				break
+			}
+		}
+		if !redefined {
+			members = append(members, member)
+		}
+	}
+	return
+}
+
+// SectionLayout computes the memory layout of a value whose type is defined by
+// the specified section. If the section does not define a type, isType will be
+// false.
+func SectionLayout (
+	section  Section,
+	platform target.Target,
+) (
+	layout Layout,
+	isType bool,
+) {
+	isType = true
+	switch section.(type) {
+	case *TypeSection:
+		layout = section.(*TypeSection).Layout(platform)
+
+	case *EnumSection:
+		layout = section.(*EnumSection).what.Layout(platform)
+
+	case *FaceSection:
+		if section.(*FaceSection).kind == FaceKindFunc {
+			// function interfaces are a pointer to a function
+			layout = pointerLayout(platform)
+		} else {
+			// object interfaces are a pointer to the object, and a
+			// pointer to its method table
+			layout = structLayout([]Layout {
+				pointerLayout(platform),
+				pointerLayout(platform),
+			})
+		}
+
+	default:
+		layout.Alignment = 1
+		isType = false
+	}
+	return
+}
+
+// primitiveSize returns the size of a primitive type section. If the section is
+// not a primitive with a fixed size, isPrimitive will be false.
+func primitiveSize (
+	section  *TypeSection,
+	platform target.Target,
+) (
+	size        int,
+	isPrimitive bool,
+) {
+	isPrimitive = true
+	switch section {
+	case &PrimitiveI8, &PrimitiveU8, &PrimitiveBool:
+		size = 1
+	case &PrimitiveI16, &PrimitiveU16:
+		size = 2
+	case &PrimitiveI32, &PrimitiveU32, &PrimitiveF32:
+		size = 4
+	case &PrimitiveI64, &PrimitiveU64, &PrimitiveF64:
+		size = 8
+	case &PrimitiveInt, &PrimitiveUInt:
+		size = platform.WordSize
+	default:
+		isPrimitive = false
+	}
+	return
+}
+
+// pointerLayout returns the layout of a pointer on the specified target.
+func pointerLayout (platform target.Target) (layout Layout) {
+	layout.Size      = platform.PointerSize
+	layout.Alignment = platform.Alignment(platform.PointerSize)
+	return
+}
+
+// wordLayout returns the layout of Int and UInt on the specified target.
+func wordLayout (platform target.Target) (layout Layout) {
+	layout.Size      = platform.WordSize
+	layout.Alignment = platform.Alignment(platform.WordSize)
+	return
+}
+
+// structLayout returns the layout of an anonymous struct made up of the
+// specified fields, in order.
+func structLayout (fields []Layout) (layout Layout) {
+	layout.Alignment = 1
+	for _, field := range fields {
+		if field.Alignment > layout.Alignment {
+			layout.Alignment = field.Alignment
+		}
+		layout.Size = alignTo(layout.Size, field.Alignment) + field.Size
+	}
+	layout.Size = alignTo(layout.Size, layout.Alignment)
+	return
+}
+
+// alignTo rounds offset up to the next multiple of alignment.
+func alignTo (offset, alignment int) (aligned int) {
+	if alignment < 1 { alignment = 1 }
+	aligned = (offset + alignment - 1) / alignment * alignment
+	return
+}
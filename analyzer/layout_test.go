@@ -0,0 +1,140 @@
+package analyzer
+
+import "os"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/testCommon"
+
+func checkLayout (
+	modulePath string,
+	platform   target.Target,
+	correct    string,
+	test       *testing.T,
+) {
+	cwd, _ := os.Getwd()
+	modulePath = filepath.Join(cwd, modulePath)
+	table, err := Analyze(modulePath, false, platform)
+	testCommon.CheckStrings(test, layoutDump { table, platform }, err, correct)
+}
+
+type layoutDump struct {
+	SectionTable
+	platform target.Target
+}
+
+func (dump layoutDump) ToString (indent int) (output string) {
+	for _, section := range dump.Sorted() {
+		layout, isType := SectionLayout(section, dump.platform)
+		if !isType { continue }
+		output += doIndent(indent, section.Name(), "\n")
+		output += layout.ToString(indent + 1)
+	}
+	return
+}
+
+func TestLayoutX86_64 (test *testing.T) {
+	checkLayout("../tests/analyzer/layout", target.X86_64,
+`aPoint
+	size 16 align 8
+		member x offset 0
+			size 8 align 8
+		member y offset 8
+			size 8 align 8
+bMixed
+	size 24 align 8
+		member small offset 0
+			size 1 align 1
+		member large offset 8
+			size 8 align 8
+		member medium offset 16
+			size 2 align 2
+cFlags
+	size 8 align 4
+		member a offset 0 bit 0 width 1
+			size 1 align 1
+		member b offset 0 bit 1 width 3
+			size 1 align 1
+		member c offset 1 bit 0 width 6
+			size 1 align 1
+		member d offset 4 bit 0 width 20
+			size 4 align 4
+		member after offset 7
+			size 1 align 1
+dHolder
+	size 32 align 8
+		member name offset 0
+			size 16 align 8
+		member next offset 16
+			size 8 align 8
+		member samples offset 24
+			size 8 align 2
+eLabeled
+	size 24 align 8
+		member x offset 0
+			size 8 align 8
+		member y offset 8
+			size 8 align 8
+		member label offset 16
+			size 1 align 1
+fDoer
+	size 16 align 8
+gLevel
+	size 2 align 2
+hBytes
+	size 8 align 8
+`, test)
+}
+
+func TestLayoutI386 (test *testing.T) {
+	checkLayout("../tests/analyzer/layout", target.I386,
+`aPoint
+	size 8 align 4
+		member x offset 0
+			size 4 align 4
+		member y offset 4
+			size 4 align 4
+bMixed
+	size 16 align 4
+		member small offset 0
+			size 1 align 1
+		member large offset 4
+			size 8 align 4
+		member medium offset 12
+			size 2 align 2
+cFlags
+	size 8 align 4
+		member a offset 0 bit 0 width 1
+			size 1 align 1
+		member b offset 0 bit 1 width 3
+			size 1 align 1
+		member c offset 1 bit 0 width 6
+			size 1 align 1
+		member d offset 4 bit 0 width 20
+			size 4 align 4
+		member after offset 7
+			size 1 align 1
+dHolder
+	size 20 align 4
+		member name offset 0
+			size 8 align 4
+		member next offset 8
+			size 4 align 4
+		member samples offset 12
+			size 8 align 2
+eLabeled
+	size 12 align 4
+		member x offset 0
+			size 4 align 4
+		member y offset 4
+			size 4 align 4
+		member label offset 8
+			size 1 align 1
+fDoer
+	size 8 align 4
+gLevel
+	size 2 align 2
+hBytes
+	size 4 align 4
+`, test)
+}
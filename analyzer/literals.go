@@ -1,6 +1,8 @@
 package analyzer
 
 import "fmt"
+import "math/big"
+import "git.tebibyte.media/arf/arf/infoerr"
 
 // IntLiteral represents a constant signed integer value.
 type IntLiteral struct {
@@ -20,6 +22,12 @@ type FloatLiteral struct {
 	value float64
 }
 
+// BoolLiteral represents a constant truth value.
+type BoolLiteral struct {
+	locatable
+	value bool
+}
+
 // StringLiteral represents a constant text value.
 type StringLiteral struct {
 	locatable
@@ -161,6 +169,47 @@ func (literal FloatLiteral) canBePassedAs (what Type) (allowed bool) {
 	return
 }
 
+// What returns the type of the argument
+func (literal BoolLiteral) What () (what Type) {
+	what.actual = &PrimitiveBool
+	what.length = 1
+	return
+}
+
+// ToString outputs the data in the argument as a string.
+func (literal BoolLiteral) ToString (indent int) (output string) {
+	output += doIndent(indent, fmt.Sprint("boolLiteral ", literal.value, "\n"))
+	return
+}
+
+// Equals returns whether the literal is equal to the specified value.
+func (literal BoolLiteral) Equals (value any) (equal bool) {
+	equal = literal.value == value
+	return
+}
+
+// Value returns the literal's value
+func (literal BoolLiteral) Value () (value any) {
+	value = literal.value
+	return
+}
+
+// Resolve resolves the argument to a constant literal, which in this case is
+// trivial because the literal is already constant.
+func (literal BoolLiteral) Resolve () (constant Argument, err error) {
+	constant = literal
+	return
+}
+
+// canBePassedAs returns true if this literal can be implicitly cast to the
+// specified type, and false if it can't.
+func (literal BoolLiteral) canBePassedAs (what Type) (allowed bool) {
+	// can be passed to singular types that are booleans at a primitive
+	// level.
+	allowed = what.isBoolean()
+	return
+}
+
 // What returns the type of the argument
 func (literal StringLiteral) What () (what Type) {
 	what.actual = &BuiltInString
@@ -218,3 +267,86 @@ func (literal StringLiteral) canBePassedAs (what Type) (allowed bool) {
 
 	return
 }
+
+// warnPrecisionLoss prints a warning if source is a numeric literal that cannot
+// be represented exactly by destination, which is a floating point type. Large
+// integers and floats with too many significant digits will be rounded when
+// they are stored, which is probably not what the user intended.
+func (analyzer *analysisOperation) warnPrecisionLoss (
+	source      Argument,
+	destination Type,
+) {
+	if !destination.isFloat() { return }
+
+	var exact *big.Float
+	switch source.(type) {
+	case IntLiteral:
+		exact = new(big.Float).SetInt64(source.(IntLiteral).value)
+	case UIntLiteral:
+		exact = new(big.Float).SetUint64(source.(UIntLiteral).value)
+	case FloatLiteral:
+		exact = big.NewFloat(source.(FloatLiteral).value)
+	default:
+		return
+	}
+
+	var rounded  float64
+	var accuracy big.Accuracy
+	if destination.underlyingPrimitive() == &PrimitiveF32 {
+		var single float32
+		single, accuracy = exact.Float32()
+		rounded = float64(single)
+	} else {
+		rounded, accuracy = exact.Float64()
+	}
+	if accuracy == big.Exact { return }
+
+	analyzer.warn(infoerr.NewError (
+		source.Location(),
+		fmt.Sprint (
+			"literal cannot be represented exactly by ",
+			destination.Describe(), ", and will be rounded to ",
+			rounded),
+		infoerr.ErrorKindWarn))
+}
+
+// checkIntegerRange makes sure that source, if it is an integer literal, fits
+// within the range of destination. If width is non-zero, the literal must fit
+// within that many bits instead. This is used for bit fields.
+func (analyzer *analysisOperation) checkIntegerRange (
+	source      Argument,
+	destination Type,
+	width       uint64,
+) (
+	err error,
+) {
+	if !destination.isSingular() || !destination.isInteger() { return }
+
+	var value *big.Int
+	switch source.(type) {
+	case IntLiteral:
+		value = big.NewInt(source.(IntLiteral).value)
+	case UIntLiteral:
+		value = new(big.Int).SetUint64(source.(UIntLiteral).value)
+	default:
+		return
+	}
+
+	description := destination.Describe()
+	if width > 0 {
+		description = fmt.Sprint("a ", width, " bit field")
+	} else {
+		width = destination.bitWidth(analyzer.wordSize())
+	}
+
+	minimum, maximum := destination.integerRange(width)
+	if value.Cmp(minimum) >= 0 && value.Cmp(maximum) <= 0 { return }
+
+	err = source.NewError (
+		fmt.Sprint (
+			"literal ", value, " is out of range for ", description,
+			", which can only hold values from ", minimum, " to ",
+			maximum),
+		infoerr.ErrorKindError)
+	return
+}
@@ -0,0 +1,390 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/file"
+
+// Reference describes a name that is written somewhere in a module, and what
+// it refers to. It is meant to be used by tools such as language servers.
+type Reference struct {
+	// Name is the name of the thing being referred to.
+	Name string
+
+	// Location is where the name is written.
+	Location file.Location
+
+	// Definition is where the thing being referred to is defined. If it is
+	// built into the language, the file of the location will be nil.
+	Definition file.Location
+
+	// What is the type of the thing being referred to. It is only valid if
+	// Typed is true, because not everything has a type.
+	What  Type
+	Typed bool
+
+	// Section is the section being referred to. If the name does not refer
+	// to a section, it will be nil.
+	Section Section
+}
+
+// ReferenceAt finds the name written at the specified row and column of a file,
+// and returns what it refers to. The row and column start at zero. If there is
+// nothing there that can be referred to, found will be false.
+func (table SectionTable) ReferenceAt (
+	path   string,
+	row    int,
+	column int,
+) (
+	reference Reference,
+	found     bool,
+) {
+	finder := referenceFinder {
+		path:   path,
+		row:    row,
+		column: column,
+	}
+
+	for _, section := range table.Sorted() {
+		finder.section(section)
+	}
+
+	reference = finder.reference
+	found     = finder.found
+	return
+}
+
+// referenceFinder searches through sections for the most specific name that is
+// written at a position. Nodes are searched from the outside in, so anything
+// found within a node replaces the node itself.
+type referenceFinder struct {
+	path   string
+	row    int
+	column int
+
+	reference Reference
+	found     bool
+}
+
+// at returns whether a location covers the position that is being searched
+// for.
+func (finder *referenceFinder) at (location file.Location) (at bool) {
+	if location.File() == nil                { return }
+	if location.File().Path() != finder.path { return }
+	if location.Row() != finder.row          { return }
+
+	width := location.Width()
+	if width < 1 { width = 1 }
+	at =
+		finder.column >= location.Column() &&
+		finder.column <  location.Column() + width
+	return
+}
+
+// set records a reference that covers the position.
+func (finder *referenceFinder) set (reference Reference) {
+	finder.reference = reference
+	finder.found     = true
+}
+
+// sectionReference records a reference to a section if location covers the
+// position.
+func (finder *referenceFinder) sectionReference (
+	location file.Location,
+	section  Section,
+) {
+	if !finder.at(location) { return }
+
+	reference := Reference {
+		Name:       section.Name(),
+		Location:   location,
+		Definition: section.Location(),
+		Section:    section,
+	}
+
+	switch section.(type) {
+	case *TypeSection:
+		reference.What  = section.(*TypeSection).what
+		reference.Typed = true
+	case *DataSection:
+		reference.What  = section.(*DataSection).what
+		reference.Typed = true
+	case *EnumSection:
+		reference.What  = section.(*EnumSection).what
+		reference.Typed = true
+	case *FaceSection:
+		reference.What  = section.(*FaceSection).what
+		reference.Typed = true
+	}
+
+	finder.set(reference)
+}
+
+// section searches a section. The line that a section starts on refers to the
+// section itself, unless there is something more specific there.
+func (finder *referenceFinder) section (section Section) {
+	header := section.Location()
+	if header.File() == nil || header.File().Path() != finder.path {
+		return
+	}
+
+	if header.Row() == finder.row {
+		finder.sectionReference(finder.headerLocation(header), section)
+	}
+
+	switch section.(type) {
+	case *TypeSection:
+		typeSection := section.(*TypeSection)
+		finder.what(typeSection.what)
+		finder.argument(typeSection.argument)
+		for _, member := range typeSection.members {
+			finder.member(member)
+		}
+
+	case *DataSection:
+		dataSection := section.(*DataSection)
+		finder.what(dataSection.what)
+		finder.argument(dataSection.argument)
+
+	case *EnumSection:
+		enumSection := section.(*EnumSection)
+		finder.what(enumSection.what)
+		for _, member := range enumSection.members {
+			if finder.at(member.location) {
+				finder.set(Reference {
+					Name:       member.name,
+					Location:   member.location,
+					Definition: member.location,
+					What:       enumSection.what,
+					Typed:      true,
+				})
+			}
+			finder.argument(member.argument)
+		}
+
+	case *FaceSection:
+		faceSection := section.(*FaceSection)
+		finder.behavior(faceSection.signature)
+		for _, behavior := range faceSection.behaviors {
+			finder.behavior(behavior)
+		}
+
+	case *FuncSection:
+		funcSection := section.(*FuncSection)
+		if funcSection.receiver != nil {
+			finder.variable(funcSection.receiver)
+		}
+		for _, input := range funcSection.inputs {
+			finder.variable(input)
+		}
+		for _, output := range funcSection.outputs {
+			finder.variable(&output.Variable)
+			finder.argument(output.argument)
+		}
+		finder.block(funcSection.root)
+	}
+}
+
+// headerLocation returns a location that covers the rest of the line that a
+// section starts on, so that its name can be found.
+func (finder *referenceFinder) headerLocation (
+	header file.Location,
+) (
+	location file.Location,
+) {
+	line  := []rune(header.File().GetLine(header.Row()))
+	width := len(line) - header.Column()
+	location = header
+	location.SetWidth(width)
+	return
+}
+
+// member searches a member of an object type.
+func (finder *referenceFinder) member (member ObjectMember) {
+	if finder.at(member.location) {
+		finder.set(Reference {
+			Name:       member.name,
+			Location:   member.location,
+			Definition: member.location,
+			What:       member.what,
+			Typed:      true,
+		})
+	}
+	finder.what(member.what)
+	finder.argument(member.argument)
+}
+
+// behavior searches a behavior of an interface.
+func (finder *referenceFinder) behavior (behavior FaceBehavior) {
+	for _, input := range behavior.inputs {
+		finder.variable(input)
+	}
+	for _, output := range behavior.outputs {
+		finder.variable(output)
+	}
+}
+
+// variable searches the declaration of a variable.
+func (finder *referenceFinder) variable (variable *Variable) {
+	if variable == nil { return }
+	if finder.at(variable.location) {
+		finder.set(Reference {
+			Name:       variable.name,
+			Location:   variable.location,
+			Definition: variable.location,
+			What:       variable.what,
+			Typed:      true,
+		})
+	}
+	finder.what(variable.what)
+}
+
+// what searches a type.
+func (finder *referenceFinder) what (what Type) {
+	if what.actual != nil {
+		finder.sectionReference(what.location, what.actual)
+	}
+	if what.points != nil {
+		finder.what(*what.points)
+	}
+}
+
+// block searches every phrase in a block.
+func (finder *referenceFinder) block (block Block) {
+	for _, phrase := range block.phrases {
+		finder.phrase(phrase)
+	}
+}
+
+// phrase searches a phrase, and any blocks beneath it.
+func (finder *referenceFinder) phrase (phrase Phrase) {
+	switch phrase.(type) {
+	case ArbitraryPhrase:
+		for _, argument := range phrase.(ArbitraryPhrase).arguments {
+			finder.argument(argument)
+		}
+
+	case AssignPhrase:
+		assign := phrase.(AssignPhrase)
+		finder.argument(assign.target)
+		for _, argument := range assign.arguments {
+			finder.argument(argument)
+		}
+
+	case IfPhrase:
+		ifPhrase := phrase.(IfPhrase)
+		for _, branch := range ifPhrase.branches {
+			finder.argument(branch.condition)
+			finder.block(branch.block)
+		}
+		if ifPhrase.fallback != nil {
+			finder.block(*ifPhrase.fallback)
+		}
+
+	case SwitchPhrase:
+		switchPhrase := phrase.(SwitchPhrase)
+		finder.argument(switchPhrase.subject)
+		for _, casePhrase := range switchPhrase.cases {
+			for _, value := range casePhrase.values {
+				finder.argument(value)
+			}
+			finder.block(casePhrase.block)
+		}
+
+	case WhilePhrase:
+		whilePhrase := phrase.(WhilePhrase)
+		finder.argument(whilePhrase.condition)
+		finder.block(whilePhrase.block)
+
+	case ForPhrase:
+		forPhrase := phrase.(ForPhrase)
+		finder.variable(forPhrase.index)
+		finder.variable(forPhrase.element)
+		finder.argument(forPhrase.collection)
+		finder.block(forPhrase.block)
+
+	case DeferPhrase:
+		finder.block(phrase.(DeferPhrase).block)
+
+	case Argument:
+		finder.argument(phrase.(Argument))
+	}
+}
+
+// argument searches an argument, and any arguments within it.
+func (finder *referenceFinder) argument (argument Argument) {
+	switch argument.(type) {
+	case VariableReference:
+		reference := argument.(VariableReference)
+		if finder.at(reference.location) {
+			finder.set(Reference {
+				Name:       reference.variable.name,
+				Location:   reference.location,
+				Definition: reference.variable.location,
+				What:       reference.variable.what,
+				Typed:      true,
+			})
+		}
+		if reference.declaration {
+			finder.what(reference.variable.what)
+		}
+
+	case DataReference:
+		reference := argument.(DataReference)
+		finder.sectionReference(reference.location, reference.section)
+
+	case EnumMemberReference:
+		reference := argument.(EnumMemberReference)
+		if finder.at(reference.location) {
+			finder.set(Reference {
+				Name:       reference.member.name,
+				Location:   reference.location,
+				Definition: reference.member.location,
+				What:       reference.section.what,
+				Typed:      true,
+				Section:    reference.section,
+			})
+		}
+
+	case MemberAccess:
+		// the base of a member access is written in the same place as
+		// the access itself, so the access is checked last
+		access := argument.(MemberAccess)
+		finder.argument(access.base)
+		if finder.at(access.location) {
+			finder.set(Reference {
+				Name:       access.member.name,
+				Location:   access.location,
+				Definition: access.member.location,
+				What:       access.member.what,
+				Typed:      true,
+			})
+		}
+
+	case Dereference:
+		finder.argument(argument.(Dereference).argument)
+
+	case ReferencePhrase:
+		finder.argument(argument.(ReferencePhrase).value)
+
+	case CastPhrase:
+		cast := argument.(CastPhrase)
+		finder.argument(cast.value)
+		finder.what(cast.what)
+
+	case OperatorPhrase:
+		for _, operand := range argument.(OperatorPhrase).arguments {
+			finder.argument(operand)
+		}
+
+	case CallPhrase:
+		call := argument.(CallPhrase)
+		finder.sectionReference(call.command.location, call.function)
+		if call.receiver != nil {
+			finder.argument(call.receiver)
+		}
+		for _, input := range call.arguments {
+			finder.argument(input)
+		}
+		for _, returnee := range call.returnsTo {
+			finder.argument(returnee)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// WhilePhrase represents a phrase that runs the block under it over and over
+// for as long as its condition is true.
+type WhilePhrase struct {
+	phraseBase
+	condition Argument
+	block     Block
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase WhilePhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "whilePhrase\n")
+	output += phrase.condition.ToString(indent + 1)
+	output += phrase.block.ToString(indent + 1)
+	return
+}
+
+// Condition returns the condition that is checked before each iteration.
+func (phrase WhilePhrase) Condition () (condition Argument) {
+	condition = phrase.condition
+	return
+}
+
+// Block returns the block that is run while the condition is true.
+func (phrase WhilePhrase) Block () (block Block) {
+	block = phrase.block
+	return
+}
+
+// ForPhrase represents a phrase that runs the block under it once for each
+// element of an array. The index variable is optional, and is nil if it was
+// not declared.
+type ForPhrase struct {
+	phraseBase
+	index      *Variable
+	element    *Variable
+	collection Argument
+	block      Block
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase ForPhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "forPhrase\n")
+	if phrase.index != nil {
+		output += doIndent (
+			indent + 1,
+			"index ", phrase.index.name, "\n")
+		output += phrase.index.what.ToString(indent + 2)
+	}
+	output += doIndent(indent + 1, "element ", phrase.element.name, "\n")
+	output += phrase.element.what.ToString(indent + 2)
+	output += phrase.collection.ToString(indent + 1)
+	output += phrase.block.ToString(indent + 1)
+	return
+}
+
+// Index returns the variable that holds the index of the current element. If
+// it was not declared, this is nil.
+func (phrase ForPhrase) Index () (index *Variable) {
+	index = phrase.index
+	return
+}
+
+// Element returns the variable that holds the current element.
+func (phrase ForPhrase) Element () (element *Variable) {
+	element = phrase.element
+	return
+}
+
+// Collection returns the array being gone over.
+func (phrase ForPhrase) Collection () (collection Argument) {
+	collection = phrase.collection
+	return
+}
+
+// Block returns the block that is run for each element.
+func (phrase ForPhrase) Block () (block Block) {
+	block = phrase.block
+	return
+}
+
+// analyzeWhilePhrase analyzes a phrase of the form [while condition].
+func (analyzer *analysisOperation) analyzeWhilePhrase (
+	inputPhrase parser.Phrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := WhilePhrase { }
+	outputPhrase.location = inputPhrase.Location()
+
+	if inputPhrase.Length() != 1 {
+		err = outputPhrase.NewError (
+			"while expects exactly one condition",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase.condition, err = analyzer.analyzeArgument (
+		inputPhrase.Argument(0))
+	if err != nil { return }
+
+	err = checkCondition(outputPhrase.condition)
+	if err != nil { return }
+
+	outputPhrase.block, err = analyzer.analyzeBlock(inputPhrase.Block())
+	if err != nil { return }
+
+	phrase = outputPhrase
+	return
+}
+
+// analyzeForPhrase analyzes a phrase of the form [for element:Type array] or
+// [for index:Type element:Type array]. The index and element are declared
+// within the scope of the block under the phrase.
+func (analyzer *analysisOperation) analyzeForPhrase (
+	inputPhrase parser.Phrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := ForPhrase { }
+	outputPhrase.location = inputPhrase.Location()
+
+	length := inputPhrase.Length()
+	if length != 2 && length != 3 {
+		err = outputPhrase.NewError (
+			"for expects an optional index, an element, and an " +
+			"array to go over",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase.collection, err = analyzer.analyzeArgument (
+		inputPhrase.Argument(length - 1))
+	if err != nil { return }
+
+	elementType, isArray := arrayElement(outputPhrase.collection.What())
+	if !isArray {
+		err = outputPhrase.collection.NewError (
+			"for can only go over arrays, but " +
+			outputPhrase.collection.What().Describe() +
+			" is not an array",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase.element, err = analyzer.analyzeLoopVariable (
+		inputPhrase.Argument(length - 2))
+	if err != nil { return }
+
+	if !elementType.canBePassedAs(outputPhrase.element.what) {
+		err = outputPhrase.element.NewError (
+			"elements of " +
+			outputPhrase.collection.What().Describe() +
+			" cannot be stored in an element of type " +
+			outputPhrase.element.what.Describe(),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	seed := []*Variable { }
+	if length == 3 {
+		outputPhrase.index, err = analyzer.analyzeLoopVariable (
+			inputPhrase.Argument(0))
+		if err != nil { return }
+
+		index := outputPhrase.index.what
+		if !index.isSingular() || !index.isInteger() {
+			err = outputPhrase.index.NewError (
+				"index must be an integer, but " +
+				index.Describe() + " is not",
+				infoerr.ErrorKindError)
+			return
+		}
+		seed = append(seed, outputPhrase.index)
+	}
+	seed = append(seed, outputPhrase.element)
+
+	outputPhrase.block, err = analyzer.analyzeBlock (
+		inputPhrase.Block(),
+		seed...)
+	if err != nil { return }
+
+	phrase = outputPhrase
+	return
+}
+
+// analyzeLoopVariable analyzes an argument of a for phrase that must declare a
+// variable.
+func (analyzer *analysisOperation) analyzeLoopVariable (
+	inputArgument parser.Argument,
+) (
+	variable *Variable,
+	err      error,
+) {
+	if inputArgument.Kind() != parser.ArgumentKindDeclaration {
+		err = inputArgument.NewError (
+			"the index and element of a for phrase must be " +
+			"declarations",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	variable, err = analyzer.analyzeDeclaration (
+		inputArgument.Value().(parser.Declaration))
+	return
+}
+
+// arrayElement returns the type of the elements of an array type. If the type
+// is not an array, isArray will be false.
+func arrayElement (what Type) (element Type, isArray bool) {
+	if what.length > 1 {
+		element        = what
+		element.length = 1
+		isArray        = true
+		return
+	}
+
+	reduced, reducible := what.reduce()
+	if reducible && reduced.kind == TypeKindVariableArray {
+		element = *reduced.points
+		isArray = true
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestLoopPhrase (test *testing.T) {
+	checkTree ("../tests/analyzer/loopPhrase", false,
+`dataSection ro ../tests/analyzer/loopPhrase.aWord
+	type 1 basic String
+	stringLiteral 'word'
+funcSection ro ../tests/analyzer/loopPhrase.bSum
+	output sum
+		type 1 basic U32
+	block
+		forPhrase
+			index index
+				type 1 basic U32
+			element character
+				type 1 basic U32
+			data ../tests/analyzer/loopPhrase.aWord
+			block
+				assignPhrase =
+					variable sum
+					operatorPhrase +
+						type 1 basic U32
+						variable sum
+						operatorPhrase *
+							type 1 basic U32
+							variable index
+							variable character
+funcSection ro ../tests/analyzer/loopPhrase.cCount
+	input limit
+		type 1 basic Int
+	output count
+		type 1 basic Int
+	block
+		deferPhrase
+			block
+				arbitraryPhrase
+					command 'puts'
+					stringLiteral 'done'
+		whilePhrase
+			operatorPhrase <
+				type 1 basic Bool
+				variable count
+				variable limit
+			block
+				assignPhrase ++
+					variable count
+`, test)
+}
+
+func TestForNotArray (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/forNotArray",
+		infoerr.ErrorKindError,
+		"for can only go over arrays, but Int is not an array",
+		7, 15, test)
+}
+
+func TestForIndexType (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/forIndexType",
+		infoerr.ErrorKindError,
+		"index must be an integer, but F64 is not",
+		6, 5, test)
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestMutability (test *testing.T) {
+	checkTree ("../tests/analyzer/mutability", false,
+`typeSection ro ../tests/analyzer/mutability.aCounter
+	type 1 basic Obj
+	member ro count
+		type 1 basic Int
+funcSection ro ../tests/analyzer/mutability.aCounter_bIncrement
+	receiver counter
+		type 1 pointer {
+			type 1 mutable basic aCounter
+		}
+	block
+		assignPhrase ++
+			member count
+				variable counter
+funcSection ro ../tests/analyzer/mutability.cSet
+	input target
+		type 1 pointer {
+			type 1 mutable basic Int
+		}
+	input value
+		type 1 basic Int
+	block
+		assignPhrase =
+			dereference 0
+				type 1 mutable basic Int
+				variable target
+			variable value
+dataSection ro ../tests/analyzer/mutability.dTotal
+	type 1 mutable basic Int
+	uintLiteral 0
+funcSection ro ../tests/analyzer/mutability.eMutate
+	input counter
+		type 1 mutable basic aCounter
+	input numbers
+		type 4 mutable basic Int
+	block
+		callPhrase ../tests/analyzer/mutability.aCounter_bIncrement
+			receiver
+				variable counter
+		assignPhrase =
+			declaration local
+				type 1 mutable basic Int
+			uintLiteral 3
+		assignPhrase ++
+			variable local
+		callPhrase ../tests/analyzer/mutability.cSet
+			referencePhrase
+				type 1 pointer {
+					type 1 mutable basic Int
+				}
+				variable local
+			uintLiteral 4
+		assignPhrase =
+			dereference 2
+				type 1 mutable basic Int
+				variable numbers
+			uintLiteral 7
+		assignPhrase ++
+			data ../tests/analyzer/mutability.dTotal
+		assignPhrase =
+			declaration pointer
+				type 1 pointer {
+					type 1 basic Int
+				}
+			referencePhrase
+				type 1 pointer {
+					type 1 mutable basic Int
+				}
+				variable local
+`, test)
+}
+
+func TestImmutableInput (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/immutableInput",
+		infoerr.ErrorKindError,
+		"cannot modify this, because it is not mutable",
+		6, 3, test)
+}
+
+func TestImmutablePointee (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/immutablePointee",
+		infoerr.ErrorKindError,
+		"cannot modify this, because it is not mutable",
+		6, 3, test)
+}
+
+func TestMutablePointer (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/mutablePointer",
+		infoerr.ErrorKindError,
+		"{Int} cannot be used as {Int:mut}",
+		11, 6, test)
+}
+
+func TestMutatingMethod (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/mutatingMethod",
+		infoerr.ErrorKindError,
+		"bIncrement modifies its receiver, so it cannot be " +
+		"called on an immutable value",
+		14, 1, test)
+}
@@ -33,6 +33,10 @@ type sectionBase struct {
 	complete   bool
 	permission types.Permission
 	locatable
+
+	// warnings contains everything that was found wrong with the section,
+	// apart from errors.
+	warnings []infoerr.Error
 }
 
 // Name returns the name of the section.
@@ -65,6 +69,18 @@ func (section sectionBase) Permission () (permission types.Permission) {
 	return
 }
 
+// Warnings returns the warnings that were found while analyzing the section, in
+// the order that they were found in.
+func (section sectionBase) Warnings () (warnings []infoerr.Error) {
+	warnings = section.warnings
+	return
+}
+
+// addWarnings adds warnings to the section.
+func (section *sectionBase) addWarnings (warnings []infoerr.Error) {
+	section.warnings = append(section.warnings, warnings...)
+}
+
 // locator returns the module path and name of the section.
 func (section sectionBase) locator () (where locator) {
 	where = section.where
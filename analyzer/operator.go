@@ -0,0 +1,667 @@
+package analyzer
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// operatorKind determines what family an operator belongs to. This decides
+// what types of operands it accepts, and what type it results in.
+type operatorKind int
+
+const (
+	// operatorKindArithmetic operators take in numbers, and result in a
+	// number of the same type.
+	operatorKindArithmetic operatorKind = iota
+
+	// operatorKindBitwise operators take in integers, and result in an
+	// integer of the same type.
+	operatorKindBitwise
+
+	// operatorKindShift operators take in an integer and an amount to shift
+	// it by, and result in an integer of the same type as the first.
+	operatorKindShift
+
+	// operatorKindLogical operators take in truth values, and result in a
+	// truth value.
+	operatorKindLogical
+
+	// operatorKindEquality operators take in two values of the same type,
+	// and result in a truth value.
+	operatorKindEquality
+
+	// operatorKindOrdering operators take in two numbers of the same type,
+	// and result in a truth value.
+	operatorKindOrdering
+)
+
+// operatorInfo describes the typing rules of a single operator.
+type operatorInfo struct {
+	symbol string
+	kind   operatorKind
+
+	// the minimum and maximum amount of operands the operator accepts. if
+	// the maximum is zero, there is no upper limit.
+	minimum int
+	maximum int
+
+	// if true, the operator only accepts integers even though it belongs
+	// to a family that would otherwise accept floats.
+	integerOnly bool
+
+	// if true, the result of the operation is stored in the first operand
+	// instead of being returned.
+	assigns bool
+}
+
+// operators lists the typing rules of every operator that can be used as the
+// command of a phrase.
+var operators = map[lexer.TokenKind] operatorInfo {
+	lexer.TokenKindPlus: {
+		symbol: "+", kind: operatorKindArithmetic,
+		minimum: 2 },
+	lexer.TokenKindMinus: {
+		symbol: "-", kind: operatorKindArithmetic,
+		minimum: 1 },
+	lexer.TokenKindAsterisk: {
+		symbol: "*", kind: operatorKindArithmetic,
+		minimum: 2 },
+	lexer.TokenKindSlash: {
+		symbol: "/", kind: operatorKindArithmetic,
+		minimum: 2 },
+	lexer.TokenKindPercent: {
+		symbol: "%", kind: operatorKindArithmetic,
+		minimum: 2, maximum: 2, integerOnly: true },
+	lexer.TokenKindPercentAssignment: {
+		symbol: "%=", kind: operatorKindArithmetic,
+		minimum: 2, maximum: 2, integerOnly: true, assigns: true },
+	lexer.TokenKindIncrement: {
+		symbol: "++", kind: operatorKindArithmetic,
+		minimum: 1, maximum: 1, assigns: true },
+	lexer.TokenKindDecrement: {
+		symbol: "--", kind: operatorKindArithmetic,
+		minimum: 1, maximum: 1, assigns: true },
+
+	lexer.TokenKindTilde: {
+		symbol: "~", kind: operatorKindBitwise,
+		minimum: 1, maximum: 1 },
+	lexer.TokenKindTildeAssignment: {
+		symbol: "~=", kind: operatorKindBitwise,
+		minimum: 1, maximum: 1, assigns: true },
+	lexer.TokenKindBinaryOr: {
+		symbol: "|", kind: operatorKindBitwise,
+		minimum: 2 },
+	lexer.TokenKindBinaryOrAssignment: {
+		symbol: "|=", kind: operatorKindBitwise,
+		minimum: 2, maximum: 2, assigns: true },
+	lexer.TokenKindBinaryAnd: {
+		symbol: "&", kind: operatorKindBitwise,
+		minimum: 2 },
+	lexer.TokenKindBinaryAndAssignment: {
+		symbol: "&=", kind: operatorKindBitwise,
+		minimum: 2, maximum: 2, assigns: true },
+	lexer.TokenKindBinaryXor: {
+		symbol: "^", kind: operatorKindBitwise,
+		minimum: 2 },
+	lexer.TokenKindBinaryXorAssignment: {
+		symbol: "^=", kind: operatorKindBitwise,
+		minimum: 2, maximum: 2, assigns: true },
+
+	lexer.TokenKindLShift: {
+		symbol: "<<", kind: operatorKindShift,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindLShiftAssignment: {
+		symbol: "<<=", kind: operatorKindShift,
+		minimum: 2, maximum: 2, assigns: true },
+	lexer.TokenKindRShift: {
+		symbol: ">>", kind: operatorKindShift,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindRShiftAssignment: {
+		symbol: ">>=", kind: operatorKindShift,
+		minimum: 2, maximum: 2, assigns: true },
+
+	lexer.TokenKindExclamation: {
+		symbol: "!", kind: operatorKindLogical,
+		minimum: 1, maximum: 1 },
+	lexer.TokenKindLogicalAnd: {
+		symbol: "&&", kind: operatorKindLogical,
+		minimum: 2 },
+	lexer.TokenKindLogicalOr: {
+		symbol: "||", kind: operatorKindLogical,
+		minimum: 2 },
+
+	lexer.TokenKindEqualTo: {
+		symbol: "==", kind: operatorKindEquality,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindNotEqualTo: {
+		symbol: "!=", kind: operatorKindEquality,
+		minimum: 2, maximum: 2 },
+
+	lexer.TokenKindLessThan: {
+		symbol: "<", kind: operatorKindOrdering,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindLessThanEqualTo: {
+		symbol: "<=", kind: operatorKindOrdering,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindGreaterThan: {
+		symbol: ">", kind: operatorKindOrdering,
+		minimum: 2, maximum: 2 },
+	lexer.TokenKindGreaterThanEqualTo: {
+		symbol: ">=", kind: operatorKindOrdering,
+		minimum: 2, maximum: 2 },
+}
+
+// OperatorPhrase represents a phrase that performs an operation on its
+// arguments and results in a new value, such as [+ x y] or [== x y].
+type OperatorPhrase struct {
+	phraseBase
+	operator  lexer.TokenKind
+	arguments []Argument
+	what      Type
+
+	// width is the size of the result in bits if it is an integer, and is
+	// used to detect overflow when the phrase is evaluated at compile time.
+	// it is zero if the result is untyped or not an integer.
+	width uint64
+
+	// untyped is true if all of the operands are untyped constants. If so,
+	// the phrase can be passed to any type its operands can be passed to.
+	untyped bool
+
+	// operandType is the type that the operands have in common.
+	operandType Type
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase OperatorPhrase) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "operatorPhrase ",
+		operators[phrase.operator].symbol, "\n")
+	output += phrase.what.ToString(indent + 1)
+
+	for _, argument := range phrase.arguments {
+		output += argument.ToString(indent + 1)
+	}
+	return
+}
+
+// What returns the type that the operation results in.
+func (phrase OperatorPhrase) What () (what Type) {
+	what = phrase.what
+	return
+}
+
+// Operator returns the operator of the phrase.
+func (phrase OperatorPhrase) Operator () (operator lexer.TokenKind) {
+	operator = phrase.operator
+	return
+}
+
+// Arguments returns the operands of the phrase.
+func (phrase OperatorPhrase) Arguments () (arguments []Argument) {
+	arguments = phrase.arguments
+	return
+}
+
+// OperandType returns the type that the operands are operated on as. For shift
+// operators, this is only the type of the value being shifted.
+func (phrase OperatorPhrase) OperandType () (what Type) {
+	what = phrase.operandType
+	return
+}
+
+// Untyped returns whether all of the operands are untyped constants.
+func (phrase OperatorPhrase) Untyped () (untyped bool) {
+	untyped = phrase.untyped
+	return
+}
+
+// Equals returns whether the phrase is equal to the specified value. This is
+// always false, because phrases are not constant.
+func (phrase OperatorPhrase) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because phrases are not constant.
+func (phrase OperatorPhrase) Value () (value any) {
+	return
+}
+
+// Resolve evaluates the operation at compile time, if all of its operands are
+// constant.
+func (phrase OperatorPhrase) Resolve () (constant Argument, err error) {
+	operands := make([]constantValue, len(phrase.arguments))
+	for index, argument := range phrase.arguments {
+		operands[index], err = resolveConstant(argument)
+		if err != nil { return }
+	}
+
+	result, err := evaluateOperator (
+		phrase.operator,
+		operands,
+		phrase.what,
+		phrase.width,
+		phrase.locatable)
+	if err != nil { return }
+
+	constant, err = result.literal(phrase.location)
+	return
+}
+
+// canBePassedAs returns true if the result of the operation can be passed to
+// the specified type. If all of the operands are untyped constants, this will
+// be true for any type that the operands can be passed to and that the
+// operator accepts.
+func (phrase OperatorPhrase) canBePassedAs (what Type) (allowed bool) {
+	if !phrase.untyped {
+		allowed = phrase.what.canBePassedAs(what)
+		return
+	}
+
+	info := operators[phrase.operator]
+	switch info.kind {
+	case operatorKindArithmetic:
+		if !what.isNumeric() { return }
+		if info.integerOnly && !what.isInteger() { return }
+	case operatorKindBitwise, operatorKindShift:
+		if !what.isInteger() { return }
+	}
+
+	operands := phrase.arguments
+	if info.kind == operatorKindShift {
+		// the shift amount does not affect the result type
+		operands = operands[:1]
+	}
+
+	for _, operand := range operands {
+		if !operand.canBePassedAs(what) { return }
+	}
+
+	allowed = true
+	return
+}
+
+// isUntyped returns whether or not an argument is a constant that does not yet
+// have a type of its own, and will instead take on the type of whatever it is
+// passed to.
+func isUntyped (argument Argument) (untyped bool) {
+	switch argument.(type) {
+	case
+		IntLiteral,
+		UIntLiteral,
+		FloatLiteral,
+		StringLiteral,
+		BoolLiteral:
+
+		untyped = true
+	case OperatorPhrase:
+		untyped = argument.(OperatorPhrase).untyped
+	}
+	return
+}
+
+// analyzeOperatorPhrase analyzes a phrase whose command is an operator. If the
+// operator stores its result in its first operand, an AssignPhrase is returned.
+// Otherwise, an OperatorPhrase is returned.
+func (analyzer *analysisOperation) analyzeOperatorPhrase (
+	base      phraseBase,
+	operator  lexer.TokenKind,
+	arguments []Argument,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	info, exists := operators[operator]
+	if !exists {
+		panic (
+			"invalid state: no typing rules for operator " +
+			operator.Describe())
+	}
+
+	// check the amount of operands
+	if len(arguments) < info.minimum {
+		err = base.NewError (
+			operatorArityErrorMessage(info),
+			infoerr.ErrorKindError)
+		return
+	}
+	if info.maximum > 0 && len(arguments) > info.maximum {
+		err = arguments[info.maximum].NewError (
+			operatorArityErrorMessage(info),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if info.assigns {
+		err = analyzer.checkAssignmentTarget(arguments[0])
+		if err != nil { return }
+	}
+
+	// find the type that the operands have in common, and make sure they
+	// are all compatible with it. the shift amount of a shift operation
+	// is handled separately because it does not need to be the same type
+	// as the value being shifted.
+	operands := arguments
+	if info.kind == operatorKindShift {
+		operands = arguments[:1]
+	}
+
+	var operandType Type
+	var untyped bool
+	if info.kind == operatorKindLogical {
+		for _, operand := range arguments {
+			err = checkBooleanOperand(info, operand)
+			if err != nil { return }
+		}
+		operandType = truthType()
+		untyped     = false
+	} else {
+		operandType, untyped, err = analyzer.unifyOperands (
+			info, operands)
+		if err != nil { return }
+	}
+
+	err = checkOperandType(info, operandType, operands[0])
+	if err != nil { return }
+
+	if info.kind == operatorKindShift {
+		err = checkShiftAmount(info, arguments[1])
+		if err != nil { return }
+	}
+
+	negates := operator == lexer.TokenKindMinus && len(arguments) == 1
+	if negates && !operandType.isSignedNumeric() {
+		err = arguments[0].NewError (
+			"cannot negate a value of unsigned type " +
+			operandType.Describe(),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if info.assigns {
+		phrase = AssignPhrase {
+			phraseBase: base,
+			operator:   operator,
+			target:     arguments[0],
+			arguments:  arguments[1:],
+		}
+		return
+	}
+
+	outputPhrase := OperatorPhrase {
+		phraseBase:  base,
+		operator:    operator,
+		arguments:   arguments,
+		operandType: operandType,
+	}
+
+	switch info.kind {
+	case
+		operatorKindArithmetic,
+		operatorKindBitwise,
+		operatorKindShift:
+
+		// the result of the operation is a new value, so it does not
+		// inherit the mutability of its operands
+		outputPhrase.what         = operandType
+		outputPhrase.what.mutable = false
+		outputPhrase.untyped      = untyped
+		if !untyped && operandType.isSingular() {
+			outputPhrase.width =
+				operandType.bitWidth(analyzer.wordSize())
+		}
+	default:
+		outputPhrase.what = truthType()
+	}
+
+	phrase = outputPhrase
+	return
+}
+
+// unifyOperands finds the type that a list of operands has in common. Each
+// typed operand must be of exactly the same type, and each untyped operand must
+// be able to be passed to that type. If all operands are untyped, a default
+// type is chosen and untyped is returned as true.
+func (analyzer *analysisOperation) unifyOperands (
+	info     operatorInfo,
+	operands []Argument,
+) (
+	what    Type,
+	untyped bool,
+	err     error,
+) {
+	// source is the index of the operand that the common type was taken
+	// from, so that mismatches can be reported in the order they were
+	// written in.
+	source := -1
+	for index, operand := range operands {
+		if isUntyped(operand) { continue }
+
+		if source < 0 {
+			source = index
+			what = operand.What()
+			continue
+		}
+
+		if operand.What().Equals(what) { continue }
+
+		operandWhat := operand.What()
+		mixesSign :=
+			operandWhat.isInteger() && what.isInteger() &&
+			operandWhat.isSignedNumeric() != what.isSignedNumeric()
+
+		if mixesSign {
+			err = operand.NewError (
+				"cannot mix signed and unsigned operands (" +
+				what.Describe() + " and " +
+				operandWhat.Describe() + ") without a cast",
+				infoerr.ErrorKindError)
+		} else {
+			err = operand.NewError (
+				operandMismatchErrorMessage (
+					info, what, operandWhat),
+				infoerr.ErrorKindError)
+		}
+		return
+	}
+
+	if source < 0 {
+		// every operand is an untyped constant, so there is nothing
+		// to infer the type from. use a sensible default.
+		untyped = true
+		source  = 0
+		what    = Type { actual: &PrimitiveInt, length: 1 }
+		for index, operand := range operands {
+			if operand.What().isBoolean() {
+				source = index
+				what   = truthType()
+				break
+			}
+			if operand.What().isFloat() {
+				source = index
+				what   = Type {
+					actual: &PrimitiveF64,
+					length: 1,
+				}
+				break
+			}
+		}
+	}
+
+	for index, operand := range operands {
+		if !isUntyped(operand) { continue }
+		if operand.canBePassedAs(what) {
+			err = analyzer.checkIntegerRange(operand, what, 0)
+			if err != nil { return }
+			analyzer.warnPrecisionLoss(operand, what)
+			continue
+		}
+
+		_, isNegative := operand.(IntLiteral)
+		_, isFloat    := operand.(FloatLiteral)
+		switch {
+		case isNegative && what.isNumeric() && !what.isSignedNumeric():
+			err = operand.NewError (
+				"negative literal cannot be used with " +
+				"unsigned operands of type " + what.Describe(),
+				infoerr.ErrorKindError)
+		case isFloat && what.isInteger():
+			err = operand.NewError (
+				"float literal cannot be used with integer " +
+				"operands of type " + what.Describe(),
+				infoerr.ErrorKindError)
+		case index < source:
+			err = operand.NewError (
+				operandMismatchErrorMessage (
+					info, operand.What(), what),
+				infoerr.ErrorKindError)
+		default:
+			err = operand.NewError (
+				operandMismatchErrorMessage (
+					info, what, operand.What()),
+				infoerr.ErrorKindError)
+		}
+		return
+	}
+
+	return
+}
+
+// checkOperandType makes sure that the type an operator is operating on is
+// allowed by that operator.
+func checkOperandType (
+	info     operatorInfo,
+	what     Type,
+	location Argument,
+) (
+	err error,
+) {
+	switch info.kind {
+	case operatorKindArithmetic:
+		if !what.isSingular() || !what.isNumeric() {
+			err = location.NewError (
+				"arithmetic operator " + info.symbol +
+				" requires numeric operands, but " +
+				what.Describe() + " is not numeric",
+				infoerr.ErrorKindError)
+		} else if info.integerOnly && !what.isInteger() {
+			err = location.NewError (
+				"operator " + info.symbol + " requires " +
+				"integer operands, but " + what.Describe() +
+				" is a floating point type",
+				infoerr.ErrorKindError)
+		}
+
+	case operatorKindBitwise, operatorKindShift:
+		if !what.isSingular() || !what.isInteger() {
+			err = location.NewError (
+				"bitwise operator " + info.symbol +
+				" requires integer operands, but " +
+				what.Describe() + " is not an integer type",
+				infoerr.ErrorKindError)
+		}
+
+	case operatorKindEquality:
+		comparable :=
+			what.length == 1 &&
+			(what.kind == TypeKindPointer ||
+			what.isSingular() && what.isNumeric() ||
+			what.isBoolean())
+		if !comparable {
+			err = location.NewError (
+				"values of type " + what.Describe() +
+				" cannot be compared with " + info.symbol,
+				infoerr.ErrorKindError)
+		}
+
+	case operatorKindOrdering:
+		if !what.isSingular() || !what.isNumeric() {
+			err = location.NewError (
+				"ordering operator " + info.symbol +
+				" requires numeric operands, but " +
+				what.Describe() + " is not numeric",
+				infoerr.ErrorKindError)
+		}
+	}
+	return
+}
+
+// checkBooleanOperand makes sure that an operand of a logical operator can be
+// used as a truth value.
+func checkBooleanOperand (
+	info    operatorInfo,
+	operand Argument,
+) (
+	err error,
+) {
+	if isUntyped(operand) {
+		if operand.canBePassedAs(truthType()) { return }
+	} else if operand.What().isBoolean() {
+		return
+	}
+
+	err = operand.NewError (
+		"logical operator " + info.symbol + " requires operands " +
+		"that can be used as truth values, but " +
+		operand.What().Describe() + " cannot",
+		infoerr.ErrorKindError)
+	return
+}
+
+// checkShiftAmount makes sure that the amount a shift operator is shifting by
+// is a non-negative integer.
+func checkShiftAmount (
+	info   operatorInfo,
+	amount Argument,
+) (
+	err error,
+) {
+	_, isNegative := amount.(IntLiteral)
+	if isNegative {
+		err = amount.NewError (
+			"shift amount cannot be negative",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if isUntyped(amount) {
+		unsigned := Type { actual: &PrimitiveUInt, length: 1 }
+		if amount.canBePassedAs(unsigned) { return }
+	} else if amount.What().isSingular() && amount.What().isInteger() {
+		return
+	}
+
+	err = amount.NewError (
+		"shift operator " + info.symbol + " requires an integer " +
+		"shift amount, but " + amount.What().Describe() +
+		" is not an integer type",
+		infoerr.ErrorKindError)
+	return
+}
+
+// truthType returns the type that comparison and logical operations result
+// in.
+func truthType () (what Type) {
+	what = Type { actual: &PrimitiveBool, length: 1 }
+	return
+}
+
+// operatorArityErrorMessage describes how many operands an operator takes.
+func operatorArityErrorMessage (info operatorInfo) (message string) {
+	message = "operator " + info.symbol + " expects "
+	switch {
+	case info.minimum == info.maximum:
+		message += fmt.Sprint("exactly ", info.minimum)
+	case info.maximum == 0:
+		message += fmt.Sprint("at least ", info.minimum)
+	default:
+		message += fmt.Sprint (
+			"between ", info.minimum, " and ", info.maximum)
+	}
+
+	if info.minimum == 1 && info.maximum == 1 {
+		message += " operand"
+	} else {
+		message += " operands"
+	}
+	return
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestOperators (test *testing.T) {
+	checkTree ("../tests/analyzer/operators", false,
+`dataSection ro ../tests/analyzer/operators.aByte
+	type 1 basic U8
+	uintLiteral 6
+funcSection ro ../tests/analyzer/operators.bArithmetic
+	input x
+		type 1 basic Int
+	input y
+		type 1 basic Int
+	output z
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable z
+			operatorPhrase +
+				type 1 basic Int
+				operatorPhrase *
+					type 1 basic Int
+					uintLiteral 250
+					uintLiteral 0
+				uintLiteral 98
+				variable x
+				operatorPhrase /
+					type 1 basic Int
+					uintLiteral 9832
+					variable y
+				uintLiteral 930
+		assignPhrase =
+			variable z
+			operatorPhrase -
+				type 1 basic Int
+				variable x
+		assignPhrase =
+			variable z
+			operatorPhrase %
+				type 1 basic Int
+				variable x
+				uintLiteral 3
+		assignPhrase ++
+			variable z
+		assignPhrase --
+			variable z
+		assignPhrase %=
+			variable z
+			uintLiteral 3
+funcSection ro ../tests/analyzer/operators.cBitwise
+	input x
+		type 1 basic U8
+	output y
+		type 1 basic U8
+	block
+		assignPhrase =
+			variable y
+			operatorPhrase &
+				type 1 basic U8
+				variable x
+				operatorPhrase |
+					type 1 basic U8
+					uintLiteral 15
+					data ../tests/analyzer/operators.aByte
+		assignPhrase =
+			variable y
+			operatorPhrase ~
+				type 1 basic U8
+				variable x
+		assignPhrase =
+			variable y
+			operatorPhrase <<
+				type 1 basic U8
+				variable x
+				uintLiteral 4
+		assignPhrase =
+			variable y
+			operatorPhrase >>
+				type 1 basic U8
+				variable x
+				uintLiteral 1
+		assignPhrase <<=
+			variable y
+			uintLiteral 2
+		assignPhrase ^=
+			variable y
+			variable x
+		assignPhrase ~=
+			variable y
+funcSection ro ../tests/analyzer/operators.dComparison
+	input x
+		type 1 basic Int
+	input y
+		type 1 basic Int
+	output z
+		type 1 basic Bool
+	block
+		assignPhrase =
+			variable z
+			operatorPhrase <
+				type 1 basic Bool
+				variable x
+				variable y
+		assignPhrase =
+			variable z
+			operatorPhrase ==
+				type 1 basic Bool
+				variable x
+				uintLiteral 4
+		assignPhrase =
+			variable z
+			operatorPhrase &&
+				type 1 basic Bool
+				operatorPhrase >=
+					type 1 basic Bool
+					variable x
+					uintLiteral 0
+				operatorPhrase !=
+					type 1 basic Bool
+					variable y
+					uintLiteral 0
+		assignPhrase =
+			variable z
+			operatorPhrase !
+				type 1 basic Bool
+				variable z
+`, test)
+}
+
+func TestOperatorMixedSign (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/operatorMixedSign",
+		infoerr.ErrorKindError,
+		"cannot mix signed and unsigned operands (Int and UInt) " +
+		"without a cast",
+		8, 10, test)
+}
+
+func TestOperatorArity (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/operatorArity",
+		infoerr.ErrorKindError,
+		"operator % expects exactly 2 operands",
+		7, 12, test)
+}
+
+func TestOperatorListOperand (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/listOperand",
+		infoerr.ErrorKindError,
+		"list arguments are not supported yet",
+		6, 8, test)
+}
+
+func TestOperatorMismatch (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/operatorMismatch",
+		infoerr.ErrorKindError,
+		"operator + cannot mix operands of type U64 and Bool",
+		6, 8, test)
+}
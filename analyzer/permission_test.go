@@ -0,0 +1,127 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestPermissions (test *testing.T) {
+	checkTree ("../tests/analyzer/permissions", false,
+`typeSection ro ../tests/analyzer/permissions/required.aBird
+	type 1 basic Obj
+	member pv secret
+		type 1 basic Int
+	member ro wing
+		type 1 basic Int
+		uintLiteral 2
+	member rw beak
+		type 1 basic Int
+funcSection ro ../tests/analyzer/permissions/required.aBird_bFly
+	receiver bird
+		type 1 pointer {
+			type 1 mutable basic aBird
+		}
+	external
+funcSection rw ../tests/analyzer/permissions/required.aBird_cSing
+	receiver bird
+		type 1 pointer {
+			type 1 basic aBird
+		}
+	output volume
+		type 1 basic Int
+	external
+dataSection rw ../tests/analyzer/permissions/required.dCount
+	type 1 mutable basic Int
+dataSection ro ../tests/analyzer/permissions/required.eLimit
+	type 1 mutable basic Int
+typeSection ro ../tests/analyzer/permissions.aParrot
+	type 1 basic aBird
+	member ro beak
+		type 1 basic Int
+		uintLiteral 4
+funcSection ro ../tests/analyzer/permissions.aParrot_cSing
+	receiver parrot
+		type 1 pointer {
+			type 1 basic aParrot
+		}
+	output volume
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable volume
+			uintLiteral 5
+funcSection ro ../tests/analyzer/permissions.bUse
+	input bird
+		type 1 mutable basic aBird
+	output wing
+		type 1 basic Int
+	block
+		assignPhrase =
+			variable wing
+			member wing
+				variable bird
+		assignPhrase =
+			member beak
+				variable bird
+			uintLiteral 3
+		assignPhrase ++
+			data ../tests/analyzer/permissions/required.dCount
+		assignPhrase =
+			variable wing
+			data ../tests/analyzer/permissions/required.eLimit
+		callPhrase ../tests/analyzer/permissions/required.aBird_bFly
+			receiver
+				variable bird
+`, test)
+}
+
+func TestPrivateMember (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/privateMember",
+		infoerr.ErrorKindError,
+		"member \"secret\" is private (pv), and cannot be accessed " +
+		"outside of its module",
+		8, 10, test)
+}
+
+func TestReadOnlyMember (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/readOnlyMember",
+		infoerr.ErrorKindError,
+		"this is read-only (ro) outside of its module, and cannot be " +
+		"modified here",
+		7, 3, test)
+}
+
+func TestReadOnlyData (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/readOnlyData",
+		infoerr.ErrorKindError,
+		"this is read-only (ro) outside of its module, and cannot be " +
+		"modified here",
+		6, 4, test)
+}
+
+func TestOverrideReadOnly (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/overrideReadOnly",
+		infoerr.ErrorKindError,
+		"inherited method bFly is not read-write (rw) in parent " +
+		"type, and cannot be overridden here",
+		6, 0, test)
+}
+
+func TestRelaxMember (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/relaxMember",
+		infoerr.ErrorKindError,
+		"cannot relax permission of inherited member",
+		5, 1, test)
+}
+
+func TestReadWriteFunc (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/readWriteFunc",
+		infoerr.ErrorKindError,
+		"read-write (rw) permission not understood in this " +
+		"context, try read-only (ro)",
+		3, 0, test)
+}
@@ -2,6 +2,7 @@ package analyzer
 
 import "regexp"
 import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
 import "git.tebibyte.media/arf/arf/parser"
 import "git.tebibyte.media/arf/arf/infoerr"
 
@@ -23,8 +24,8 @@ type ArbitraryPhrase struct {
 }
 
 func (phrase ArbitraryPhrase) ToString (indent int) (output string) {
-	output += doIndent(indent, "phrase\n")
-	output += doIndent(indent + 1, phrase.command, "\n")
+	output += doIndent(indent, "arbitraryPhrase\n")
+	output += doIndent(indent + 1, "command '", phrase.command, "'\n")
 
 	for _, argument := range phrase.arguments {
 		output += argument.ToString(indent + 1)
@@ -33,12 +34,119 @@ func (phrase ArbitraryPhrase) ToString (indent int) (output string) {
 	return
 }
 
-type CastPhrase struct {
+// Command returns the name of what the phrase runs.
+func (phrase ArbitraryPhrase) Command () (command string) {
+	command = phrase.command
+	return
+}
+
+// Arguments returns the arguments passed to the command.
+func (phrase ArbitraryPhrase) Arguments () (arguments []Argument) {
+	arguments = phrase.arguments
+	return
+}
+
+// AssignPhrase represents a phrase that stores a value in its target. This
+// includes plain assignment with =, as well as operators like ++ and <<= that
+// modify their first operand.
+type AssignPhrase struct {
 	phraseBase
-	command Argument
+	operator  lexer.TokenKind
+	target    Argument
 	arguments []Argument
 }
 
+// ToString returns all data stored within the phrase, in string form.
+func (phrase AssignPhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "assignPhrase ")
+	if phrase.operator == lexer.TokenKindAssignment {
+		output += "="
+	} else {
+		output += operators[phrase.operator].symbol
+	}
+	output += "\n"
+
+	output += phrase.target.ToString(indent + 1)
+	for _, argument := range phrase.arguments {
+		output += argument.ToString(indent + 1)
+	}
+	return
+}
+
+// Operator returns the operator of the phrase. For plain assignment, this is
+// lexer.TokenKindAssignment.
+func (phrase AssignPhrase) Operator () (operator lexer.TokenKind) {
+	operator = phrase.operator
+	return
+}
+
+// Target returns where the value is stored.
+func (phrase AssignPhrase) Target () (target Argument) {
+	target = phrase.target
+	return
+}
+
+// Arguments returns the values that are assigned to or combined with the
+// target.
+func (phrase AssignPhrase) Arguments () (arguments []Argument) {
+	arguments = phrase.arguments
+	return
+}
+
+// ReferencePhrase represents a phrase that gets the location of a value,
+// creating a pointer to it.
+type ReferencePhrase struct {
+	phraseBase
+	value Argument
+	what  Type
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase ReferencePhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "referencePhrase\n")
+	output += phrase.what.ToString(indent + 1)
+	output += phrase.value.ToString(indent + 1)
+	return
+}
+
+// What returns the type of the pointer that the phrase creates.
+func (phrase ReferencePhrase) What () (what Type) {
+	what = phrase.what
+	return
+}
+
+// Operand returns the value whose location is taken.
+func (phrase ReferencePhrase) Operand () (operand Argument) {
+	operand = phrase.value
+	return
+}
+
+// Equals returns whether the phrase is equal to the specified value. This is
+// always false, because locations are not known at compile time.
+func (phrase ReferencePhrase) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because locations are not known at compile time.
+func (phrase ReferencePhrase) Value () (value any) {
+	return
+}
+
+// Resolve returns an error, because locations are not known at compile time.
+func (phrase ReferencePhrase) Resolve () (constant Argument, err error) {
+	err = phrase.NewError (
+		"location cannot be evaluated at compile time",
+		infoerr.ErrorKindError)
+	return
+}
+
+// canBePassedAs returns true if the pointer can be passed to the specified
+// type.
+func (phrase ReferencePhrase) canBePassedAs (what Type) (allowed bool) {
+	allowed = phrase.What().canBePassedAs(what)
+	return
+}
+
 // TODO more phrases lol
 
 func (analyzer *analysisOperation) analyzePhrase (
@@ -50,18 +158,39 @@ func (analyzer *analysisOperation) analyzePhrase (
 	base := phraseBase { }
 	base.location = inputPhrase.Location()
 
+	// the last argument of a cast phrase is a type, not a value, so it is
+	// analyzed separately
+	length := inputPhrase.Length()
+	kind   := inputPhrase.Kind()
+	if kind == parser.PhraseKindCast && length > 0 {
+		length --
+	}
+
 	arguments := []Argument { }
-	for index := 0; index < inputPhrase.Length(); index ++ {
+	for index := 0; index < length; index ++ {
 		inputArgument := inputPhrase.Argument(index)
-		
+
 		var argument Argument
 		argument, err = analyzer.analyzeArgument(inputArgument)
 		if err != nil { return }
-		
+
 		arguments = append(arguments, argument)
 	}
 
-	switch inputPhrase.Kind() {
+	if kind != parser.PhraseKindCall && inputPhrase.ReturneesLength() > 0 {
+		err = inputPhrase.Returnee(0).NewError (
+			"only function calls can have a return direction",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	switch kind {
+	case parser.PhraseKindCall:
+		phrase, err = analyzer.analyzeCallPhrase (
+			base,
+			inputPhrase,
+			arguments)
+
 	case parser.PhraseKindArbitrary:
 		command := inputPhrase.Command().Value().(string)
 		if !validNameRegex.Match([]byte(command)) {
@@ -79,9 +208,190 @@ func (analyzer *analysisOperation) analyzePhrase (
 			arguments:  arguments,
 		}
 		phrase = outputPhrase
-		
+
+	case parser.PhraseKindOperator:
+		phrase, err = analyzer.analyzeOperatorPhrase (
+			base,
+			inputPhrase.Operator(),
+			arguments)
+
+	case parser.PhraseKindAssign:
+		phrase, err = analyzer.analyzeAssignPhrase(base, arguments)
+
+	case parser.PhraseKindReference:
+		phrase, err = analyzer.analyzeReferencePhrase(base, arguments)
+
+	case parser.PhraseKindCast:
+		phrase, err = analyzer.analyzeCastPhrase (
+			base,
+			inputPhrase,
+			arguments)
+
+	case
+		parser.PhraseKindSwitch,
+		parser.PhraseKindIf,
+		parser.PhraseKindWhile,
+		parser.PhraseKindFor,
+		parser.PhraseKindDefer:
+
+		// control flow phrases are analyzed by analyzeBlock
+		err = inputPhrase.NewError (
+			"control flow phrases cannot be used as values",
+			infoerr.ErrorKindError)
+
+	case parser.PhraseKindCase:
+		err = inputPhrase.NewError (
+			"cases can only be used directly after a switch",
+			infoerr.ErrorKindError)
+
+	case parser.PhraseKindElseIf, parser.PhraseKindElse:
+		err = inputPhrase.NewError (
+			"elseif and else can only be used directly after an if",
+			infoerr.ErrorKindError)
+
 	default:
 		panic("phrase kind not implemented")
 	}
 	return
 }
+
+// analyzeAssignPhrase analyzes a phrase of the form [= target value].
+func (analyzer *analysisOperation) analyzeAssignPhrase (
+	base      phraseBase,
+	arguments []Argument,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	if len(arguments) != 2 {
+		err = base.NewError (
+			"assignment expects exactly one target and one value",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	target := arguments[0]
+	value  := arguments[1]
+
+	err = analyzer.checkAssignmentTarget(target)
+	if err != nil { return }
+
+	err = analyzer.typeCheck(value, target.What())
+	if err != nil { return }
+
+	phrase = AssignPhrase {
+		phraseBase: base,
+		operator:   lexer.TokenKindAssignment,
+		target:     target,
+		arguments:  []Argument { value },
+	}
+	return
+}
+
+// analyzeReferencePhrase analyzes a phrase of the form [loc value]. The
+// resulting pointer only points to mutable data if the value is writable.
+func (analyzer *analysisOperation) analyzeReferencePhrase (
+	base      phraseBase,
+	arguments []Argument,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	if len(arguments) != 1 {
+		err = base.NewError (
+			"loc expects exactly one value",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	value := arguments[0]
+	if _, isStorage := value.(storage); !isStorage {
+		err = value.NewError (
+			"cannot get the location of this, because it is not " +
+			"stored anywhere",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	points := value.What()
+	points.mutable = isWritable(value)
+
+	phrase = ReferencePhrase {
+		phraseBase: base,
+		value:      value,
+		what: Type {
+			kind:   TypeKindPointer,
+			length: 1,
+			points: &points,
+		},
+	}
+	return
+}
+
+// storage is an argument that refers to a place where a value is stored, such
+// as a variable or a data section.
+type storage interface {
+	Argument
+	writable () (writable bool)
+}
+
+// restrictedStorage is storage that may be read-only depending on which module
+// it is being accessed from.
+type restrictedStorage interface {
+	storage
+	readOnly () (readOnly bool)
+}
+
+// isWritable returns whether or not an argument refers to a place where a value
+// is stored that is allowed to be modified.
+func isWritable (argument Argument) (writable bool) {
+	place, isStorage := argument.(storage)
+	writable = isStorage && place.writable()
+	return
+}
+
+// canModifyThrough returns whether an object can be modified through the
+// argument it is accessed by. If the argument is a pointer, the data it points
+// to must be mutable. Otherwise, the argument itself must be writable.
+func canModifyThrough (argument Argument) (allowed bool) {
+	what := argument.What()
+	if what.kind == TypeKindPointer && what.length == 1 {
+		allowed = what.points.mutable
+	} else {
+		allowed = isWritable(argument)
+	}
+	return
+}
+
+// checkAssignmentTarget makes sure that an argument refers to something that
+// is able to store a value, and is allowed to be modified.
+func (analyzer *analysisOperation) checkAssignmentTarget (
+	target Argument,
+) (
+	err error,
+) {
+	place, isStorage := target.(storage)
+	if !isStorage {
+		err = target.NewError (
+			"cannot assign a value to this",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	restricted, isRestricted := target.(restrictedStorage)
+	if isRestricted && restricted.readOnly() {
+		err = target.NewError (
+			"this is read-only (ro) outside of its module, and " +
+			"cannot be modified here",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if !place.writable() {
+		err = target.NewError (
+			"cannot modify this, because it is not mutable",
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
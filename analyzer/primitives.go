@@ -38,16 +38,17 @@ var PrimitiveU32  = createPrimitive("U32",  Type { length: 1 })
 // PrimitiveI64 is an unsigned 64 bit integer primitive.
 var PrimitiveU64  = createPrimitive("U64",  Type { length: 1 })
 
+// PrimitiveBool is a boolean primitive. It can only be true or false.
+var PrimitiveBool = createPrimitive("Bool", Type { length: 1 })
+
 // PrimitiveObj is a blank object primitive.
 var PrimitiveObj  = createPrimitive("Obj",  Type { length: 1 })
 
-// TODO: make these two be interface sections
-
 // PrimitiveFace is a blank interface primitive. It accepts any value.
-// var PrimitiveFace = createPrimitive("Face", Type {})
+var PrimitiveFace = createFacePrimitive("Face", FaceKindType)
 
 // PrimitiveFunc is a blank function interface primitive. It is useless.
-// var PrimitiveFunc = createPrimitive("Func", Type {})
+var PrimitiveFunc = createFacePrimitive("Func", FaceKindFunc)
 
 // BuiltInString is a built in string type. It is a dynamic array of UTF-32
 // codepoints.
@@ -67,3 +68,11 @@ func createPrimitive (name string, inherits Type) (primitive TypeSection) {
 	primitive.what  = inherits
 	return
 }
+
+// createFacePrimitive provides a quick way to construct an interface primitive
+// for the above list.
+func createFacePrimitive (name string, kind FaceKind) (primitive FaceSection) {
+	primitive.where = locator { name: name }
+	primitive.kind  = kind
+	return
+}
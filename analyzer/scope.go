@@ -0,0 +1,95 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// scope stores the variables declared within a block.
+type scope struct {
+	variables map[string] *Variable
+}
+
+// lookupVariable returns the variable under the given name if it exists within
+// this scope, and nil if it does not.
+func (scope scope) lookupVariable (name string) (variable *Variable) {
+	variable = scope.variables[name]
+	return
+}
+
+// addVariable adds a variable to the scope.
+func (scope *scope) addVariable (variable *Variable) {
+	if scope.variables == nil {
+		scope.variables = make(map[string] *Variable)
+	}
+	scope.variables[variable.name] = variable
+}
+
+// pushScope pushes a new scope onto the analyzer's scope stack. Variables
+// defined from this point on will be put into it.
+func (analyzer *analysisOperation) pushScope (into *scope) {
+	analyzer.currentScopes.Push(into)
+}
+
+// popScope removes the innermost scope from the analyzer's scope stack.
+func (analyzer *analysisOperation) popScope () {
+	analyzer.currentScopes.Pop()
+}
+
+// lookupVariable searches all scopes, starting with the closest and ending with
+// the farthest, for a variable with the specified name. If none is found, nil
+// is returned.
+func (analyzer *analysisOperation) lookupVariable (
+	name string,
+) (
+	variable *Variable,
+) {
+	for index := len(analyzer.currentScopes) - 1; index >= 0; index -- {
+		variable = analyzer.currentScopes[index].lookupVariable(name)
+		if variable != nil { return }
+	}
+	return
+}
+
+// defineVariable adds a variable to the innermost scope. New variables are not
+// allowed to shadow anything else that is accessible from the current scope,
+// so if the name is already taken an error is returned.
+func (analyzer *analysisOperation) defineVariable (
+	variable *Variable,
+) (
+	err error,
+) {
+	if len(analyzer.currentScopes) < 1 {
+		panic (
+			"invalid state: attempt to define variable " +
+			variable.name + " outside of any scope")
+	}
+
+	taken := analyzer.lookupVariable(variable.name) != nil
+
+	// variables cannot shadow sections or required modules either
+	if !taken {
+		_, taken = analyzer.currentTree.ResolveRequire(variable.name)
+	}
+	if !taken {
+		section := analyzer.currentTree.LookupSection("", variable.name)
+		taken = section != nil
+	}
+	if !taken {
+		taken = variable.name == "true" || variable.name == "false"
+	}
+	if !taken {
+		_, taken = analyzer.resolvePrimitive (locator {
+			modulePath: analyzer.currentPosition.modulePath,
+			name:       variable.name,
+		})
+	}
+
+	if taken {
+		err = variable.NewError (
+			"there is already something called \"" +
+			variable.name + "\" within the current scope",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	analyzer.currentScopes.Top().addVariable(variable)
+	return
+}
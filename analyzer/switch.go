@@ -0,0 +1,244 @@
+package analyzer
+
+import "strings"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// SwitchPhrase represents a phrase that runs one of the cases beneath it
+// depending on the value of its subject.
+type SwitchPhrase struct {
+	phraseBase
+	subject Argument
+	cases   []CasePhrase
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase SwitchPhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "switchPhrase\n")
+	output += phrase.subject.ToString(indent + 1)
+	for _, casePhrase := range phrase.cases {
+		output += casePhrase.ToString(indent + 1)
+	}
+	return
+}
+
+// Subject returns the value that the cases are compared against.
+func (phrase SwitchPhrase) Subject () (subject Argument) {
+	subject = phrase.subject
+	return
+}
+
+// Cases returns the cases of the switch, in order.
+func (phrase SwitchPhrase) Cases () (cases []CasePhrase) {
+	cases = phrase.cases
+	return
+}
+
+// CasePhrase represents a single case of a switch phrase. A case without any
+// values is the default case, and is run when none of the other cases match.
+type CasePhrase struct {
+	phraseBase
+	values []Argument
+	block  Block
+}
+
+// ToString returns all data stored within the phrase, in string form.
+func (phrase CasePhrase) ToString (indent int) (output string) {
+	output += doIndent(indent, "casePhrase")
+	if phrase.IsDefault() {
+		output += " default"
+	}
+	output += "\n"
+	for _, value := range phrase.values {
+		output += value.ToString(indent + 1)
+	}
+	output += phrase.block.ToString(indent + 1)
+	return
+}
+
+// IsDefault returns whether the case is the default case of its switch.
+func (phrase CasePhrase) IsDefault () (isDefault bool) {
+	isDefault = len(phrase.values) == 0
+	return
+}
+
+// Values returns the values that the case handles.
+func (phrase CasePhrase) Values () (values []Argument) {
+	values = phrase.values
+	return
+}
+
+// Block returns the block that is run if the case matches.
+func (phrase CasePhrase) Block () (block Block) {
+	block = phrase.block
+	return
+}
+
+// analyzeSwitchPhrase analyzes a phrase of the form [switch value], along with
+// its cases. The cases of a switch are not indented beneath it, but come
+// directly after it within the same block. If the value is an enum, every
+// member of the enum must be handled by a case unless there is a default case.
+func (analyzer *analysisOperation) analyzeSwitchPhrase (
+	inputPhrase parser.Phrase,
+	inputCases  []parser.Phrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	outputPhrase := SwitchPhrase { }
+	outputPhrase.location = inputPhrase.Location()
+
+	if inputPhrase.Length() != 1 {
+		err = outputPhrase.NewError (
+			"switch expects exactly one value",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if len(inputPhrase.Block()) > 0 {
+		err = inputPhrase.Block()[0].NewError (
+			"cases must be at the same level of indentation as " +
+			"their switch",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputPhrase.subject, err = analyzer.analyzeArgument (
+		inputPhrase.Argument(0))
+	if err != nil { return }
+	subjectType := outputPhrase.subject.What()
+
+	hasDefault := false
+	for _, inputCase := range inputCases {
+		outputCase := CasePhrase { }
+		outputCase.location = inputCase.Location()
+
+		for index := 0; index < inputCase.Length(); index ++ {
+			var value Argument
+			value, err = analyzer.analyzeArgument (
+				inputCase.Argument(index))
+			if err != nil { return }
+
+			err = analyzer.typeCheck(value, subjectType)
+			if err != nil { return }
+
+			outputCase.values = append(outputCase.values, value)
+		}
+
+		if outputCase.IsDefault() {
+			if hasDefault {
+				err = outputCase.NewError (
+					"switch can only have one default case",
+					infoerr.ErrorKindError)
+				return
+			}
+			hasDefault = true
+		}
+
+		outputCase.block, err = analyzer.analyzeBlock (
+			inputCase.Block())
+		if err != nil { return }
+
+		outputPhrase.cases = append(outputPhrase.cases, outputCase)
+	}
+
+	enum, isEnum := subjectType.actual.(*EnumSection)
+	if isEnum && subjectType.length == 1 {
+		err = outputPhrase.checkEnumCases(enum, hasDefault)
+		if err != nil { return }
+	}
+
+	phrase = outputPhrase
+	return
+}
+
+// checkEnumCases makes sure that every case value of a switch over an enum is
+// a member of that enum, that no member is handled more than once, and that
+// every member is handled if there is no default case.
+func (phrase SwitchPhrase) checkEnumCases (
+	enum       *EnumSection,
+	hasDefault bool,
+) (
+	err error,
+) {
+	handled := make([]bool, len(enum.members))
+
+	for _, casePhrase := range phrase.cases {
+		for _, value := range casePhrase.values {
+			var index int
+			index, err = enumMemberIndex(enum, value)
+			if err != nil { return }
+
+			if handled[index] {
+				err = value.NewError (
+					"enum member " +
+					enum.members[index].name +
+					" is already handled by another case",
+					infoerr.ErrorKindError)
+				return
+			}
+			handled[index] = true
+		}
+	}
+
+	if hasDefault { return }
+
+	missing := []string { }
+	for index, member := range enum.members {
+		if !handled[index] {
+			missing = append(missing, member.name)
+		}
+	}
+
+	if len(missing) > 0 {
+		err = phrase.NewError (
+			"switch does not handle every member of " +
+			phrase.subject.What().Describe() + ", missing: " +
+			strings.Join(missing, ", "),
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
+
+// enumMemberIndex finds which member of an enum a case value refers to. The
+// value can either be a reference to the member, or a constant that is equal
+// to the value of the member.
+func enumMemberIndex (
+	enum  *EnumSection,
+	value Argument,
+) (
+	index int,
+	err   error,
+) {
+	reference, isReference := value.(EnumMemberReference)
+	if isReference && reference.section == enum {
+		for index = range enum.members {
+			if enum.members[index].name == reference.member.name {
+				return
+			}
+		}
+	}
+
+	if !isConstant(value) {
+		err = value.NewError (
+			"case value must be known at compile time",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	literal, err := value.Resolve()
+	if err != nil { return }
+
+	for index = range enum.members {
+		if enum.members[index].argument.Equals(literal.Value()) {
+			return
+		}
+	}
+
+	err = value.NewError (
+		"case value is not a member of " + enum.ModuleName() + "." +
+		enum.Name(),
+		infoerr.ErrorKindError)
+	return
+}
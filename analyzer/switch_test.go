@@ -0,0 +1,109 @@
+package analyzer
+
+import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func TestSwitchPhrase (test *testing.T) {
+	checkTree ("../tests/analyzer/switchPhrase", false,
+`enumSection ro ../tests/analyzer/switchPhrase.aDirection
+	uintLiteral 0
+	type 1 basic U8
+	member north
+		uintLiteral 0
+	member east
+		uintLiteral 1
+	member south
+		uintLiteral 2
+	member west
+		uintLiteral 3
+funcSection ro ../tests/analyzer/switchPhrase.bEvery
+	input direction
+		type 1 basic aDirection
+	output x
+		type 1 basic Int
+	block
+		switchPhrase
+			variable direction
+			casePhrase
+				enumMember ../tests/analyzer/switchPhrase.aDirection.north
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 1
+			casePhrase
+				enumMember ../tests/analyzer/switchPhrase.aDirection.east
+				enumMember ../tests/analyzer/switchPhrase.aDirection.west
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 2
+			casePhrase
+				uintLiteral 2
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 3
+funcSection ro ../tests/analyzer/switchPhrase.cDefault
+	input direction
+		type 1 basic aDirection
+	output x
+		type 1 basic Int
+	block
+		switchPhrase
+			variable direction
+			casePhrase
+				enumMember ../tests/analyzer/switchPhrase.aDirection.north
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 1
+			casePhrase default
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 0
+funcSection ro ../tests/analyzer/switchPhrase.dNumber
+	input number
+		type 1 basic Int
+	output x
+		type 1 basic Int
+	block
+		switchPhrase
+			variable number
+			casePhrase
+				uintLiteral 5
+				operatorPhrase +
+					type 1 basic Int
+					uintLiteral 3
+					uintLiteral 3
+				block
+					assignPhrase =
+						variable x
+						uintLiteral 1
+`, test)
+}
+
+func TestSwitchMissing (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/switchMissing",
+		infoerr.ErrorKindError,
+		"switch does not handle every member of " +
+		"switchMissing.aDirection, missing: east, west",
+		11, 1, test)
+}
+
+func TestSwitchDuplicate (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/switchDuplicate",
+		infoerr.ErrorKindError,
+		"enum member north is already handled by another case",
+		12, 19, test)
+}
+
+func TestSwitchNotMember (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/switchNotMember",
+		infoerr.ErrorKindError,
+		"case value is not a member of switchNotMember.aDirection",
+		12, 3, test)
+}
@@ -30,6 +30,16 @@ type SectionTable map[locator] Section
 
 // ToString returns the data stored in the table as a string.
 func (table SectionTable) ToString (indent int) (output string) {
+	for _, section := range table.Sorted() {
+		output += section.ToString(indent)
+	}
+
+	return
+}
+
+// Sorted returns all sections in the table, sorted by module path and then by
+// name.
+func (table SectionTable) Sorted () (sections []Section) {
 	sortedKeys := make(locatorArray, len(table))
 	index := 0
 	for key, _ := range table {
@@ -37,12 +47,10 @@ func (table SectionTable) ToString (indent int) (output string) {
 		index ++
 	}
 	sort.Sort(sortedKeys)
-	
+
 	for _, name := range sortedKeys {
-		section := table[name]
-		output += section.ToString(indent)
+		sections = append(sections, table[name])
 	}
-
 	return
 }
 
@@ -76,14 +84,16 @@ func (array locatorArray) Swap (left, right int) {
 // Section is a semantically analyzed section.
 type Section interface {
 	// Provided by sectionBase
-	Name       () (name string)
-	Complete   () (complete bool)
-	ModulePath () (path string)
-	ModuleName () (path string)
-	Permission () (permission types.Permission)
-	Location   () (location file.Location)
-	NewError   (message string, kind infoerr.ErrorKind) (err error)
-	locator    () (where locator)
+	Name        () (name string)
+	Complete    () (complete bool)
+	ModulePath  () (path string)
+	ModuleName  () (path string)
+	Permission  () (permission types.Permission)
+	Location    () (location file.Location)
+	NewError    (message string, kind infoerr.ErrorKind) (err error)
+	Warnings    () (warnings []infoerr.Error)
+	locator     () (where locator)
+	addWarnings (warnings []infoerr.Error)
 
 	// Must be implemented by each individual section
 	ToString (indent int) (output string)
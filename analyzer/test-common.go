@@ -3,11 +3,93 @@ package analyzer
 import "os"
 import "testing"
 import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/infoerr"
 import "git.tebibyte.media/arf/arf/testCommon"
 
 func checkTree (modulePath string, skim bool, correct string, test *testing.T) {
 	cwd, _ := os.Getwd()
 	modulePath = filepath.Join(cwd, modulePath)
-	table, err := Analyze(modulePath, skim)
+	table, err := Analyze(modulePath, skim, target.Default)
 	testCommon.CheckStrings(test, table, err, correct)
 }
+
+func checkError (
+	modulePath     string,
+	correctKind    infoerr.ErrorKind,
+	correctMessage string,
+	correctRow     int,
+	correctColumn  int,
+	test *testing.T,
+) {
+	checkErrorOn (
+		target.Default,
+		modulePath,
+		correctKind,
+		correctMessage,
+		correctRow,
+		correctColumn,
+		test)
+}
+
+func checkErrorOn (
+	platform       target.Target,
+	modulePath     string,
+	correctKind    infoerr.ErrorKind,
+	correctMessage string,
+	correctRow     int,
+	correctColumn  int,
+	test *testing.T,
+) {
+	test.Log("testing error in", modulePath, "on", platform.Name)
+	cwd, _ := os.Getwd()
+	modulePath = filepath.Join(cwd, modulePath)
+	table, err := Analyze(modulePath, false, platform)
+	check, isCorrectType := err.(infoerr.Error)
+
+	test.Log("RESULT:")
+	test.Log(table.ToString(0))
+
+	if err == nil {
+		test.Log("no error was recieved, test failed.")
+		test.Fail()
+		return
+	}
+
+	test.Log("error that was recieved:")
+	test.Log(err)
+
+	if !isCorrectType {
+		test.Log("error is not infoerr.Error, something has gone wrong.")
+		test.Fail()
+		return
+	}
+
+	if check.Kind() != correctKind {
+		test.Log("mismatched error kind")
+		test.Log("- want:", correctKind)
+		test.Log("- have:", check.Kind())
+		test.Fail()
+	}
+
+	if check.Message() != correctMessage {
+		test.Log("mismatched error message")
+		test.Log("- want:", correctMessage)
+		test.Log("- have:", check.Message())
+		test.Fail()
+	}
+
+	if check.Row() != correctRow {
+		test.Log("mismatched error row")
+		test.Log("- want:", correctRow)
+		test.Log("- have:", check.Row())
+		test.Fail()
+	}
+
+	if check.Column() != correctColumn {
+		test.Log("mismatched error column")
+		test.Log("- want:", correctColumn)
+		test.Log("- have:", check.Column())
+		test.Fail()
+	}
+}
@@ -22,6 +22,10 @@ type ObjectMember struct {
 
 	what Type
 	argument Argument
+
+	// the path of the module where the member was originally defined. this
+	// is used to decide whether its permission applies.
+	modulePath string
 }
 
 // ToString returns all data stored within the member, in string form.
@@ -43,6 +47,32 @@ func (member ObjectMember) ToString (indent int) (output string) {
 	return
 }
 
+// Name returns the name of the member.
+func (member ObjectMember) Name () (name string) {
+	name = member.name
+	return
+}
+
+// What returns the type of the member.
+func (member ObjectMember) What () (what Type) {
+	what = member.what
+	return
+}
+
+// Argument returns the default value of the member. If it does not have one,
+// this is nil.
+func (member ObjectMember) Argument () (argument Argument) {
+	argument = member.argument
+	return
+}
+
+// BitWidth returns the width of the member in bits if it is a bit field, and
+// zero if it is not.
+func (member ObjectMember) BitWidth () (width uint64) {
+	width = member.bitWidth
+	return
+}
+
 // ToString returns all data stored within the type section, in string form.
 func (section TypeSection) ToString (indent int) (output string) {
 	output += doIndent(indent, "typeSection ")
@@ -59,6 +89,25 @@ func (section TypeSection) ToString (indent int) (output string) {
 	return
 }
 
+// What returns the type that the type section inherits from.
+func (section TypeSection) What () (what Type) {
+	what = section.what
+	return
+}
+
+// Argument returns the default value of the type. If it does not have one,
+// this is nil.
+func (section TypeSection) Argument () (argument Argument) {
+	argument = section.argument
+	return
+}
+
+// Members returns every member of the type, including the ones it inherits.
+func (section *TypeSection) Members () (members []ObjectMember) {
+	members = section.allMembers()
+	return
+}
+
 // Member returns the membrs ksdn ,mn ,mxc lkzxjclkjxzc l,mnzc .,zxmn.,zxmc
 // IT RECURSES!
 func (section TypeSection) Member (
@@ -129,6 +178,9 @@ func (analyzer analysisOperation) analyzeTypeSection () (
 		outputSection.argument,
 		err = analyzer.analyzeArgument(inputSection.Argument())
 		if err != nil { return }
+		outputSection.argument,
+		err = analyzer.fold(outputSection.argument)
+		if err != nil { return }
 
 		// type check default value
 		err = analyzer.typeCheck (
@@ -200,7 +252,8 @@ func (analyzer *analysisOperation) analyzeObjectMembers (
 				return
 			}
 
-			outputMember.what = inheritedMember.what
+			outputMember.what       = inheritedMember.what
+			outputMember.modulePath = inheritedMember.modulePath
 			if !inputMember.Type().Nil() {
 				err = inputMember.NewError (
 					"cannot override type of " +
@@ -208,6 +261,17 @@ func (analyzer *analysisOperation) analyzeObjectMembers (
 					infoerr.ErrorKindError)
 				return
 			}
+
+			inheritedWidth := inheritedMember.bitWidth
+			if outputMember.bitWidth == 0 {
+				outputMember.bitWidth = inheritedWidth
+			} else if outputMember.bitWidth != inheritedWidth {
+				err = inputMember.NewError (
+					"cannot override bit width of " +
+					"inherited member",
+					infoerr.ErrorKindError)
+				return
+			}
 			
 			if outputMember.permission > inheritedMember.permission {
 				err = inputMember.NewError (
@@ -239,16 +303,26 @@ func (analyzer *analysisOperation) analyzeObjectMembers (
 				outputMember.argument,
 				err = analyzer.analyzeArgument(inputMember.Argument())
 				if err != nil { return }
+				outputMember.argument,
+				err = analyzer.fold(outputMember.argument)
+				if err != nil { return }
 
 				// type check default value
 				err = analyzer.typeCheck (
 					outputMember.argument,
 					outputMember.what)
 				if err != nil { return }
+
+				err = analyzer.checkIntegerRange (
+					outputMember.argument,
+					outputMember.what,
+					outputMember.bitWidth)
+				if err != nil { return }
 			}
 			
 		} else {
 			// defining a new member
+			outputMember.modulePath = into.where.modulePath
 			if inputMember.Type().Nil() {
 				err = inputMember.NewError (
 					"new members must be given a " +
@@ -260,18 +334,30 @@ func (analyzer *analysisOperation) analyzeObjectMembers (
 			outputMember.what, err = analyzer.analyzeType (
 				inputMember.Type())
 			if err != nil { return }
+
+			err = analyzer.checkBitWidth(inputMember, outputMember)
+			if err != nil { return }
 			
 			// apply default value
 			if !inputMember.Argument().Nil() {
 				outputMember.argument,
 				err = analyzer.analyzeArgument(inputMember.Argument())
 				if err != nil { return }
+				outputMember.argument,
+				err = analyzer.fold(outputMember.argument)
+				if err != nil { return }
 
 				// type check default value
 				err = analyzer.typeCheck (
 					outputMember.argument,
 					outputMember.what)
 				if err != nil { return }
+
+				err = analyzer.checkIntegerRange (
+					outputMember.argument,
+					outputMember.what,
+					outputMember.bitWidth)
+				if err != nil { return }
 			}
 		}
 
@@ -291,3 +377,34 @@ func (analyzer *analysisOperation) analyzeObjectMembers (
 	}
 	return
 }
+
+// checkBitWidth makes sure that a member with a bit width is an integer, and
+// that its bit width is not larger than the integer itself.
+func (analyzer *analysisOperation) checkBitWidth (
+	inputMember  parser.TypeSectionMember,
+	outputMember ObjectMember,
+) (
+	err error,
+) {
+	if outputMember.bitWidth == 0 { return }
+
+	what  := outputMember.what
+	width := what.bitWidth(analyzer.wordSize())
+	if !what.isSingular() || !what.isInteger() {
+		err = inputMember.NewError (
+			"only integer members can have a bit width",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if outputMember.bitWidth > width {
+		err = inputMember.NewError (
+			fmt.Sprint (
+				"bit width of ", outputMember.bitWidth,
+				" is larger than ", what.Describe(), ", which ",
+				"is only ", width, " bits wide"),
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
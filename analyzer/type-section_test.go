@@ -1,6 +1,7 @@
 package analyzer
 
 import "testing"
+import "git.tebibyte.media/arf/arf/infoerr"
 
 func TestTypeSection (test *testing.T) {
 	checkTree ("../tests/analyzer/typeSection", false,
@@ -45,5 +46,30 @@ typeSection ro ../tests/analyzer/typeSection.hDynamicArray
 	type 1 dynamicArray {
 		type 1 basic Int
 	}
+typeSection ro ../tests/analyzer/typeSection.iBitFields
+	type 1 basic Obj
+	member ro flag width 1
+		type 1 basic U8
+		uintLiteral 1
+	member ro mode width 3
+		type 1 basic I8
+		intLiteral -4
 `, test)
 }
+
+func TestBitFieldRange (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/bitFieldRange",
+		infoerr.ErrorKindError,
+		"literal 8 is out of range for a 3 bit field, which can only " +
+		"hold values from 0 to 7",
+		4, 12, test)
+}
+
+func TestBitWidthTooLarge (test *testing.T) {
+	checkError (
+		"../tests/analyzer/error/bitWidthTooLarge",
+		infoerr.ErrorKindError,
+		"bit width of 9 is larger than U8, which is only 8 bits wide",
+		4, 1, test)
+}
@@ -1,6 +1,7 @@
 package analyzer
 
 import "fmt"
+import "math/big"
 import "git.tebibyte.media/arf/arf/parser"
 import "git.tebibyte.media/arf/arf/infoerr"
 
@@ -30,7 +31,7 @@ type Type struct {
 	mutable bool
 	kind TypeKind
 
-	primitiveCache *TypeSection
+	primitiveCache Section
 	singularCache  *bool
 
 	// if this is greater than 1, it means that this is a fixed-length array
@@ -70,9 +71,50 @@ func (what Type) ToString (indent int) (output string) {
 	return
 }
 
+// Kind returns what kind of type the type is.
+func (what Type) Kind () (kind TypeKind) {
+	kind = what.kind
+	return
+}
+
+// Actual returns the section that a basic type refers to. For other kinds of
+// types, this is nil.
+func (what Type) Actual () (actual Section) {
+	actual = what.actual
+	return
+}
+
+// Points returns the type that a pointer or variable array points to. For basic
+// types, this is nil.
+func (what Type) Points () (points *Type) {
+	points = what.points
+	return
+}
+
+// Length returns the length of the type if it is a fixed length array, and one
+// if it is not.
+func (what Type) Length () (length uint64) {
+	length = what.length
+	return
+}
+
+// Primitive returns the primitive that the type eventually inherits from. If
+// the type ends up pointing to something, this is nil.
+func (what Type) Primitive () (primitive Section) {
+	primitive = what.underlyingPrimitive()
+	return
+}
+
+// Element returns the type of the elements of an array type. If the type is
+// not an array, isArray will be false.
+func (what Type) Element () (element Type, isArray bool) {
+	element, isArray = arrayElement(what)
+	return
+}
+
 // underlyingPrimitive returns the primitive that this type eventually inherits
 // from. If the type ends up pointing to something, this returns nil.
-func (what Type) underlyingPrimitive () (underlying *TypeSection) {
+func (what Type) underlyingPrimitive () (underlying Section) {
 	// if we have already done this operation, return the cahced result.
 	if what.primitiveCache != nil {
 		underlying = what.primitiveCache
@@ -100,9 +142,12 @@ func (what Type) underlyingPrimitive () (underlying *TypeSection) {
 		&PrimitiveI16,
 		&PrimitiveI8,
 		&PrimitiveUInt,
-		&PrimitiveInt:
+		&PrimitiveInt,
+		&PrimitiveBool,
+		&PrimitiveFace,
+		&PrimitiveFunc:
 
-		underlying = actual.(*TypeSection)
+		underlying = actual
 		return
 	
 	case nil:
@@ -119,10 +164,13 @@ func (what Type) underlyingPrimitive () (underlying *TypeSection) {
 				actual.(*TypeSection).
 				what.underlyingPrimitive()
 		
-		// case *FaceSection:
-			// TODO: depending on if this is an object interface or
-			// a function interface, return either Face or Func.
-			// we can assume this because of inheritence rules.
+		case *FaceSection:
+			// this will eventually be either Face or Func, because
+			// interfaces can only inherit from other interfaces of
+			// the same kind.
+			underlying =
+				actual.(*FaceSection).
+				what.underlyingPrimitive()
 			
 		case *EnumSection:
 			underlying =
@@ -183,6 +231,70 @@ func (what Type) isSignedNumeric () (signedNumeric bool) {
 	return
 }
 
+// isInteger returns whether or not the type descends from an integer
+// primitive.
+func (what Type) isInteger () (integer bool) {
+	integer = what.isNumeric() && !what.isFloat()
+	return
+}
+
+// bitWidth returns the amount of bits that an integer type takes up, given the
+// size of a word in bits. If the type is not an integer, zero is returned.
+func (what Type) bitWidth (wordSize uint64) (width uint64) {
+	switch what.underlyingPrimitive() {
+	case &PrimitiveI8,  &PrimitiveU8:
+		width = 8
+	case &PrimitiveI16, &PrimitiveU16:
+		width = 16
+	case &PrimitiveI32, &PrimitiveU32:
+		width = 32
+	case &PrimitiveI64, &PrimitiveU64:
+		width = 64
+	case &PrimitiveInt, &PrimitiveUInt:
+		width = wordSize
+	}
+	return
+}
+
+// integerRange returns the smallest and largest values that an integer type
+// can hold if it is the specified amount of bits wide.
+func (what Type) integerRange (width uint64) (minimum, maximum *big.Int) {
+	minimum = big.NewInt(0)
+	maximum = big.NewInt(1)
+	if what.isSignedNumeric() {
+		maximum.Lsh(maximum, uint(width - 1))
+		minimum.Neg(maximum)
+	} else {
+		maximum.Lsh(maximum, uint(width))
+	}
+	maximum.Sub(maximum, big.NewInt(1))
+	return
+}
+
+// isFloat returns whether or not the type descends from a floating point
+// primitive.
+func (what Type) isFloat () (float bool) {
+	primitive := what.underlyingPrimitive()
+	switch primitive {
+	case
+		&PrimitiveF64,
+		&PrimitiveF32:
+
+		float = true
+	}
+
+	return
+}
+
+// isBoolean returns whether or not the type descends from the boolean
+// primitive, and can therefore be used as a truth value.
+func (what Type) isBoolean () (boolean bool) {
+	boolean =
+		what.isSingular() &&
+		what.underlyingPrimitive() == &PrimitiveBool
+	return
+}
+
 // isSingular returns whether or not the type is a singular value. this goes
 // all the way up the inheritence chain, only stopping when it hits a non-basic
 // type because this is about data storage of a value.
@@ -193,24 +305,27 @@ func (what Type) isSingular () (singular bool) {
 		return
 	}
 
-	if what.length > 0 {
+	// fixed length arrays are not singular
+	if what.length > 1 { return }
+
+	// pointers and dynamic arrays are stored as a single value
+	if what.kind != TypeKindBasic {
 		singular = true
 		return
 	}
 
-	// decide whether or not to recurse
-	if what.kind != TypeKindBasic { return }
 	actual := what.actual
 	if actual == nil {
+		// we have reached the root of a primitive
+		singular = true
 		return
 	} else {
 		switch actual.(type) {
 		case *TypeSection:
 			singular = actual.(*TypeSection).what.isSingular()
 		
-		// TODO: uncomment this when this section has been implemented
-		// case *FaceSection:
-			// singular = true
+		case *FaceSection:
+			singular = true
 			
 		case *EnumSection:
 			singular = actual.(*EnumSection).what.isSingular()
@@ -250,9 +365,9 @@ func (what Type) reduce () (reduced Type, reducible bool) {
 	case *TypeSection:
 		reduced, reducible = what.actual.(*TypeSection).what.reduce()
 		
-	// TODO: uncomment this when his section has been implemented
-	// case *FaceSection:
-		// singular = true
+	case *FaceSection:
+		// interfaces cannot be reduced
+		reducible = false
 		
 	case *EnumSection:
 		reduced, reducible = what.actual.(*EnumSection).what.reduce()
@@ -267,6 +382,60 @@ func (what Type) reduce () (reduced Type, reducible bool) {
 	return
 }
 
+// inheritsFrom returns whether or not this type is the same as ancestor, or
+// inherits from it somewhere along its inheritence chain.
+func (what Type) inheritsFrom (ancestor Type) (inherits bool) {
+	for {
+		if what.Equals(ancestor) {
+			inherits = true
+			return
+		}
+		
+		if what.kind != TypeKindBasic { return }
+		section, isTypeSection := what.actual.(*TypeSection)
+		if !isTypeSection { return }
+		what = section.what
+	}
+}
+
+// Equals returns whether or not this type is the same as another type. Whether
+// or not the types are mutable is not taken into account.
+func (what Type) Equals (other Type) (equal bool) {
+	if what.kind   != other.kind   { return }
+	if what.length != other.length { return }
+	
+	if what.kind == TypeKindBasic {
+		equal = what.actual == other.actual
+	} else {
+		if what.points == nil || other.points == nil {
+			equal = what.points == other.points
+			return
+		}
+		equal = what.points.Equals(*other.points)
+	}
+	return
+}
+
+// canBePassedAs returns whether or not a value of this type can be passed to a
+// slot of type destination without an explicit cast. Since the value is copied,
+// its own mutability does not matter. However, a pointer to immutable data
+// cannot be passed to a pointer to mutable data.
+func (what Type) canBePassedAs (destination Type) (allowed bool) {
+	if !what.Equals(destination) { return }
+
+	source := what
+	for source.points != nil && destination.points != nil {
+		if destination.points.mutable && !source.points.mutable {
+			return
+		}
+		source      = *source.points
+		destination = *destination.points
+	}
+
+	allowed = true
+	return
+}
+
 // analyzeType analyzes a type specifier.
 func (analyzer analysisOperation) analyzeType (
 	inputType parser.Type,
@@ -301,37 +470,47 @@ func (analyzer analysisOperation) analyzeType (
 
 	} else {
 		// analyze the type section this type uses
-		var node any
-		var bitten parser.Identifier
-		
-		node,
-		bitten,
-		err = analyzer.fetchNodeFromIdentifier(inputType.Name())
-		if err != nil { return }
-
-		if bitten.Length() > 0 {
-			err = bitten.NewError(
-				"cannot use member selection in this context",
-				infoerr.ErrorKindError)
-			return
-		}		
-
-		switch node.(type) {
-		// TODO: uncomment once these sections are implemented
-		case *TypeSection, *EnumSection /* , *FaceSection */:
-			outputType.actual = node.(Section)
-			
-		default:
-			err = inputType.Name().NewError (
-				"this must refer to a type, interface, or enum",
-				infoerr.ErrorKindError)
-			return
-		}
+		outputType.actual, err = analyzer.analyzeTypeName (
+			inputType.Name())
 	}
 	
 	return
 }
 
+// analyzeTypeName finds the type, interface, or enum section that an
+// identifier refers to.
+func (analyzer analysisOperation) analyzeTypeName (
+	name parser.Identifier,
+) (
+	actual Section,
+	err error,
+) {
+	var node any
+	var bitten parser.Identifier
+	
+	node, bitten, err = analyzer.fetchNodeFromIdentifier(name)
+	if err != nil { return }
+
+	if bitten.Length() > 0 {
+		err = bitten.NewError(
+			"cannot use member selection in this context",
+			infoerr.ErrorKindError)
+		return
+	}		
+
+	switch node.(type) {
+	case *TypeSection, *EnumSection, *FaceSection:
+		actual = node.(Section)
+		
+	default:
+		err = name.NewError (
+			"this must refer to a type, interface, or enum",
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
+
 // Describe provides a human readable description of the type. The value of this
 // should not be computationally analyzed.
 func (what Type) Describe () (description string) {
@@ -364,10 +543,12 @@ func (what Type) Describe () (description string) {
 			description += "UInt"
 		case &PrimitiveInt:
 			description += "Int"
-		// case &PrimitiveFunc:
-			// description += "Func"
-		// case &PrimitiveFace:
-			// description += "Face"
+		case &PrimitiveBool:
+			description += "Bool"
+		case &PrimitiveFunc:
+			description += "Func"
+		case &PrimitiveFace:
+			description += "Face"
 		case &BuiltInString:
 			description += "String"
 		
@@ -376,7 +557,6 @@ func (what Type) Describe () (description string) {
 
 		default:
 			description += actual.ModuleName() + "." + actual.Name()
-			return
 		}
 	} else {
 		description += "{"
@@ -384,6 +564,10 @@ func (what Type) Describe () (description string) {
 		description += "}"
 	}
 
+	if what.mutable {
+		description += ":mut"
+	}
+
 	if what.length != 1 {
 		description += fmt.Sprint(":", what.length)
 	}
@@ -0,0 +1,276 @@
+package analyzer
+
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// Variable represents a variable declared within a scope. This includes the
+// inputs, outputs, and receiver of a function.
+type Variable struct {
+	locatable
+	name string
+	what Type
+
+	// output is true if the variable is an output of a function. Outputs
+	// can always be written to, because that is how a function returns
+	// values.
+	output bool
+}
+
+// Name returns the name of the variable.
+func (variable Variable) Name () (name string) {
+	name = variable.name
+	return
+}
+
+// What returns the type of the variable.
+func (variable Variable) What () (what Type) {
+	what = variable.what
+	return
+}
+
+// VariableReference is an argument that refers to a variable. If the argument
+// is the place where the variable was declared, declaration will be true.
+type VariableReference struct {
+	locatable
+	variable    *Variable
+	declaration bool
+}
+
+// ToString outputs the data in the argument as a string.
+func (reference VariableReference) ToString (indent int) (output string) {
+	if reference.declaration {
+		output += doIndent (
+			indent, "declaration ",
+			reference.variable.name, "\n")
+		output += reference.variable.what.ToString(indent + 1)
+	} else {
+		output += doIndent (
+			indent, "variable ",
+			reference.variable.name, "\n")
+	}
+	return
+}
+
+// What returns the type of the argument.
+func (reference VariableReference) What () (what Type) {
+	what = reference.variable.what
+	return
+}
+
+// Variable returns the variable being referred to.
+func (reference VariableReference) Variable () (variable *Variable) {
+	variable = reference.variable
+	return
+}
+
+// Declaration returns whether the argument is where the variable is declared.
+func (reference VariableReference) Declaration () (declaration bool) {
+	declaration = reference.declaration
+	return
+}
+
+// Equals returns whether the argument is equal to the specified value. This
+// is always false, because variables are not constant.
+func (reference VariableReference) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because variables are not constant.
+func (reference VariableReference) Value () (value any) {
+	return
+}
+
+// Resolve returns an error, because variables are not constant.
+func (reference VariableReference) Resolve () (constant Argument, err error) {
+	err = reference.NewError (
+		"variable cannot be evaluated at compile time",
+		infoerr.ErrorKindError)
+	return
+}
+
+// canBePassedAs returns true if the variable's type can be passed to the
+// specified type.
+func (reference VariableReference) canBePassedAs (what Type) (allowed bool) {
+	allowed = reference.What().canBePassedAs(what)
+	return
+}
+
+// writable returns whether the variable can be modified. A variable can always
+// be written to where it is declared, so that it can be given an initial value.
+func (reference VariableReference) writable () (writable bool) {
+	writable =
+		reference.declaration ||
+		reference.variable.output ||
+		reference.variable.what.mutable
+	return
+}
+
+// DataReference is an argument that refers to a data section.
+type DataReference struct {
+	locatable
+	section  *DataSection
+	external bool
+}
+
+// ToString outputs the data in the argument as a string.
+func (reference DataReference) ToString (indent int) (output string) {
+	output += doIndent (
+		indent, "data ",
+		reference.section.where.ToString(), "\n")
+	return
+}
+
+// What returns the type of the argument.
+func (reference DataReference) What () (what Type) {
+	what = reference.section.what
+	return
+}
+
+// Section returns the data section being referred to.
+func (reference DataReference) Section () (section *DataSection) {
+	section = reference.section
+	return
+}
+
+// Equals returns whether the argument is equal to the specified value. This
+// is always false, because data sections are not constant.
+func (reference DataReference) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because data sections are not constant.
+func (reference DataReference) Value () (value any) {
+	return
+}
+
+// Resolve returns the value of the data section as a literal, if the data
+// section is immutable and its value is constant.
+func (reference DataReference) Resolve () (constant Argument, err error) {
+	if !isConstant(reference) {
+		err = reference.NewError (
+			"data section cannot be evaluated at compile time",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	constant, err = reference.section.argument.Resolve()
+	if err != nil { return }
+	constant = relocate(constant, reference.location)
+	return
+}
+
+// canBePassedAs returns true if the data section's type can be passed to the
+// specified type.
+func (reference DataReference) canBePassedAs (what Type) (allowed bool) {
+	allowed = reference.What().canBePassedAs(what)
+	return
+}
+
+// writable returns whether the data section can be modified. Data sections
+// from other modules can only be modified if they are read-write (rw).
+func (reference DataReference) writable () (writable bool) {
+	writable = reference.section.what.mutable && !reference.readOnly()
+	return
+}
+
+// readOnly returns whether the data section is being accessed from another
+// module that is only allowed to read its value.
+func (reference DataReference) readOnly () (readOnly bool) {
+	readOnly =
+		reference.external &&
+		reference.section.permission != types.PermissionReadWrite
+	return
+}
+
+// MemberAccess is an argument that selects a member of an object.
+type MemberAccess struct {
+	locatable
+	base     Argument
+	member   ObjectMember
+	external bool
+}
+
+// ToString outputs the data in the argument as a string.
+func (access MemberAccess) ToString (indent int) (output string) {
+	output += doIndent(indent, "member ", access.member.name, "\n")
+	output += access.base.ToString(indent + 1)
+	return
+}
+
+// What returns the type of the argument.
+func (access MemberAccess) What () (what Type) {
+	what = access.member.what
+	return
+}
+
+// Base returns the object that the member is being selected from.
+func (access MemberAccess) Base () (base Argument) {
+	base = access.base
+	return
+}
+
+// Member returns the member being selected.
+func (access MemberAccess) Member () (member ObjectMember) {
+	member = access.member
+	return
+}
+
+// Equals returns whether the argument is equal to the specified value. This
+// is always false, because members are not constant.
+func (access MemberAccess) Equals (value any) (equal bool) {
+	return
+}
+
+// Value returns nil, because members are not constant.
+func (access MemberAccess) Value () (value any) {
+	return
+}
+
+// Resolve returns an error, because members are not constant.
+func (access MemberAccess) Resolve () (constant Argument, err error) {
+	err = access.NewError (
+		"member cannot be evaluated at compile time",
+		infoerr.ErrorKindError)
+	return
+}
+
+// canBePassedAs returns true if the member's type can be passed to the
+// specified type.
+func (access MemberAccess) canBePassedAs (what Type) (allowed bool) {
+	allowed = access.What().canBePassedAs(what)
+	return
+}
+
+// writable returns whether the member can be modified. If the member is being
+// accessed through a pointer, this depends on whether the pointer points to
+// mutable data. Otherwise, the object itself must be writable. Members from
+// other modules can only be modified if they are read-write (rw).
+func (access MemberAccess) writable () (writable bool) {
+	writable = !access.readOnly() && canModifyThrough(access.base)
+	return
+}
+
+// readOnly returns whether the member is being accessed from another module
+// that is only allowed to read its value.
+func (access MemberAccess) readOnly () (readOnly bool) {
+	readOnly =
+		access.external &&
+		access.member.permission != types.PermissionReadWrite
+	return
+}
+
+// analyzeDeclaration analyzes a parser declaration into a new variable. The
+// variable is not defined in any scope.
+func (analyzer *analysisOperation) analyzeDeclaration (
+	declaration parser.Declaration,
+) (
+	variable *Variable,
+	err error,
+) {
+	variable = &Variable { }
+	variable.location = declaration.Location()
+	variable.name     = declaration.Name()
+	variable.what, err = analyzer.analyzeType(declaration.Type())
+	return
+}
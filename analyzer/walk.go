@@ -0,0 +1,245 @@
+package analyzer
+
+import "sort"
+
+// Node is any part of an analyzed module. It is one of SectionTable,
+// *DataSection, *TypeSection, ObjectMember, *EnumSection, EnumMember,
+// *FaceSection, FaceBehavior, *FuncSection, *FuncOutput, *Variable, Block, a
+// Phrase, IfBranch, CasePhrase, an Argument, or Type.
+type Node any
+
+// Visitor has its Visit method called for each node encountered by Walk. If
+// the visitor it returns is not nil, Walk visits each of the children of the
+// node with it, and then calls its Visit method with nil.
+type Visitor interface {
+	Visit (node Node) (visitor Visitor)
+}
+
+// Walk traverses an analyzed module in depth first order. It starts by calling
+// visitor.Visit(node), and continues on to the children of node with the
+// visitor that it returns.
+//
+// Only types that are written in the source code are visited, such as the
+// types of sections, variables, and cast phrases. Sections that are referred
+// to by a node, such as the function called by a call phrase or the section
+// that a type is based on, are not children of that node.
+func Walk (node Node, visitor Visitor) {
+	visitor = visitor.Visit(node)
+	if visitor == nil { return }
+
+	switch node.(type) {
+	case SectionTable:
+		for _, section := range node.(SectionTable).Sorted() {
+			Walk(section, visitor)
+		}
+
+	case *DataSection:
+		section := node.(*DataSection)
+		Walk(section.what, visitor)
+		walkArgument(section.argument, visitor)
+
+	case *TypeSection:
+		section := node.(*TypeSection)
+		Walk(section.what, visitor)
+		walkArgument(section.argument, visitor)
+		for _, member := range section.members {
+			Walk(member, visitor)
+		}
+
+	case ObjectMember:
+		member := node.(ObjectMember)
+		Walk(member.what, visitor)
+		walkArgument(member.argument, visitor)
+
+	case *EnumSection:
+		section := node.(*EnumSection)
+		Walk(section.what, visitor)
+		for _, member := range section.members {
+			Walk(member, visitor)
+		}
+
+	case EnumMember:
+		walkArgument(node.(EnumMember).argument, visitor)
+
+	case *FaceSection:
+		section := node.(*FaceSection)
+		Walk(section.what, visitor)
+		switch section.kind {
+		case FaceKindType:
+			names := make([]string, 0, len(section.behaviors))
+			for name := range section.behaviors {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				Walk(section.behaviors[name], visitor)
+			}
+		case FaceKindFunc:
+			walkVariables(section.signature.inputs, visitor)
+			walkVariables(section.signature.outputs, visitor)
+		}
+
+	case FaceBehavior:
+		behavior := node.(FaceBehavior)
+		walkVariables(behavior.inputs, visitor)
+		walkVariables(behavior.outputs, visitor)
+
+	case *FuncSection:
+		section := node.(*FuncSection)
+		if section.receiver != nil {
+			Walk(section.receiver, visitor)
+		}
+		walkVariables(section.inputs, visitor)
+		for _, output := range section.outputs {
+			Walk(output, visitor)
+		}
+		if !section.external {
+			Walk(section.root, visitor)
+		}
+
+	case *FuncOutput:
+		output := node.(*FuncOutput)
+		Walk(output.what, visitor)
+		walkArgument(output.argument, visitor)
+
+	case *Variable:
+		Walk(node.(*Variable).what, visitor)
+
+	case Type:
+		what := node.(Type)
+		if what.points != nil {
+			Walk(*what.points, visitor)
+		}
+
+	case Block:
+		for _, phrase := range node.(Block).phrases {
+			Walk(phrase, visitor)
+		}
+
+	case ArbitraryPhrase:
+		walkArguments(node.(ArbitraryPhrase).arguments, visitor)
+
+	case AssignPhrase:
+		phrase := node.(AssignPhrase)
+		Walk(phrase.target, visitor)
+		walkArguments(phrase.arguments, visitor)
+
+	case IfPhrase:
+		phrase := node.(IfPhrase)
+		for _, branch := range phrase.branches {
+			Walk(branch, visitor)
+		}
+		if phrase.fallback != nil {
+			Walk(*phrase.fallback, visitor)
+		}
+
+	case IfBranch:
+		branch := node.(IfBranch)
+		Walk(branch.condition, visitor)
+		Walk(branch.block, visitor)
+
+	case SwitchPhrase:
+		phrase := node.(SwitchPhrase)
+		Walk(phrase.subject, visitor)
+		for _, casePhrase := range phrase.cases {
+			Walk(casePhrase, visitor)
+		}
+
+	case CasePhrase:
+		phrase := node.(CasePhrase)
+		walkArguments(phrase.values, visitor)
+		Walk(phrase.block, visitor)
+
+	case WhilePhrase:
+		phrase := node.(WhilePhrase)
+		Walk(phrase.condition, visitor)
+		Walk(phrase.block, visitor)
+
+	case ForPhrase:
+		phrase := node.(ForPhrase)
+		if phrase.index != nil {
+			Walk(phrase.index, visitor)
+		}
+		if phrase.element != nil {
+			Walk(phrase.element, visitor)
+		}
+		Walk(phrase.collection, visitor)
+		Walk(phrase.block, visitor)
+
+	case DeferPhrase:
+		Walk(node.(DeferPhrase).block, visitor)
+
+	case CallPhrase:
+		phrase := node.(CallPhrase)
+		walkArgument(phrase.receiver, visitor)
+		walkArguments(phrase.arguments, visitor)
+		walkArguments(phrase.returnsTo, visitor)
+
+	case CastPhrase:
+		phrase := node.(CastPhrase)
+		Walk(phrase.value, visitor)
+		Walk(phrase.what, visitor)
+
+	case OperatorPhrase:
+		walkArguments(node.(OperatorPhrase).arguments, visitor)
+
+	case ReferencePhrase:
+		Walk(node.(ReferencePhrase).value, visitor)
+
+	case List:
+		walkArguments(node.(List).arguments, visitor)
+
+	case Dereference:
+		Walk(node.(Dereference).argument, visitor)
+
+	case VariableReference:
+		reference := node.(VariableReference)
+		if reference.declaration {
+			Walk(reference.variable, visitor)
+		}
+
+	case MemberAccess:
+		Walk(node.(MemberAccess).base, visitor)
+	}
+
+	visitor.Visit(nil)
+}
+
+// walkArgument walks an argument, unless it is nil.
+func walkArgument (argument Argument, visitor Visitor) {
+	if argument == nil { return }
+	Walk(argument, visitor)
+}
+
+// walkArguments walks each argument in a list.
+func walkArguments (arguments []Argument, visitor Visitor) {
+	for _, argument := range arguments {
+		walkArgument(argument, visitor)
+	}
+}
+
+// walkVariables walks each variable in a list.
+func walkVariables (variables []*Variable, visitor Visitor) {
+	for _, variable := range variables {
+		Walk(variable, visitor)
+	}
+}
+
+// inspector is a visitor that calls a function on each node.
+type inspector func (node Node) (proceed bool)
+
+// Visit calls the inspector function, and continues on to the children of the
+// node if it returns true.
+func (function inspector) Visit (node Node) (visitor Visitor) {
+	if function(node) {
+		visitor = function
+	}
+	return
+}
+
+// Inspect traverses an analyzed module in depth first order, in the same way as
+// Walk. It starts by calling function(node), and if that returns true, it
+// inspects each of the children of node, followed by calling function(nil).
+func Inspect (node Node, function func (node Node) (proceed bool)) {
+	Walk(node, inspector(function))
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import "os"
+import "fmt"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+
+// nodeNamer is a visitor that writes down the type of every node it visits,
+// indented by how deep the node is.
+type nodeNamer struct {
+	depth  *int
+	output *string
+}
+
+func (namer nodeNamer) Visit (node Node) (visitor Visitor) {
+	if node == nil {
+		*namer.depth --
+		return
+	}
+
+	*namer.output += doIndent(*namer.depth, fmt.Sprintf("%T", node), "\n")
+	*namer.depth ++
+	visitor = namer
+	return
+}
+
+func walkTable (test *testing.T) (table SectionTable) {
+	cwd, _ := os.Getwd()
+	modulePath := filepath.Join(cwd, "../tests/analyzer/walk")
+	table, err := Analyze(modulePath, false, target.Default)
+	if err != nil {
+		test.Log("returned error:")
+		test.Log(err.Error())
+		test.FailNow()
+	}
+	return
+}
+
+func TestWalk (test *testing.T) {
+	table  := walkTable(test)
+	depth  := 0
+	output := ""
+	Walk(table, nodeNamer { depth: &depth, output: &output })
+
+	correct :=
+`analyzer.SectionTable
+	*analyzer.DataSection
+		analyzer.Type
+		analyzer.UIntLiteral
+	*analyzer.TypeSection
+		analyzer.Type
+		analyzer.ObjectMember
+			analyzer.Type
+			analyzer.UIntLiteral
+	*analyzer.FuncSection
+		*analyzer.Variable
+			analyzer.Type
+				analyzer.Type
+		*analyzer.Variable
+			analyzer.Type
+		*analyzer.FuncOutput
+			analyzer.Type
+			analyzer.UIntLiteral
+		analyzer.Block
+			analyzer.IfPhrase
+				analyzer.IfBranch
+					analyzer.OperatorPhrase
+						analyzer.VariableReference
+						analyzer.UIntLiteral
+					analyzer.Block
+						analyzer.AssignPhrase
+							analyzer.VariableReference
+								*analyzer.Variable
+									analyzer.Type
+							analyzer.CastPhrase
+								analyzer.VariableReference
+								analyzer.Type
+						analyzer.AssignPhrase
+							analyzer.VariableReference
+							analyzer.VariableReference
+				analyzer.Block
+					analyzer.CallPhrase
+						analyzer.VariableReference
+						analyzer.VariableReference
+						analyzer.VariableReference
+	*analyzer.EnumSection
+		analyzer.Type
+		analyzer.EnumMember
+			analyzer.UIntLiteral
+		analyzer.EnumMember
+			analyzer.UIntLiteral
+`
+	if output != correct {
+		test.Log("mismatched output")
+		test.Log("- want:\n" + correct)
+		test.Log("- have:\n" + output)
+		test.Fail()
+	}
+
+	if depth != 0 {
+		test.Log("visitor was not called with nil after every node")
+		test.Fail()
+	}
+}
+
+func TestInspect (test *testing.T) {
+	table := walkTable(test)
+	names := []string { }
+	Inspect(table, func (node Node) (proceed bool) {
+		switch node.(type) {
+		case Block:
+			// nothing inside of blocks should be inspected
+			return false
+		case *Variable:
+			names = append(names, node.(*Variable).Name())
+		}
+		return true
+	})
+
+	correct := "[receiver input]"
+	if fmt.Sprint(names) != correct {
+		test.Log("CORRECT:", correct)
+		test.Log("RESULT: ", fmt.Sprint(names))
+		test.Fail()
+	}
+}
@@ -1,21 +1,280 @@
-package arfc
+package main
 
 import "os"
 import "fmt"
-import "git.tebibyte.media/arf/arf"
+import "flag"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/lsp"
+import "git.tebibyte.media/arf/arf/repl"
+import "git.tebibyte.media/arf/arf/doc"
+import "git.tebibyte.media/arf/arf/formatter"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/translator"
+import "git.tebibyte.media/arf/arf/interpreter"
+
+// command is a subcommand of arfc. If args is negative, the command takes one
+// or more arguments.
+type command struct {
+	usage string
+	run   func (platform target.Target, arguments []string) (err error)
+	args  int
+
+	// flags, if not nil, adds any flags that only this command uses.
+	flags func (flags *flag.FlagSet)
+}
+
+// flags used by the fmt command.
+var formatCheck *bool
+var formatDiff  *bool
+
+// flags used by the doc command.
+var docFormat *string
+var docOutput *string
+
+var commands = map[string] command {
+	"build": {
+		usage: "module output",
+		run:   build,
+		args:  2,
+	},
+	"layout": {
+		usage: "module",
+		run:   layout,
+		args:  1,
+	},
+	"lsp": {
+		usage: "",
+		run:   languageServer,
+		args:  0,
+	},
+	"fmt": {
+		usage: "[--check] [--diff] file|module...",
+		run:   format,
+		args:  -1,
+		flags: func (flags *flag.FlagSet) {
+			formatCheck = flags.Bool (
+				"check", false,
+				"list unformatted files instead of " +
+				"formatting them")
+			formatDiff = flags.Bool (
+				"diff", false,
+				"print what formatting would change instead " +
+				"of changing it")
+		},
+	},
+	"run": {
+		usage: "module [arguments...]",
+		run:   run,
+		args:  -1,
+	},
+	"repl": {
+		usage: "",
+		run:   readEvalPrint,
+		args:  0,
+	},
+	"doc": {
+		usage: "[--format html|markdown] [--output directory] module",
+		run:   document,
+		args:  1,
+		flags: func (flags *flag.FlagSet) {
+			docFormat = flags.String (
+				"format", "html",
+				"the format to write documentation in")
+			docOutput = flags.String (
+				"output", "docs",
+				"the directory to write documentation to")
+		},
+	},
+}
 
 func main () {
-	if len(os.Args) != 2 {
-		fmt.Println("XXX please specify module path and output path")
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
 	}
 
-	inPath  := os.Args[0]
-	outPath := os.Args[1]
-	file := os.OpenFile(outPath, os.WRONLY | os.CREATE, 0655)
+	name := os.Args[1]
+	subcommand, exists := commands[name]
+	if !exists {
+		usage()
+		os.Exit(2)
+	}
+
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	targetName := flags.String (
+		"target", target.Default.Name,
+		fmt.Sprint("target to compile for, one of: ", target.Names()))
+	flags.Usage = func () {
+		fmt.Fprintln (
+			flags.Output(), "usage:", os.Args[0], name,
+			"[--target name]", subcommand.usage)
+		flags.PrintDefaults()
+	}
+	if subcommand.flags != nil {
+		subcommand.flags(flags)
+	}
+	flags.Parse(os.Args[2:])
 
-	err := arf.CompileModule(inPath, outFile)
+	wrongArgs :=
+		subcommand.args <  0 && flags.NArg() < 1 ||
+		subcommand.args >= 0 && flags.NArg() != subcommand.args
+	if wrongArgs {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	platform, err := target.Find(*targetName)
 	if err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	err = subcommand.run(platform, flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+func usage () {
+	fmt.Fprintln(os.Stderr, "usage:", os.Args[0], "command [arguments]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	names := []string { "build", "run", "repl", "layout", "lsp", "fmt", "doc" }
+	for _, name := range names {
+		fmt.Fprintln(os.Stderr, "\t" + name, commands[name].usage)
+	}
+}
+
+// build translates a module into C.
+func build (platform target.Target, arguments []string) (err error) {
+	inPath, err := filepath.Abs(arguments[0])
+	if err != nil { return }
+
+	outFile, err := os.OpenFile (
+		arguments[1],
+		os.O_WRONLY | os.O_CREATE | os.O_TRUNC,
+		0644)
+	if err != nil { return }
+	defer outFile.Close()
+
+	err = translator.Translate(inPath, platform, outFile)
+	return
+}
+
+// run runs a module with the interpreter, passing any arguments after it on to
+// its main function. The output of main is used as the exit status.
+func run (platform target.Target, arguments []string) (err error) {
+	status, err := interpreter.Run (
+		arguments[0],
+		arguments[1:],
+		platform,
+		os.Stdout)
+	if err != nil { return }
+	if status != 0 { os.Exit(status) }
+	return
+}
+
+// readEvalPrint runs an interactive prompt on standard input and output.
+func readEvalPrint (platform target.Target, arguments []string) (err error) {
+	err = repl.Run(os.Stdin, os.Stdout, platform)
+	return
+}
+
+// layout prints out the memory layout of every type defined in a module.
+func layout (platform target.Target, arguments []string) (err error) {
+	inPath, err := filepath.Abs(arguments[0])
+	if err != nil { return }
+
+	table, err := analyzer.Analyze(inPath, false, platform)
+	if err != nil { return }
+
+	fmt.Println("target", platform.ToString())
+	for _, section := range table.Sorted() {
+		if section.ModulePath() != inPath { continue }
+
+		sectionLayout, isType := analyzer.SectionLayout(section, platform)
+		if !isType { continue }
+
+		fmt.Println(section.Name())
+		fmt.Print(sectionLayout.ToString(1))
+	}
+	return
+}
+
+// languageServer runs a language server that talks to an editor over standard
+// input and output.
+func languageServer (platform target.Target, arguments []string) (err error) {
+	err = lsp.Serve(os.Stdin, os.Stdout, platform)
+	return
+}
+
+// format formats ARF files in place. Modules are formatted by formatting every
+// file in them. With --check or --diff, the files are left as they are and
+// formatting them is treated as a failure.
+func format (platform target.Target, arguments []string) (err error) {
+	paths := []string { }
+	for _, argument := range arguments {
+		var info os.FileInfo
+		info, err = os.Stat(argument)
+		if err != nil { return }
+
+		if !info.IsDir() {
+			paths = append(paths, argument)
+			continue
+		}
+
+		var files []string
+		files, err = filepath.Glob(filepath.Join(argument, "*.arf"))
+		if err != nil { return }
+		paths = append(paths, files...)
+	}
+
+	unformatted := 0
+	for _, path := range paths {
+		var source []byte
+		source, err = os.ReadFile(path)
+		if err != nil { return }
+
+		var formatted string
+		formatted, err = formatter.Format(path, string(source))
+		if err != nil { return }
+		if formatted == string(source) { continue }
+		unformatted ++
+
+		switch {
+		case *formatDiff:
+			diff := formatter.Diff(path, string(source), formatted)
+			fmt.Print(diff)
+		case *formatCheck:
+			fmt.Println(path)
+		default:
+			err = os.WriteFile(path, []byte(formatted), 0644)
+			if err != nil { return }
+		}
+	}
+
+	if (*formatCheck || *formatDiff) && unformatted > 0 {
+		err = fmt.Errorf("%d file(s) are not formatted", unformatted)
+	}
+	return
+}
+
+// document writes documentation for a module, and every module it requires,
+// into a directory.
+func document (platform target.Target, arguments []string) (err error) {
+	format, worked := doc.FormatFrom(*docFormat)
+	if !worked {
+		err = fmt.Errorf("unknown documentation format %s", *docFormat)
+		return
+	}
+
+	inPath, err := filepath.Abs(arguments[0])
+	if err != nil { return }
+
+	written, err := doc.Generate(inPath, *docOutput, format, platform)
+	for _, path := range written {
+		fmt.Println(path)
+	}
+	return
+}
@@ -0,0 +1,582 @@
+/*
+Package doc implements a documentation generator for the ARF language. The
+function Generate takes in a module path, and writes a page of documentation for
+that module and every module that it requires into a directory.
+
+Each page lists the sections of its module that other modules can use, along
+with their members, inputs, outputs, and doc comments. Types that are defined in
+a documented module are linked to. Pages can be written as either Markdown or
+HTML.
+*/
+package doc
+
+import "os"
+import "fmt"
+import "sort"
+import "strings"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/analyzer"
+
+// Format determines what kind of file documentation is written as.
+type Format int
+
+const (
+	FormatHTML Format = iota
+	FormatMarkdown
+)
+
+// FormatFrom creates a format from its name. If the name is not valid, worked
+// will be false.
+func FormatFrom (name string) (format Format, worked bool) {
+	worked = true
+	switch name {
+	case "html":     format = FormatHTML
+	case "markdown": format = FormatMarkdown
+	default:         worked = false
+	}
+	return
+}
+
+// extension returns the file extension used for pages of the format.
+func (format Format) extension () (extension string) {
+	switch format {
+	case FormatHTML:     extension = ".html"
+	case FormatMarkdown: extension = ".md"
+	}
+	return
+}
+
+// generationOperation holds information about an ongoing documentation
+// generation operation.
+type generationOperation struct {
+	format Format
+
+	// modules contains every module being documented, indexed by its full
+	// path.
+	modules map[string] *module
+	order   []string
+}
+
+// module is a module that is being documented.
+type module struct {
+	path string
+	name string
+	file string
+	tree parser.SyntaxTree
+}
+
+// page is the documentation of a single module.
+type page struct {
+	name     string
+	author   string
+	license  string
+	requires []span
+	groups   []group
+}
+
+// group is a list of sections of the same kind.
+type group struct {
+	title   string
+	entries []entry
+}
+
+// entry is a documented section or member.
+type entry struct {
+	name      string
+	anchor    string
+	signature []span
+	doc       string
+	members   []entry
+}
+
+// span is a piece of code. If it names something that is documented, it has a
+// link to its documentation.
+type span struct {
+	text string
+
+	// file is the file name of the page being linked to, without its
+	// extension. If it is empty, the link is to the same page.
+	file   string
+	anchor string
+	linked bool
+}
+
+// Generate analyzes the module at modulePath, and writes documentation for it
+// and every module it requires into outputPath. The paths of all files that
+// were written are returned.
+func Generate (
+	modulePath string,
+	outputPath string,
+	format     Format,
+	platform   target.Target,
+) (
+	written []string,
+	err     error,
+) {
+	modulePath, err = filepath.Abs(modulePath)
+	if err != nil { return }
+
+	// documentation is only generated for modules that are correct
+	_, err = analyzer.Analyze(modulePath, false, platform)
+	if err != nil { return }
+
+	generator := generationOperation {
+		format:  format,
+		modules: make(map[string] *module),
+	}
+	err = generator.collect(modulePath)
+	if err != nil { return }
+
+	err = os.MkdirAll(outputPath, 0755)
+	if err != nil { return }
+
+	for _, path := range generator.order {
+		current := generator.modules[path]
+		content := generator.render(generator.page(current))
+		output  := filepath.Join (
+			outputPath,
+			current.file + format.extension())
+
+		err = os.WriteFile(output, []byte(content), 0644)
+		if err != nil { return }
+		written = append(written, output)
+	}
+	return
+}
+
+// collect finds a module and every module it requires, and gives each of them a
+// unique file name.
+func (generator *generationOperation) collect (modulePath string) (err error) {
+	modulePath = filepath.Clean(modulePath)
+	if _, exists := generator.modules[modulePath]; exists { return }
+
+	tree, err := parser.Fetch(modulePath, true)
+	if err != nil { return }
+
+	current := &module {
+		path: modulePath,
+		name: filepath.Base(modulePath),
+		tree: tree,
+	}
+	current.file = current.name
+	for suffix := 2; generator.fileTaken(current.file); suffix ++ {
+		current.file = fmt.Sprint(current.name, "-", suffix)
+	}
+	generator.modules[modulePath] = current
+	generator.order = append(generator.order, modulePath)
+
+	for _, name := range requireNames(tree) {
+		path, _ := tree.ResolveRequire(name)
+		err = generator.collect(path)
+		if err != nil { return }
+	}
+	return
+}
+
+// fileTaken returns whether a module is already using a file name.
+func (generator *generationOperation) fileTaken (file string) (taken bool) {
+	for _, current := range generator.modules {
+		taken = taken || current.file == file
+	}
+	return
+}
+
+// requireNames returns the names of the modules that a module requires, in
+// alphabetical order.
+func requireNames (tree parser.SyntaxTree) (names []string) {
+	requires := tree.Requires()
+	for ; !requires.End(); requires.Next() {
+		names = append(names, requires.Key())
+	}
+	sort.Strings(names)
+	return
+}
+
+// page builds the documentation of a module.
+func (generator *generationOperation) page (current *module) (built page) {
+	built.name    = current.name
+	built.author  = current.tree.Author()
+	built.license = current.tree.License()
+
+	for _, name := range requireNames(current.tree) {
+		path, _ := current.tree.ResolveRequire(name)
+		built.requires = append(built.requires, span {
+			text:   name,
+			file:   generator.modules[filepath.Clean(path)].file,
+			linked: true,
+		})
+	}
+
+	groups := map[string] *group {
+		"type": { title: "Types" },
+		"enum": { title: "Enums" },
+		"face": { title: "Interfaces" },
+		"data": { title: "Data" },
+		"func": { title: "Functions" },
+	}
+
+	sections := current.tree.Sections()
+	for ; !sections.End(); sections.Next() {
+		section := sections.Value()
+		if section.Permission() == types.PermissionPrivate { continue }
+
+		kind, documented := generator.entry(current, section)
+		groups[kind].entries = append(groups[kind].entries, documented)
+	}
+
+	kinds := []string { "type", "enum", "face", "data", "func" }
+	for _, kind := range kinds {
+		current := groups[kind]
+		if len(current.entries) == 0 { continue }
+		sortEntries(current.entries)
+		built.groups = append(built.groups, *current)
+	}
+	return
+}
+
+// entry builds the documentation of a section, and returns what kind of section
+// it is.
+func (generator *generationOperation) entry (
+	current *module,
+	section parser.Section,
+) (
+	kind       string,
+	documented entry,
+) {
+	documented.name = section.Name()
+	documented.doc  = section.Doc()
+	permission := section.Permission().ToString()
+
+	switch section.(type) {
+	case parser.TypeSection:
+		typeSection := section.(parser.TypeSection)
+		kind = "type"
+		documented.signature = generator.declaration (
+			current, "type " + permission + " " + documented.name,
+			typeSection.Type(), typeSection.Argument())
+
+		for index := 0; index < typeSection.MembersLength(); index ++ {
+			member := typeSection.Member(index)
+			if member.Permission() == types.PermissionPrivate {
+				continue
+			}
+
+			documented.members = append (
+				documented.members,
+				generator.member(current, member))
+		}
+
+	case parser.EnumSection:
+		enumSection := section.(parser.EnumSection)
+		kind = "enum"
+		documented.signature = generator.declaration (
+			current, "enum " + permission + " " + documented.name,
+			enumSection.Type(), parser.Argument { })
+
+		for index := 0; index < enumSection.Length(); index ++ {
+			member    := enumSection.Item(index)
+			signature := []span { { text: "- " + member.Name() } }
+			value     := member.Argument()
+			if !value.Nil() {
+				signature = append(signature, span {
+					text: " " + value.ToString(0, false),
+				})
+			}
+
+			documented.members = append(documented.members, entry {
+				name:      member.Name(),
+				signature: signature,
+				doc:       member.Doc(),
+			})
+		}
+
+	case parser.FaceSection:
+		faceSection := section.(parser.FaceSection)
+		kind = "face"
+		header   := "face " + permission + " " + documented.name + ":"
+		inherits := faceSection.Inherits()
+		documented.signature = append (
+			[]span { { text: header } },
+			generator.identifier(current, inherits)...)
+
+		if faceSection.Kind() == parser.FaceKindFunc {
+			documented.members = generator.behavior (
+				current, faceSection.FaceBehavior)
+			break
+		}
+
+		behaviors := faceSection.Behaviors()
+		for ; !behaviors.End(); behaviors.Next() {
+			behavior := behaviors.Value()
+			documented.members = append(documented.members, entry {
+				name:      behavior.Name(),
+				signature: []span { { text: behavior.Name() } },
+				doc:       behavior.Doc(),
+				members:   generator.behavior (
+					current, behavior),
+			})
+		}
+		sortEntries(documented.members)
+
+	case parser.DataSection:
+		dataSection := section.(parser.DataSection)
+		kind = "data"
+		documented.signature = generator.declaration (
+			current, "data " + permission + " " + documented.name,
+			dataSection.Type(), dataSection.Argument())
+
+	case parser.FuncSection:
+		funcSection := section.(parser.FuncSection)
+		kind = "func"
+		documented.signature = []span {
+			{ text: "func " + permission + " " + documented.name },
+		}
+
+		receiver := funcSection.Receiver()
+		if receiver != nil {
+			what := receiver.Type()
+			if what.Kind() != parser.TypeKindBasic {
+				what = what.Points()
+			}
+			documented.name =
+				what.Name().ToString() + "." + documented.name
+			documented.members = append (
+				documented.members,
+				generator.variable(current, "@ ", *receiver))
+		}
+
+		for index := 0; index < funcSection.InputsLength(); index ++ {
+			documented.members = append (
+				documented.members,
+				generator.variable (
+					current, "> ",
+					funcSection.Input(index)))
+		}
+
+		for index := 0; index < funcSection.OutputsLength(); index ++ {
+			output := funcSection.Output(index)
+			documented.members = append(documented.members, entry {
+				name: output.Name(),
+				signature: generator.declaration (
+					current, "< " + output.Name(),
+					output.Type(), output.Argument()),
+			})
+		}
+	}
+
+	documented.anchor = documented.name
+	anchor(documented.members, documented.anchor)
+	return
+}
+
+// anchor gives each member in a list, and the members within them, an anchor
+// that is based on the anchor of what they are in.
+func anchor (members []entry, prefix string) {
+	for index := range members {
+		member := &members[index]
+		member.anchor = prefix + "." + member.name
+		anchor(member.members, member.anchor)
+	}
+}
+
+// member builds the documentation of a type section member.
+func (generator *generationOperation) member (
+	current *module,
+	member  parser.TypeSectionMember,
+) (
+	documented entry,
+) {
+	documented.name = member.Name()
+	documented.doc  = member.Doc()
+	documented.signature = generator.declaration (
+		current, member.Permission().ToString() + " " + member.Name(),
+		member.Type(), member.Argument())
+	if member.BitWidth() > 0 {
+		documented.signature = append(documented.signature, span {
+			text: fmt.Sprint(" & ", member.BitWidth()),
+		})
+	}
+	return
+}
+
+// behavior builds the documentation of the inputs and outputs of an interface
+// behavior.
+func (generator *generationOperation) behavior (
+	current  *module,
+	behavior parser.FaceBehavior,
+) (
+	members []entry,
+) {
+	for index := 0; index < behavior.InputsLength(); index ++ {
+		input := behavior.Input(index)
+		members = append (
+			members,
+			generator.variable(current, "> ", input))
+	}
+	for index := 0; index < behavior.OutputsLength(); index ++ {
+		output := behavior.Output(index)
+		members = append (
+			members,
+			generator.variable(current, "< ", output))
+	}
+	return
+}
+
+// variable builds the documentation of a function input, output, or receiver.
+func (generator *generationOperation) variable (
+	current     *module,
+	prefix      string,
+	declaration parser.Declaration,
+) (
+	documented entry,
+) {
+	documented.name      = declaration.Name()
+	documented.signature = generator.declaration (
+		current, prefix + declaration.Name(),
+		declaration.Type(), parser.Argument { })
+	return
+}
+
+// declaration builds the signature of something that has a name, a type, and
+// an optional value.
+func (generator *generationOperation) declaration (
+	current *module,
+	name    string,
+	what    parser.Type,
+	value   parser.Argument,
+) (
+	signature []span,
+) {
+	signature = []span { { text: name } }
+	if !what.Nil() {
+		signature = append(signature, span { text: ":" })
+		signature = append(signature, generator.what(current, what)...)
+	}
+	if !value.Nil() {
+		signature = append(signature, span {
+			text: " " + value.ToString(0, false),
+		})
+	}
+	return
+}
+
+// what builds the signature of a type, linking to the sections that it names.
+func (generator *generationOperation) what (
+	current *module,
+	what    parser.Type,
+) (
+	signature []span,
+) {
+	if what.Kind() == parser.TypeKindBasic {
+		signature = generator.identifier(current, what.Name())
+	} else {
+		signature = append(signature, span { text: "{" })
+		signature = append (
+			signature,
+			generator.what(current, what.Points())...)
+		if what.Kind() == parser.TypeKindVariableArray {
+			signature = append(signature, span { text: " .." })
+		}
+		signature = append(signature, span { text: "}" })
+	}
+
+	if what.Length() > 1 {
+		signature = append(signature, span {
+			text: fmt.Sprint(":", what.Length()),
+		})
+	}
+	if what.Mutable() {
+		signature = append(signature, span { text: ":mut" })
+	}
+	return
+}
+
+// identifier builds a span for the name of a section. If the section is
+// documented, the span links to it.
+func (generator *generationOperation) identifier (
+	current    *module,
+	identifier parser.Identifier,
+) (
+	signature []span,
+) {
+	named := span { text: identifier.ToString() }
+
+	var target *module
+	var name   string
+	switch identifier.Length() {
+	case 1:
+		target = current
+		name   = identifier.Item(0)
+	case 2:
+		path, exists := current.tree.ResolveRequire(identifier.Item(0))
+		if exists {
+			target = generator.modules[filepath.Clean(path)]
+			name   = identifier.Item(1)
+		}
+	}
+
+	if target != nil {
+		section := target.tree.LookupSection("", name)
+		named.linked =
+			section != nil &&
+			section.Permission() != types.PermissionPrivate
+	}
+	if named.linked {
+		named.anchor = name
+		if target != current { named.file = target.file }
+	}
+
+	signature = []span { named }
+	return
+}
+
+// render writes out a page in the format of the operation.
+func (generator *generationOperation) render (built page) (output string) {
+	switch generator.format {
+	case FormatMarkdown:
+		output = built.markdown(generator.format.extension())
+	case FormatHTML:
+		output = built.html(generator.format.extension())
+	}
+	return
+}
+
+// href returns the address that a span links to.
+func (linked span) href (extension string) (href string) {
+	if linked.file != "" { href = linked.file + extension }
+	if linked.anchor != "" { href += "#" + linked.anchor }
+	return
+}
+
+// paragraphs splits a doc comment into paragraphs, which are separated by blank
+// lines. Lines within a paragraph are joined with spaces.
+func paragraphs (doc string) (split []string) {
+	current := []string { }
+	flush := func () {
+		if len(current) > 0 {
+			split = append(split, strings.Join(current, " "))
+		}
+		current = current[:0]
+	}
+
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+		} else {
+			current = append(current, line)
+		}
+	}
+	flush()
+	return
+}
+
+// sortEntries sorts a list of entries by name.
+func sortEntries (entries []entry) {
+	sort.Slice(entries, func (left, right int) bool {
+		return entries[left].name < entries[right].name
+	})
+}
@@ -0,0 +1,87 @@
+package doc
+
+import "os"
+import "strings"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+
+// generate generates documentation for the test module, and returns the
+// contents of each page that was written, indexed by file name.
+func generate (test *testing.T, format Format) (pages map[string] string) {
+	written, err := Generate (
+		"../tests/doc/module", test.TempDir(),
+		format, target.Default)
+	if err != nil { test.Fatal(err) }
+
+	pages = make(map[string] string)
+	for _, path := range written {
+		content, err := os.ReadFile(path)
+		if err != nil { test.Fatal(err) }
+		pages[filepath.Base(path)] = string(content)
+	}
+	return
+}
+
+func TestMarkdown (test *testing.T) {
+	pages := generate(test, FormatMarkdown)
+	if len(pages) != 2 {
+		test.Errorf("expected 2 pages, got %d", len(pages))
+	}
+
+	for _, name := range []string { "module.md", "library.md" } {
+		correct, err := os.ReadFile(filepath.Join("../tests/doc", name))
+		if err != nil { test.Fatal(err) }
+		if pages[name] != string(correct) {
+			test.Errorf (
+				"%s does not match:\ncorrect:\n%s\nresult:\n%s",
+				name, correct, pages[name])
+		}
+	}
+}
+
+func TestHTML (test *testing.T) {
+	pages := generate(test, FormatHTML)
+
+	module := pages["module.html"]
+	for _, expected := range []string {
+		"<h1>Module module</h1>",
+		"<li><code><a href=\"library.html\">library</a></code></li>",
+		"<h3 id=\"aPoint\">aPoint</h3>",
+		"<code>rw thing:" +
+			"<a href=\"library.html#aThing\">library.aThing</a>" +
+			"</code>",
+		"<code>@ point:{<a href=\"#aPoint\">aPoint</a>:mut}</code>",
+		"<code>&gt; distance:Int</code>",
+		"<p>it has two coordinates.</p>",
+	} {
+		if !strings.Contains(module, expected) {
+			test.Errorf("module.html does not contain %s", expected)
+		}
+	}
+
+	library := pages["library.html"]
+	for _, expected := range []string {
+		"<p>Author: Sasha Koshka<br>\nLicense: GPLv3</p>",
+		"<p>aThing is a thing that can be &lt;used&gt;.</p>",
+	} {
+		if !strings.Contains(library, expected) {
+			test.Errorf("library.html does not contain %s", expected)
+		}
+	}
+
+	// private sections and members are left out
+	for _, unexpected := range []string { "secret", "bHidden" } {
+		if strings.Contains(library, unexpected) {
+			test.Errorf("library.html contains %s", unexpected)
+		}
+	}
+}
+
+func TestParagraphs (test *testing.T) {
+	result  := paragraphs("one\ntwo\n\n  three\n\n\n")
+	correct := []string { "one two", "three" }
+	if strings.Join(result, "|") != strings.Join(correct, "|") {
+		test.Errorf("paragraphs: got %q, want %q", result, correct)
+	}
+}
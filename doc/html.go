@@ -0,0 +1,129 @@
+package doc
+
+import "fmt"
+import "html"
+import "strings"
+
+// html writes out a page as an HTML document. Links to other pages use the
+// given file extension.
+func (built page) html (extension string) (output string) {
+	builder := &strings.Builder { }
+	name := html.EscapeString(built.name)
+	fmt.Fprintf (
+		builder,
+		"<!DOCTYPE html>\n" +
+		"<html>\n" +
+		"<head>\n" +
+		"<meta charset=\"utf-8\">\n" +
+		"<title>%s</title>\n" +
+		"</head>\n" +
+		"<body>\n" +
+		"<h1>Module %s</h1>\n",
+		name, name)
+
+	details := []string { }
+	if built.author != "" {
+		details = append (
+			details,
+			"Author: " + html.EscapeString(built.author))
+	}
+	if built.license != "" {
+		details = append (
+			details,
+			"License: " + html.EscapeString(built.license))
+	}
+	if len(details) > 0 {
+		fmt.Fprintf (
+			builder, "<p>%s</p>\n",
+			strings.Join(details, "<br>\n"))
+	}
+
+	if len(built.requires) > 0 {
+		builder.WriteString("<h2>Requires</h2>\n<ul>\n")
+		for _, required := range built.requires {
+			fmt.Fprintf (
+				builder, "<li>%s</li>\n",
+				htmlCode([]span { required }, extension))
+		}
+		builder.WriteString("</ul>\n")
+	}
+
+	for _, current := range built.groups {
+		fmt.Fprintf(builder, "<h2>%s</h2>\n", current.title)
+		for _, documented := range current.entries {
+			htmlEntry(builder, documented, extension)
+		}
+	}
+
+	builder.WriteString("</body>\n</html>\n")
+	return builder.String()
+}
+
+// htmlEntry writes out the documentation of a single section.
+func htmlEntry (
+	builder    *strings.Builder,
+	documented entry,
+	extension  string,
+) {
+	fmt.Fprintf (
+		builder, "<h3 id=\"%s\">%s</h3>\n<pre>%s</pre>\n",
+		html.EscapeString(documented.anchor),
+		html.EscapeString(documented.name),
+		htmlCode(documented.signature, extension))
+
+	for _, paragraph := range paragraphs(documented.doc) {
+		fmt.Fprintf (
+			builder, "<p>%s</p>\n",
+			html.EscapeString(paragraph))
+	}
+
+	htmlMembers(builder, documented.members, extension)
+}
+
+// htmlMembers writes out a list of members, and any members within them.
+func htmlMembers (
+	builder   *strings.Builder,
+	members   []entry,
+	extension string,
+) {
+	if len(members) == 0 { return }
+
+	builder.WriteString("<ul>\n")
+	for _, member := range members {
+		fmt.Fprintf (
+			builder, "<li id=\"%s\">%s",
+			html.EscapeString(member.anchor),
+			htmlCode(member.signature, extension))
+
+		for index, paragraph := range paragraphs(member.doc) {
+			if index == 0 {
+				builder.WriteString(": ")
+			} else {
+				builder.WriteString("<br>\n")
+			}
+			builder.WriteString(html.EscapeString(paragraph))
+		}
+		builder.WriteString("\n")
+
+		htmlMembers(builder, member.members, extension)
+		builder.WriteString("</li>\n")
+	}
+	builder.WriteString("</ul>\n")
+}
+
+// htmlCode writes out a list of spans as code. Spans that link to something
+// are written as links.
+func htmlCode (spans []span, extension string) (output string) {
+	output = "<code>"
+	for _, current := range spans {
+		text := html.EscapeString(current.text)
+		if current.linked {
+			href := html.EscapeString(current.href(extension))
+			output += "<a href=\"" + href + "\">" + text + "</a>"
+		} else {
+			output += text
+		}
+	}
+	output += "</code>"
+	return
+}
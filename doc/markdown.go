@@ -0,0 +1,134 @@
+package doc
+
+import "fmt"
+import "strings"
+
+// markdown writes out a page as Markdown. Links to other pages use the given
+// file extension.
+func (built page) markdown (extension string) (output string) {
+	builder := &strings.Builder { }
+	fmt.Fprintf(builder, "# Module %s\n", markdownEscape(built.name))
+
+	if built.author != "" || built.license != "" {
+		builder.WriteString("\n")
+	}
+	if built.author != "" {
+		fmt.Fprintf (
+			builder, "Author: %s  \n",
+			markdownEscape(built.author))
+	}
+	if built.license != "" {
+		fmt.Fprintf (
+			builder, "License: %s  \n",
+			markdownEscape(built.license))
+	}
+
+	if len(built.requires) > 0 {
+		builder.WriteString("\n## Requires\n\n")
+		for _, required := range built.requires {
+			fmt.Fprintf (
+				builder, "- %s\n",
+				markdownCode([]span { required }, extension))
+		}
+	}
+
+	for _, current := range built.groups {
+		fmt.Fprintf(builder, "\n## %s\n", current.title)
+		for _, documented := range current.entries {
+			markdownEntry(builder, documented, extension)
+		}
+	}
+
+	return builder.String()
+}
+
+// markdownEntry writes out the documentation of a single section.
+func markdownEntry (
+	builder    *strings.Builder,
+	documented entry,
+	extension  string,
+) {
+	fmt.Fprintf (
+		builder, "\n### <a id=\"%s\"></a>%s\n\n%s\n",
+		documented.anchor, markdownEscape(documented.name),
+		markdownCode(documented.signature, extension))
+
+	for _, paragraph := range paragraphs(documented.doc) {
+		fmt.Fprintf(builder, "\n%s\n", markdownEscape(paragraph))
+	}
+
+	if len(documented.members) > 0 {
+		builder.WriteString("\n")
+		markdownMembers(builder, documented.members, 0, extension)
+	}
+}
+
+// markdownMembers writes out a list of members, and any members within them.
+func markdownMembers (
+	builder   *strings.Builder,
+	members   []entry,
+	indent    int,
+	extension string,
+) {
+	prefix := strings.Repeat("  ", indent)
+	for _, member := range members {
+		fmt.Fprintf (
+			builder, "%s- <a id=\"%s\"></a>%s",
+			prefix, member.anchor,
+			markdownCode(member.signature, extension))
+
+		for index, paragraph := range paragraphs(member.doc) {
+			if index == 0 {
+				builder.WriteString(": ")
+			} else {
+				builder.WriteString("\n\n" + prefix + "  ")
+			}
+			builder.WriteString(markdownEscape(paragraph))
+		}
+		builder.WriteString("\n")
+
+		markdownMembers(builder, member.members, indent + 1, extension)
+	}
+}
+
+// markdownCode writes out a list of spans as inline code. Spans that link to
+// something are written as links.
+func markdownCode (spans []span, extension string) (output string) {
+	plain := ""
+	flush := func () {
+		trimmed := strings.TrimLeft(plain, " ")
+		output += plain[:len(plain) - len(trimmed)]
+		if trimmed != "" { output += "`" + trimmed + "`" }
+		plain = ""
+	}
+
+	for _, current := range spans {
+		if !current.linked {
+			plain += current.text
+			continue
+		}
+
+		flush()
+		output +=
+			"[`" + current.text + "`](" +
+			current.href(extension) + ")"
+	}
+	flush()
+	return
+}
+
+// markdownEscape escapes characters in plain text that would otherwise be read
+// as Markdown or HTML.
+func markdownEscape (text string) (escaped string) {
+	return markdownEscaper.Replace(text)
+}
+
+var markdownEscaper = strings.NewReplacer (
+	"\\", "\\\\",
+	"`",  "\\`",
+	"*",  "\\*",
+	"_",  "\\_",
+	"[",  "\\[",
+	"]",  "\\]",
+	"<",  "&lt;",
+	"&",  "&amp;")
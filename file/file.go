@@ -2,6 +2,7 @@ package file
 
 import "os"
 import "bufio"
+import "strings"
 
 // File represents a read only file that can print out formatted errors.
 type File struct {
@@ -29,6 +30,18 @@ func Open (path string) (file *File, err error) {
 	return
 }
 
+// Load returns a new File that reads from contents instead of from the
+// filesystem. The path is only used when reporting errors. This is useful for
+// files that are being edited, and have not been saved yet.
+func Load (path string, contents string) (file *File) {
+	file = &File {
+		path:   path,
+		lines:  []string { "" },
+		reader: bufio.NewReader(strings.NewReader(contents)),
+	}
+	return
+}
+
 // Stat returns the FileInfo structure describing file. If there is an error, it
 // will be of type *PathError. 
 func (file *File) Stat () (fileInfo os.FileInfo, err error) {
@@ -106,6 +119,7 @@ func (file *File) ReadString (delimiter byte) (read string, err error) {
 // Close closes the file. After the file is closed, data that has been read will
 // still be retained, and errors can be reported.
 func (file *File) Close () {
+	if file.file == nil { return }
 	file.file.Close()
 }
 
@@ -125,7 +139,9 @@ func (file *File) Path () (path string) {
 	return file.path
 }
 
-// GetLine returns the line at the specified index.
+// GetLine returns the line at the specified index. If the line has not been
+// read, it returns an empty string.
 func (file *File) GetLine (index int) (line string) {
+	if index < 0 || index >= len(file.lines) { return "" }
 	return file.lines[index]
 }
@@ -11,6 +11,17 @@ type Location struct {
 	width  int
 }
 
+// NewLocation creates a location within a file. This is useful for restoring
+// locations that were saved elsewhere.
+func NewLocation (file *File, row, column, width int) (location Location) {
+	return Location {
+		file:   file,
+		row:    row,
+		column: column,
+		width:  width,
+	}
+}
+
 // File returns the file the location is in
 func (location Location) File () (file *File) {
 	return location.file
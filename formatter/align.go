@@ -0,0 +1,160 @@
+package formatter
+
+import "git.tebibyte.media/arf/arf/lexer"
+
+// sectionTracker keeps track of which part of the file each line is in.
+type sectionTracker struct {
+	inHeader bool
+
+	// kind is the keyword of the section that the current line is in.
+	kind string
+
+	// inBody is true if the current line is after the separator of a
+	// function section.
+	inBody bool
+}
+
+// advance updates the tracker to describe where a line is.
+func (tracker *sectionTracker) advance (current line) {
+	if current.kind != lineKindCode { return }
+
+	switch {
+	case tracker.inHeader:
+		if current.isSeparator() && current.indent == 0 {
+			tracker.inHeader = false
+		}
+
+	case current.isSectionStart():
+		tracker.kind   = current.texts[0]
+		tracker.inBody = false
+
+	case tracker.kind == "func" && current.indent == 1:
+		if current.isSeparator() { tracker.inBody = true }
+	}
+}
+
+// alignMembers pads the names of consecutive members that have values, so that
+// the values line up in a column.
+func (formatter *formattingOperation) alignMembers () {
+	tracker := sectionTracker { inHeader: true }
+	run := []int { }
+
+	finishRun := func () {
+		if len(run) > 1 {
+			keyWidth := 0
+			for _, index := range run {
+				current := formatter.lines[index]
+				key := width(renderTokens (
+					current.tokens[:current.keyEnd],
+					current.texts))
+				if key > keyWidth { keyWidth = key }
+			}
+			for _, index := range run {
+				formatter.lines[index].keyWidth = keyWidth
+			}
+		} else if len(run) == 1 {
+			formatter.lines[run[0]].keyEnd = 0
+		}
+		run = run[:0]
+	}
+
+	for index := range formatter.lines {
+		current := &formatter.lines[index]
+		tracker.advance(*current)
+
+		keyEnd, isMember := tracker.memberKey(*current)
+		hasValue := isMember && keyEnd < len(current.tokens)
+		if !hasValue {
+			finishRun()
+			continue
+		}
+
+		if len(run) > 0 {
+			previous := formatter.lines[run[len(run) - 1]]
+			if previous.indent != current.indent { finishRun() }
+		}
+
+		current.keyEnd = keyEnd
+		run = append(run, index)
+	}
+	finishRun()
+}
+
+// memberKey determines whether a line declares a member that can be given a
+// value, such as an enum member, an object member, or the input or output of a
+// function. If it does, keyEnd is the index of the first token of the value.
+func (tracker sectionTracker) memberKey (
+	current line,
+) (
+	keyEnd   int,
+	isMember bool,
+) {
+	tokens := current.tokens
+	if current.kind != lineKindCode || len(tokens) < 2 { return }
+	if !tokens[1].Is(lexer.TokenKindName)              { return }
+
+	first := tokens[0].Kind()
+	argument :=
+		first == lexer.TokenKindGreaterThan ||
+		first == lexer.TokenKindLessThan ||
+		first == lexer.TokenKindAt
+
+	switch tracker.kind {
+	case "enum":
+		if current.indent != 1 || first != lexer.TokenKindMinus {
+			return
+		}
+		return 2, true
+
+	case "type":
+		if current.indent != 1 || first != lexer.TokenKindPermission {
+			return
+		}
+
+	case "func":
+		if tracker.inBody || current.indent != 1 || !argument { return }
+
+	case "face":
+		if current.indent < 1 || current.indent > 2 || !argument {
+			return
+		}
+
+	default:
+		return
+	}
+
+	return declarationEnd(tokens, 1), true
+}
+
+// declarationEnd returns the index of the token after the name and type of a
+// declaration that starts at index, such as someName:{Int ..}:mut.
+func declarationEnd (tokens []lexer.Token, index int) (end int) {
+	end = index + 1
+	for end + 1 < len(tokens) && tokens[end].Is(lexer.TokenKindColon) {
+		end ++
+
+		switch tokens[end].Kind() {
+		case lexer.TokenKindLBrace:
+			depth := 0
+			for end < len(tokens) {
+				depth += bracketDepth(tokens[end:end + 1])
+				end ++
+				if depth == 0 { break }
+			}
+
+		case lexer.TokenKindName:
+			end ++
+			for end + 1 < len(tokens) {
+				dotted :=
+					tokens[end].Is(lexer.TokenKindDot) &&
+					tokens[end + 1].Is(lexer.TokenKindName)
+				if !dotted { break }
+				end += 2
+			}
+
+		case lexer.TokenKindInt, lexer.TokenKindUInt:
+			end ++
+		}
+	}
+	return
+}
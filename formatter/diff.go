@@ -0,0 +1,145 @@
+package formatter
+
+import "fmt"
+import "strings"
+
+// diffContext is the amount of unchanged lines shown around each change.
+const diffContext = 3
+
+// edit is a single line of a diff.
+type edit struct {
+	kind rune
+	text string
+
+	// before and after are the indices of the line in the old and new
+	// text. Only the one that the line is in is meaningful.
+	before int
+	after  int
+}
+
+// Diff returns the changes between two versions of a file in the unified diff
+// format, with the path used as the name of both versions. If the versions are
+// the same, it returns an empty string.
+func Diff (path string, before string, after string) (diff string) {
+	if before == after { return }
+
+	edits := diffLines(splitLines(before), splitLines(after))
+
+	builder := strings.Builder { }
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", path, path)
+
+	// find each run of changes, and print it along with the unchanged
+	// lines around it
+	index := 0
+	for index < len(edits) {
+		if edits[index].kind == ' ' {
+			index ++
+			continue
+		}
+
+		start := index - diffContext
+		if start < 0 { start = 0 }
+
+		// extend the hunk until there are enough unchanged lines in a
+		// row to end it
+		end       := index
+		unchanged := 0
+		for end < len(edits) && unchanged <= diffContext * 2 {
+			if edits[end].kind == ' ' {
+				unchanged ++
+			} else {
+				unchanged = 0
+			}
+			end ++
+		}
+		if unchanged > diffContext { end -= unchanged - diffContext }
+
+		writeHunk(&builder, edits[start:end])
+		index = end
+	}
+
+	return builder.String()
+}
+
+// writeHunk writes a hunk header followed by its lines.
+func writeHunk (builder *strings.Builder, hunk []edit) {
+	beforeStart, beforeLength := -1, 0
+	afterStart,  afterLength  := -1, 0
+	for _, line := range hunk {
+		if line.kind != '+' {
+			if beforeStart < 0 { beforeStart = line.before }
+			beforeLength ++
+		}
+		if line.kind != '-' {
+			if afterStart < 0 { afterStart = line.after }
+			afterLength ++
+		}
+	}
+
+	// lines are counted from one, unless a side of the hunk is empty, in
+	// which case the line before it is given instead
+	if beforeStart < 0 { beforeStart = hunk[0].before - 1 }
+	if afterStart  < 0 { afterStart  = hunk[0].after  - 1 }
+
+	fmt.Fprintf (
+		builder, "@@ -%d,%d +%d,%d @@\n",
+		beforeStart + 1, beforeLength,
+		afterStart + 1, afterLength)
+	for _, line := range hunk {
+		builder.WriteRune(line.kind)
+		builder.WriteString(line.text)
+		builder.WriteRune('\n')
+	}
+}
+
+// splitLines splits text into lines, leaving out the newline at the end.
+func splitLines (text string) (lines []string) {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" { return }
+	return strings.Split(text, "\n")
+}
+
+// diffLines finds the smallest set of lines that need to be removed from and
+// added to before in order to make it into after, using the longest common
+// subsequence between them.
+func diffLines (before []string, after []string) (edits []edit) {
+	// common[i][j] is the length of the longest common subsequence of
+	// before[i:] and after[j:]
+	common := make([][]int, len(before) + 1)
+	for index := range common {
+		common[index] = make([]int, len(after) + 1)
+	}
+	for i := len(before) - 1; i >= 0; i -- {
+		for j := len(after) - 1; j >= 0; j -- {
+			if before[i] == after[j] {
+				common[i][j] = common[i + 1][j + 1] + 1
+			} else if common[i + 1][j] >= common[i][j + 1] {
+				common[i][j] = common[i + 1][j]
+			} else {
+				common[i][j] = common[i][j + 1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(before) || j < len(after) {
+		switch {
+		case i < len(before) && j < len(after) && before[i] == after[j]:
+			edits = append(edits, edit { ' ', before[i], i, j })
+			i ++
+			j ++
+
+		case
+			j >= len(after) ||
+			i < len(before) && common[i + 1][j] >= common[i][j + 1]:
+
+			edits = append(edits, edit { '-', before[i], i, j })
+			i ++
+
+		default:
+			edits = append(edits, edit { '+', after[j], i, j })
+			j ++
+		}
+	}
+	return
+}
@@ -0,0 +1,263 @@
+/*
+Package formatter implements a canonical source formatter for the ARF language.
+The function Format takes in the text of an ARF file, and returns the same code
+laid out the way the rest of the code base writes it.
+
+The formatter works on tokens rather than on a syntax tree, so that comments,
+blank lines, and the order of sections are kept as they are. It normalizes the
+spacing between tokens and the indentation of each line, leaves exactly one
+blank line between sections, aligns the values of consecutive members the way
+the hand-written test files do, and wraps phrases that are too long to fit on
+one line. Formatting already formatted code does not change it.
+*/
+package formatter
+
+import "strings"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+
+// maxWidth is the width that lines are wrapped to, where tabs are tabWidth
+// columns wide.
+const maxWidth = 80
+const tabWidth = 8
+
+// lineKind determines what a line of source code contains.
+type lineKind int
+
+const (
+	lineKindCode lineKind = iota
+	lineKindComment
+	lineKindBlank
+)
+
+// line is a single line of source code, along with everything needed to print
+// it again.
+type line struct {
+	kind    lineKind
+	indent  int
+	tokens  []lexer.Token
+	texts   []string
+	comment string
+
+	// if keyEnd is above zero, the tokens before it are padded to keyWidth
+	// so that the values after them line up with the lines around it.
+	keyEnd   int
+	keyWidth int
+}
+
+// isSectionStart returns whether the line starts a new top level section.
+func (line line) isSectionStart () (is bool) {
+	is =
+		line.kind   == lineKindCode &&
+		line.indent == 0 &&
+		line.tokens[0].Is(lexer.TokenKindName)
+	return
+}
+
+// isSeparator returns whether the line is a lone --- separator.
+func (line line) isSeparator () (is bool) {
+	is =
+		line.kind == lineKindCode &&
+		len(line.tokens) == 1 &&
+		line.tokens[0].Is(lexer.TokenKindSeparator)
+	return
+}
+
+// formattingOperation holds information about an ongoing formatting operation.
+type formattingOperation struct {
+	source []rune
+	rows   [][]rune
+
+	// rowStarts contains the offset into source that each row starts at.
+	rowStarts []int
+
+	lines []line
+}
+
+// Format formats the source code of an ARF file. The path is only used when
+// reporting errors. If the code cannot be tokenized, it is returned unchanged
+// along with the error.
+func Format (path string, source string) (output string, err error) {
+	tokens, err := lexer.Tokenize(file.Load(path, source))
+	if err != nil {
+		output = source
+		return
+	}
+
+	formatter := formattingOperation { source: []rune(source) }
+	formatter.splitRows()
+	formatter.collectLines(tokens)
+	formatter.normalizeBlankLines()
+	formatter.alignMembers()
+	formatter.wrapPhrases()
+	output = formatter.print()
+	return
+}
+
+// splitRows splits the source code into rows, recording where each one starts.
+func (formatter *formattingOperation) splitRows () {
+	start := 0
+	for index, char := range formatter.source {
+		if char != '\n' { continue }
+		formatter.rows = append (
+			formatter.rows,
+			formatter.source[start:index])
+		formatter.rowStarts = append(formatter.rowStarts, start)
+		start = index + 1
+	}
+	formatter.rows      = append(formatter.rows, formatter.source[start:])
+	formatter.rowStarts = append(formatter.rowStarts, start)
+}
+
+// tokenText returns the text of a token, exactly as it is written in the source
+// code.
+func (formatter *formattingOperation) tokenText (
+	token lexer.Token,
+) (
+	text string,
+) {
+	location := token.Location()
+	start := formatter.rowStarts[location.Row()] + location.Column()
+	end   := start + location.Width()
+	if end > len(formatter.source) { end = len(formatter.source) }
+	return string(formatter.source[start:end])
+}
+
+// collectLines groups tokens into lines, and finds the blank lines that the
+// lexer leaves out.
+func (formatter *formattingOperation) collectLines (tokens []lexer.Token) {
+	byRow   := make([]line, len(formatter.rows))
+	covered := make([]bool, len(formatter.rows))
+
+	for _, token := range tokens {
+		row := token.Location().Row()
+		switch token.Kind() {
+		case lexer.TokenKindNewline:
+			continue
+		case lexer.TokenKindIndent:
+			byRow[row].indent = token.Value().(int)
+			continue
+		case lexer.TokenKindComment:
+			byRow[row].comment = strings.TrimRight (
+				formatter.tokenText(token),
+				" \t\r")
+			continue
+		}
+
+		text := formatter.tokenText(token)
+		byRow[row].tokens = append(byRow[row].tokens, token)
+		byRow[row].texts  = append(byRow[row].texts, text)
+
+		// strings may span several rows, which must not be mistaken
+		// for blank lines
+		rowsSpanned := strings.Count(text, "\n")
+		for offset := 1; offset <= rowsSpanned; offset ++ {
+			covered[row + offset] = true
+		}
+	}
+
+	// the first row is always :arf, so it is skipped
+	for row := 1; row < len(formatter.rows); row ++ {
+		if covered[row] { continue }
+		current := byRow[row]
+
+		switch {
+		case len(current.tokens) > 0:
+			current.kind = lineKindCode
+		case current.comment != "":
+			current.kind = lineKindComment
+		default:
+			current.kind = lineKindBlank
+		}
+
+		formatter.lines = append(formatter.lines, current)
+	}
+}
+
+// normalizeBlankLines removes blank lines from the start and end of the file,
+// collapses runs of blank lines into one, and makes sure that there is exactly
+// one blank line before each section, and before any comments directly above
+// it.
+func (formatter *formattingOperation) normalizeBlankLines () {
+	lines := []line { }
+	inHeader := true
+	for _, current := range formatter.lines {
+		previousBlank :=
+			len(lines) == 0 ||
+			lines[len(lines) - 1].kind == lineKindBlank
+
+		switch {
+		case current.kind == lineKindBlank:
+			if previousBlank { continue }
+
+		case inHeader:
+			// the metadata and the separator after it are kept
+			// together
+			if previousBlank && len(lines) > 0 {
+				lines = lines[:len(lines) - 1]
+			}
+			if current.isSeparator() && current.indent == 0 {
+				inHeader = false
+			}
+
+		case current.isSectionStart():
+			// find the first line of the comments directly above
+			// the section
+			start := len(lines)
+			for start > 0 &&
+				lines[start - 1].kind   == lineKindComment &&
+				lines[start - 1].indent == 0 {
+				start --
+			}
+
+			if start > 0 && lines[start - 1].kind != lineKindBlank {
+				lines = append(lines[:start], append (
+					[]line { { kind: lineKindBlank } },
+					lines[start:]...)...)
+			}
+		}
+
+		lines = append(lines, current)
+	}
+
+	for len(lines) > 0 && lines[len(lines) - 1].kind == lineKindBlank {
+		lines = lines[:len(lines) - 1]
+	}
+	formatter.lines = lines
+}
+
+// print renders all lines, and returns the formatted file.
+func (formatter *formattingOperation) print () (output string) {
+	builder := strings.Builder { }
+	builder.WriteString(":arf\n")
+	for _, current := range formatter.lines {
+		builder.WriteString(current.render())
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// render returns the formatted text of a line, including its indentation and
+// comment.
+func (line line) render () (output string) {
+	if line.kind == lineKindBlank { return }
+
+	code := ""
+	if line.keyEnd > 0 && line.keyEnd < len(line.tokens) {
+		key   := renderTokens(line.tokens[:line.keyEnd], line.texts)
+		value := renderTokens (
+			line.tokens[line.keyEnd:],
+			line.texts[line.keyEnd:])
+		padding := strings.Repeat(" ", line.keyWidth - width(key) + 1)
+		code = key + padding + value
+	} else {
+		code = renderTokens(line.tokens, line.texts)
+	}
+
+	output = strings.Repeat("\t", line.indent) + code
+	if line.comment != "" {
+		if code != "" { output += " " }
+		output += line.comment
+	}
+	return
+}
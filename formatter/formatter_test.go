@@ -0,0 +1,126 @@
+package formatter
+
+import "os"
+import "testing"
+import "io/fs"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+
+func TestFormat (test *testing.T) {
+	input, err := os.ReadFile("../tests/formatter/input.arf")
+	if err != nil { test.Fatal(err) }
+	correct, err := os.ReadFile("../tests/formatter/output.arf")
+	if err != nil { test.Fatal(err) }
+
+	output, err := Format("input.arf", string(input))
+	if err != nil { test.Fatal(err) }
+	if output != string(correct) {
+		test.Log("formatted output does not match")
+		test.Log(Diff("output.arf", string(correct), output))
+		test.Fail()
+	}
+}
+
+// TestIdempotent formats every ARF file in the tests directory, and makes sure
+// that formatting them twice does nothing more than formatting them once, and
+// that formatting does not change their meaning.
+func TestIdempotent (test *testing.T) {
+	err := filepath.WalkDir ("../tests", func (
+		path  string,
+		entry fs.DirEntry,
+		err   error,
+	) error {
+		if err != nil { return err }
+		if entry.IsDir() || filepath.Ext(path) != ".arf" { return nil }
+
+		source, err := os.ReadFile(path)
+		if err != nil { return err }
+
+		// files that cannot be tokenized are tests for the lexer
+		sourceTokens, err := tokenize(path, string(source))
+		if err != nil { return nil }
+
+		once, err := Format(path, string(source))
+		if err != nil { return err }
+		twice, err := Format(path, once)
+		if err != nil { return err }
+
+		if once != twice {
+			test.Errorf("formatting %s is not idempotent:\n%s",
+				path, Diff(path, once, twice))
+			return nil
+		}
+
+		formattedTokens, err := tokenize(path, once)
+		if err != nil { return err }
+		if !tokensEqual(sourceTokens, formattedTokens) {
+			test.Errorf("formatting %s changes its tokens", path)
+		}
+		return nil
+	})
+	if err != nil { test.Fatal(err) }
+}
+
+func TestDiff (test *testing.T) {
+	before := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n"
+	after  := "a\nb\nC\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	correct :=
+		"--- file\n" +
+		"+++ file\n" +
+		"@@ -1,6 +1,6 @@\n" +
+		" a\n" +
+		" b\n" +
+		"-c\n" +
+		"+C\n" +
+		" d\n" +
+		" e\n" +
+		" f\n" +
+		"@@ -8,3 +8,4 @@\n" +
+		" h\n" +
+		" i\n" +
+		" j\n" +
+		"+k\n"
+
+	result := Diff("file", before, after)
+	if result != correct {
+		test.Errorf("diff:\ncorrect:\n%s\nresult:\n%s", correct, result)
+	}
+
+	if Diff("file", before, before) != "" {
+		test.Error("diff of identical files is not empty")
+	}
+}
+
+// tokenize returns the tokens of some source code, without any newlines or
+// indents. Square brackets are also left out, because they are added around
+// phrases that get wrapped, and so are comments, because trailing whitespace is
+// removed from them.
+func tokenize (path string, source string) (tokens []lexer.Token, err error) {
+	all, err := lexer.Tokenize(file.Load(path, source))
+	for _, token := range all {
+		switch token.Kind() {
+		case
+			lexer.TokenKindNewline,
+			lexer.TokenKindIndent,
+			lexer.TokenKindComment,
+			lexer.TokenKindLBracket,
+			lexer.TokenKindRBracket:
+
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return
+}
+
+// tokensEqual returns whether two lists of tokens have the same kinds and
+// values.
+func tokensEqual (left []lexer.Token, right []lexer.Token) (equal bool) {
+	if len(left) != len(right) { return }
+	for index := range left {
+		if !left[index].Equals(right[index]) { return }
+	}
+	equal = true
+	return
+}
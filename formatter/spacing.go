@@ -0,0 +1,70 @@
+package formatter
+
+import "strings"
+import "unicode/utf8"
+import "git.tebibyte.media/arf/arf/lexer"
+
+// renderTokens joins a line of tokens together, putting a single space between
+// them wherever one belongs.
+func renderTokens (tokens []lexer.Token, texts []string) (output string) {
+	builder := strings.Builder { }
+	for index := range tokens {
+		if index > 0 && spaceBefore(tokens, index) {
+			builder.WriteRune(' ')
+		}
+		builder.WriteString(texts[index])
+	}
+	return builder.String()
+}
+
+// spaceBefore returns whether there should be a space between a token and the
+// one before it. Spaces are left out just inside of brackets, and around the
+// colons and dots that join parts of names and types together.
+func spaceBefore (tokens []lexer.Token, index int) (space bool) {
+	previous := tokens[index - 1]
+	current  := tokens[index]
+
+	// two dots next to each other would become an elipsis
+	if previous.Is(lexer.TokenKindDot) && current.Is(lexer.TokenKindDot) {
+		space = true
+		return
+	}
+
+	switch previous.Kind() {
+	case
+		lexer.TokenKindLBracket,
+		lexer.TokenKindLParen,
+		lexer.TokenKindLBrace,
+		lexer.TokenKindDot:
+
+		return
+
+	case lexer.TokenKindColon:
+		// a colon at the start of a phrase begins a switch case, and
+		// is followed by its values
+		space =
+			index - 1 == 0 ||
+			tokens[index - 2].Is(lexer.TokenKindLBracket)
+		return
+	}
+
+	switch current.Kind() {
+	case
+		lexer.TokenKindRBracket,
+		lexer.TokenKindRParen,
+		lexer.TokenKindRBrace,
+		lexer.TokenKindDot,
+		lexer.TokenKindComma,
+		lexer.TokenKindColon:
+
+		return
+	}
+
+	space = true
+	return
+}
+
+// width returns how many columns a piece of text takes up when it is printed.
+func width (text string) (columns int) {
+	return utf8.RuneCountInString(text)
+}
@@ -0,0 +1,186 @@
+package formatter
+
+import "git.tebibyte.media/arf/arf/lexer"
+
+// wrapPhrases breaks phrases within function bodies that are too wide to fit on
+// one line. Each of them is put in brackets, and its arguments are spread over
+// as many lines as needed. Phrases that already span several lines are left as
+// they are.
+func (formatter *formattingOperation) wrapPhrases () {
+	tracker := sectionTracker { inHeader: true }
+	lines := []line { }
+	depth := 0
+
+	for _, current := range formatter.lines {
+		tracker.advance(current)
+		depthBefore := depth
+		depth += bracketDepth(current.tokens)
+
+		code := renderTokens(current.tokens, current.texts)
+		wrappable :=
+			current.kind == lineKindCode &&
+			tracker.kind == "func" &&
+			tracker.inBody &&
+			current.indent > 0 &&
+			depthBefore == 0 && depth == 0 &&
+			current.indent * tabWidth + width(code) > maxWidth
+
+		if wrappable {
+			lines = append(lines, wrapPhrase(current)...)
+		} else {
+			lines = append(lines, current)
+		}
+	}
+
+	formatter.lines = lines
+}
+
+// bracketDepth returns how many more brackets a list of tokens opens than it
+// closes.
+func bracketDepth (tokens []lexer.Token) (depth int) {
+	for _, token := range tokens {
+		switch token.Kind() {
+		case
+			lexer.TokenKindLBracket,
+			lexer.TokenKindLParen,
+			lexer.TokenKindLBrace:
+
+			depth ++
+		case
+			lexer.TokenKindRBracket,
+			lexer.TokenKindRParen,
+			lexer.TokenKindRBrace:
+
+			depth --
+		}
+	}
+	return
+}
+
+// word is a range of tokens that must stay together on one line.
+type word struct {
+	start int
+	end   int
+	text  string
+}
+
+// wrapPhrase puts a phrase in brackets, and splits it across several lines
+// between its arguments. If the phrase cannot be split, it is returned as is.
+func wrapPhrase (phrase line) (wrapped []line) {
+	tokens := phrase.tokens
+	texts  := phrase.texts
+
+	// anything after the return direction stays on the last line
+	phraseEnd := len(tokens)
+	depth     := 0
+	for index, token := range tokens {
+		depth += bracketDepth(tokens[index:index + 1])
+		if depth == 0 && token.Is(lexer.TokenKindReturnDirection) {
+			phraseEnd = index
+			break
+		}
+	}
+	inner      := tokens[:phraseEnd]
+	innerTexts := texts[:phraseEnd]
+	if matchingBracket(inner) == len(inner) - 1 {
+		inner      = inner[1:len(inner) - 1]
+		innerTexts = innerTexts[1:len(innerTexts) - 1]
+	}
+
+	words := splitWords(inner, innerTexts)
+	if len(words) < 2 { return []line { phrase } }
+
+	closing := "]"
+	if phraseEnd < len(tokens) {
+		closing += " " + renderTokens (
+			tokens[phraseEnd:],
+			texts[phraseEnd:])
+	}
+
+	current := line {
+		kind:   lineKindCode,
+		indent: phrase.indent,
+		tokens: []lexer.Token {
+			lexer.NewToken(lexer.TokenKindLBracket, nil),
+		},
+		texts: []string { "[" },
+	}
+	currentText := "["
+	wordsOnLine := 0
+
+	for index, word := range words {
+		addition := word.text
+		if index == len(words) - 1 { addition += closing }
+		if wordsOnLine > 0 {
+			addition = " " + addition
+			tooWide :=
+				current.indent * tabWidth +
+				width(currentText + addition) > maxWidth
+			if tooWide {
+				wrapped = append(wrapped, current)
+				current = line {
+					kind:   lineKindCode,
+					indent: phrase.indent + 1,
+				}
+				currentText = ""
+				wordsOnLine = 0
+			}
+		}
+
+		if wordsOnLine > 0 { currentText += " " }
+		currentText += word.text
+		wordsOnLine ++
+		current.tokens = append (
+			current.tokens,
+			inner[word.start:word.end]...)
+		current.texts = append (
+			current.texts,
+			innerTexts[word.start:word.end]...)
+	}
+
+	current.tokens = append (
+		current.tokens,
+		lexer.NewToken(lexer.TokenKindRBracket, nil))
+	current.texts = append(current.texts, "]")
+	current.tokens = append(current.tokens, tokens[phraseEnd:]...)
+	current.texts  = append(current.texts, texts[phraseEnd:]...)
+	current.comment = phrase.comment
+	wrapped = append(wrapped, current)
+	return
+}
+
+// matchingBracket returns the index of the bracket that closes a phrase that
+// starts with an opening bracket, or -1 if it does not start with one.
+func matchingBracket (tokens []lexer.Token) (index int) {
+	if len(tokens) == 0 || !tokens[0].Is(lexer.TokenKindLBracket) {
+		return -1
+	}
+
+	depth := 0
+	for index = range tokens {
+		depth += bracketDepth(tokens[index:index + 1])
+		if depth == 0 { return }
+	}
+	return -1
+}
+
+// splitWords splits the tokens of a phrase into its command and arguments.
+func splitWords (tokens []lexer.Token, texts []string) (words []word) {
+	depth := 0
+	for index := range tokens {
+		boundary :=
+			index == 0 ||
+			depth == 0 && spaceBefore(tokens, index)
+		if boundary {
+			words = append(words, word { start: index })
+		}
+		depth += bracketDepth(tokens[index:index + 1])
+
+		last := &words[len(words) - 1]
+		last.end  = index + 1
+		last.text = renderTokens (
+			tokens[last.start:last.end],
+			texts[last.start:])
+	}
+	return
+}
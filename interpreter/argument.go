@@ -0,0 +1,407 @@
+package interpreter
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// isUntyped returns whether an argument is a constant that takes on the type of
+// whatever it is passed to.
+func isUntyped (argument analyzer.Argument) (untyped bool) {
+	switch argument.(type) {
+	case
+		analyzer.IntLiteral,
+		analyzer.UIntLiteral,
+		analyzer.FloatLiteral,
+		analyzer.StringLiteral,
+		analyzer.BoolLiteral:
+
+		untyped = true
+	case analyzer.OperatorPhrase:
+		untyped = argument.(analyzer.OperatorPhrase).Untyped()
+	}
+	return
+}
+
+// evaluateAs evaluates an argument that is being passed to something of the
+// specified type. Untyped constants are converted to that type.
+func (operation *interpretingOperation) evaluateAs (
+	argument analyzer.Argument,
+	what     analyzer.Type,
+) (
+	value any,
+	err   error,
+) {
+	if !isUntyped(argument) {
+		value, err = operation.evaluate(argument)
+		return
+	}
+
+	constant, err := argument.Resolve()
+	if err != nil { return }
+	value, err = operation.literal(constant, what)
+	return
+}
+
+// evaluate evaluates an argument as its own type.
+func (operation *interpretingOperation) evaluate (
+	argument analyzer.Argument,
+) (
+	value any,
+	err   error,
+) {
+	switch argument.(type) {
+	case
+		analyzer.IntLiteral,
+		analyzer.UIntLiteral,
+		analyzer.FloatLiteral,
+		analyzer.StringLiteral,
+		analyzer.BoolLiteral:
+
+		value, err = operation.literal(argument, argument.What())
+
+	case
+		analyzer.VariableReference,
+		analyzer.DataReference,
+		analyzer.MemberAccess,
+		analyzer.Dereference:
+
+		var place pointer
+		place, err = operation.locate(argument)
+		if err != nil { return }
+		value = place.target().value
+
+	case analyzer.EnumMemberReference:
+		reference := argument.(analyzer.EnumMemberReference)
+		value, err = operation.evaluateAs (
+			reference.Member().Value(),
+			reference.What())
+
+	case analyzer.ReferencePhrase:
+		value, err = operation.locate (
+			argument.(analyzer.ReferencePhrase).Operand())
+
+	case analyzer.CastPhrase:
+		value, err = operation.cast(argument.(analyzer.CastPhrase))
+
+	case analyzer.OperatorPhrase:
+		value, err = operation.operatorPhrase (
+			argument.(analyzer.OperatorPhrase))
+
+	case analyzer.CallPhrase:
+		var outputs []any
+		phrase := argument.(analyzer.CallPhrase)
+		outputs, err = operation.callPhrase(phrase)
+		if err != nil { return }
+		if len(outputs) > 0 { value = outputs[0] }
+
+	default:
+		err = argument.NewError (
+			"this cannot be interpreted",
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// literal converts a literal into a value of the specified type.
+func (operation *interpretingOperation) literal (
+	argument analyzer.Argument,
+	what     analyzer.Type,
+) (
+	value any,
+	err   error,
+) {
+	var source scalar
+	var raw    any
+	switch argument.(type) {
+	case analyzer.StringLiteral:
+		value, err = operation.text(argument.Value().(string), what)
+		return
+	case analyzer.IntLiteral:
+		source = scalar { signed: true, width: 64 }
+		raw    = uint64(argument.Value().(int64))
+	case analyzer.UIntLiteral:
+		source = scalar { width: 64 }
+		raw    = argument.Value().(uint64)
+	case analyzer.FloatLiteral:
+		source = scalar { kind: scalarFloat, width: 64 }
+		raw    = argument.Value().(float64)
+	case analyzer.BoolLiteral:
+		source = scalar { kind: scalarBool }
+		raw    = argument.Value().(bool)
+	}
+
+	shape := operation.shapeOf(what)
+	if shape.kind != shapeScalar {
+		err = argument.NewError (
+			"cannot interpret this as " + what.Describe(),
+			infoerr.ErrorKindError)
+		return
+	}
+
+	value, valid := shape.scalar.convert(raw, source)
+	if !valid {
+		err = argument.NewError (
+			fmt.Sprint (
+				"cannot convert ", raw, " to ",
+				what.Describe()),
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// text converts the text of a string literal into a value of the specified
+// type. Fixed length arrays are padded with zeros if the text is too short, and
+// cut off if it is too long. A single number is set to the first character.
+func (operation *interpretingOperation) text (
+	text string,
+	what analyzer.Type,
+) (
+	value any,
+	err   error,
+) {
+	shape := operation.shapeOf(what)
+	if shape.kind == shapeScalar {
+		characters := operation.characters(text, shape.scalar)
+		if len(characters) > 0 {
+			value = characters[0]
+		} else {
+			value = shape.scalar.zero()
+		}
+		return
+	}
+
+	element    := operation.shapeOf(shape.element).scalar
+	characters := operation.characters(text, element)
+	if shape.kind == shapeArray {
+		elements := make(array, shape.length)
+		for index := range elements {
+			elements[index] = &cell { element.zero() }
+			if index < len(characters) {
+				elements[index].value = characters[index]
+			}
+		}
+		value = elements
+		return
+	}
+
+	elements := make([]*cell, len(characters))
+	for index, character := range characters {
+		elements[index] = &cell { character }
+	}
+	value = pointer { cells: elements }
+	return
+}
+
+// characters splits text into values that can be stored as the specified
+// scalar. If it is eight bits wide, the text is stored as UTF-8. Otherwise,
+// each character is stored as a code point.
+func (operation *interpretingOperation) characters (
+	text        string,
+	description scalar,
+) (
+	characters []any,
+) {
+	source := scalar { width: 32 }
+	if description.width == 8 {
+		for _, character := range []byte(text) {
+			code     := uint64(character)
+			value, _ := description.convert(code, source)
+			characters = append(characters, value)
+		}
+	} else {
+		for _, character := range []rune(text) {
+			code     := uint64(character)
+			value, _ := description.convert(code, source)
+			characters = append(characters, value)
+		}
+	}
+	return
+}
+
+// locate finds the cell that an argument refers to, and returns a pointer to
+// it. Arguments that are not stored anywhere are placed in a new cell.
+func (operation *interpretingOperation) locate (
+	argument analyzer.Argument,
+) (
+	place pointer,
+	err   error,
+) {
+	switch argument.(type) {
+	case analyzer.VariableReference:
+		reference := argument.(analyzer.VariableReference)
+		var variable *cell
+		variable, err = operation.variable (
+			reference.Variable(),
+			reference.Declaration())
+		if err != nil { return }
+		place = pointer { cells: []*cell { variable } }
+
+	case analyzer.DataReference:
+		var data *cell
+		data, err = operation.dataSection (
+			argument.(analyzer.DataReference).Section(),
+			argument)
+		if err != nil { return }
+		place = pointer { cells: []*cell { data } }
+
+	case analyzer.MemberAccess:
+		access := argument.(analyzer.MemberAccess)
+		var base any
+		base, err = operation.evaluate(access.Base())
+		if err != nil { return }
+
+		// members can be selected through a pointer to an object
+		if basePointer, isPointer := base.(pointer); isPointer {
+			basePointer, err = operation.offset (
+				basePointer, 0, argument)
+			if err != nil { return }
+			base = basePointer.target().value
+		}
+
+		member := base.(object)[access.Member().Name()]
+		place = pointer { cells: []*cell { member } }
+
+	case analyzer.Dereference:
+		dereference := argument.(analyzer.Dereference)
+		var operand any
+		operand, err = operation.evaluate(dereference.Operand())
+		if err != nil { return }
+
+		switch operand.(type) {
+		case array:
+			place = pointer {
+				cells: operand.(array),
+				index: int(dereference.Offset()),
+			}
+		case pointer:
+			place, err = operation.offset (
+				operand.(pointer),
+				dereference.Offset(),
+				argument)
+		}
+
+	default:
+		var value any
+		value, err = operation.evaluate(argument)
+		if err != nil { return }
+		place = pointer { cells: []*cell { { value } } }
+	}
+	return
+}
+
+// offset moves a pointer forward by the specified amount of elements, and
+// makes sure that it still points to something.
+func (operation *interpretingOperation) offset (
+	base   pointer,
+	amount uint64,
+	where  node,
+) (
+	moved pointer,
+	err   error,
+) {
+	if base.cells == nil {
+		err = where.NewError (
+			"cannot dereference a nil pointer",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	moved = base
+	moved.index += int(amount)
+	if moved.target() == nil {
+		err = where.NewError (
+			fmt.Sprint (
+				"offset ", amount, " is out of bounds for an ",
+				"array of length ", len(base.elements())),
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// variable returns the cell that a variable is stored in. If the variable is
+// being declared, or has not been used yet, a new cell is made for it.
+func (operation *interpretingOperation) variable (
+	variable    *analyzer.Variable,
+	declaration bool,
+) (
+	place *cell,
+	err   error,
+) {
+	current := operation.frame()
+	place, exists := current.variables[variable]
+	if exists && !declaration { return }
+
+	value, err := operation.zero(variable.What())
+	if err != nil { return }
+	place = &cell { value }
+	current.variables[variable] = place
+	return
+}
+
+// dataSection returns the cell that a data section is stored in. The first time
+// a data section is used, its value is evaluated.
+func (operation *interpretingOperation) dataSection (
+	section *analyzer.DataSection,
+	where   node,
+) (
+	place *cell,
+	err   error,
+) {
+	place, exists := operation.data[section]
+	if exists { return }
+
+	if section.External() {
+		err = where.NewError (
+			section.Name() + " is external, and cannot be " +
+			"interpreted",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	var value any
+	if section.Argument() == nil {
+		value, err = operation.zero(section.What())
+	} else {
+		value, err = operation.evaluateAs (
+			section.Argument(),
+			section.What())
+	}
+	if err != nil { return }
+
+	place = &cell { copyValue(value) }
+	operation.data[section] = place
+	return
+}
+
+// assign stores a value in the place that a target argument refers to.
+func (operation *interpretingOperation) assign (
+	target analyzer.Argument,
+	value  any,
+) (
+	err error,
+) {
+	place, err := operation.locate(target)
+	if err != nil { return }
+	operation.store(target, place, value)
+	return
+}
+
+// store stores a copy of a value in a place that has already been located. If
+// the target is a bit field, the value is cut down to fit within it.
+func (operation *interpretingOperation) store (
+	target analyzer.Argument,
+	place  pointer,
+	value  any,
+) {
+	value = copyValue(value)
+
+	access, isMember := target.(analyzer.MemberAccess)
+	if isMember && access.Member().BitWidth() > 0 {
+		shape := operation.shapeOf(access.What())
+		value = shape.scalar.truncate (
+			value.(uint64),
+			access.Member().BitWidth())
+	}
+
+	place.target().value = value
+}
@@ -0,0 +1,67 @@
+package interpreter
+
+import "fmt"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// cast evaluates a cast phrase, converting its operand to a new type.
+func (operation *interpretingOperation) cast (
+	phrase analyzer.CastPhrase,
+) (
+	value any,
+	err   error,
+) {
+	operand := phrase.Operand()
+	what    := phrase.What()
+
+	// constants are converted directly to the new type
+	if isUntyped(operand) {
+		value, err = operation.evaluateAs(operand, what)
+		return
+	}
+
+	value, err = operation.evaluate(operand)
+	if err != nil { return }
+
+	switch phrase.Conversion() {
+	case analyzer.ConversionNumeric, analyzer.ConversionTruth:
+		from := operation.shapeOf(operand.What()).scalar
+		to   := operation.shapeOf(what).scalar
+
+		converted, valid := to.convert(value, from)
+		if !valid {
+			err = phrase.NewError (
+				fmt.Sprint (
+					"cannot convert ", value, " to ",
+					what.Describe()),
+				infoerr.ErrorKindError)
+			return
+		}
+		value = converted
+
+	case analyzer.ConversionObject:
+		value, err = operation.convertObject(value.(object), what)
+	}
+	return
+}
+
+// convertObject converts an object to a different object type. Members that
+// both types have are copied over, and the rest are set to their default
+// values.
+func (operation *interpretingOperation) convertObject (
+	source object,
+	what   analyzer.Type,
+) (
+	converted object,
+	err       error,
+) {
+	value, err := operation.zero(what)
+	if err != nil { return }
+	converted = value.(object)
+
+	for name, member := range converted {
+		original, exists := source[name]
+		if exists { member.value = copyValue(original.value) }
+	}
+	return
+}
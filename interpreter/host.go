@@ -0,0 +1,128 @@
+package interpreter
+
+import "os"
+import "fmt"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// hostFunction is a function that the interpreter provides itself, in place of
+// an external function that it cannot run.
+type hostFunction func (
+	operation *interpretingOperation,
+	inputs    []any,
+) (
+	outputs []any,
+	err     error,
+)
+
+// hostModule is a module that the interpreter provides. Its source code is
+// given to the parser from memory, and each of its external functions is
+// implemented by a host function.
+type hostModule struct {
+	source    string
+	functions map[string] hostFunction
+}
+
+// hostModules lists every module that the interpreter provides, by name.
+var hostModules = map[string] hostModule {
+	"io": {
+		source:
+			":arf\n" +
+			"---\n" +
+			"\n" +
+			"# print writes text to standard output.\n" +
+			"func ro print\n" +
+			"\t> text:String\n" +
+			"\t---\n" +
+			"\texternal\n" +
+			"\n" +
+			"# println writes text to standard output, followed by a " +
+			"newline.\n" +
+			"func ro println\n" +
+			"\t> text:String\n" +
+			"\t---\n" +
+			"\texternal\n",
+		functions: map[string] hostFunction {
+			"print": func (
+				operation *interpretingOperation,
+				inputs    []any,
+			) (
+				outputs []any,
+				err     error,
+			) {
+				text := goString(inputs[0].(pointer))
+				_, err = fmt.Fprint(operation.output, text)
+				return
+			},
+			"println": func (
+				operation *interpretingOperation,
+				inputs    []any,
+			) (
+				outputs []any,
+				err     error,
+			) {
+				text := goString(inputs[0].(pointer))
+				_, err = fmt.Fprintln(operation.output, text)
+				return
+			},
+		},
+	},
+}
+
+// registerHostModules makes the parser read the source code of each host
+// module from memory. The modules are placed in an empty temporary directory
+// rather than in the include path, so that they do not hide any modules that
+// are installed there. The path of each module is returned along with its name,
+// as well as a function that undoes all of this.
+func registerHostModules () (
+	hosts      map[string] string,
+	unregister func (),
+	err        error,
+) {
+	directory, err := os.MkdirTemp("", "arf-run-")
+	if err != nil { return }
+
+	hosts = make(map[string] string)
+	for name, module := range hostModules {
+		modulePath := filepath.Join(directory, name)
+		parser.SetOverlay (
+			filepath.Join(modulePath, "main.arf"),
+			module.source)
+		parser.SetHostModule(name, modulePath)
+		hosts[modulePath] = name
+	}
+
+	unregister = func () {
+		for modulePath, name := range hosts {
+			filePath := filepath.Join(modulePath, "main.arf")
+			parser.ClearHostModule(name)
+			parser.ClearOverlay(filePath)
+		}
+		os.Remove(directory)
+	}
+	return
+}
+
+// callHost runs the host function that implements an external function.
+func (operation *interpretingOperation) callHost (
+	function *analyzer.FuncSection,
+	inputs   []any,
+	caller   node,
+) (
+	outputs []any,
+	err     error,
+) {
+	name, hosted := operation.hosts[function.ModulePath()]
+	implementation, exists := hostModules[name].functions[function.Name()]
+	if hosted && exists {
+		outputs, err = implementation(operation, inputs)
+		return
+	}
+
+	err = caller.NewError (
+		function.Name() + " is external, and cannot be interpreted",
+		infoerr.ErrorKindError)
+	return
+}
@@ -0,0 +1,210 @@
+/*
+Package interpreter runs ARF modules directly, without translating them into C
+first. It walks the section table produced by the analyzer: data sections are
+evaluated the first time they are used, and the phrases of each function are
+interpreted as it is called.
+
+Functions that are marked as external can only be run if the interpreter
+provides them itself. A small io module is provided this way, so that programs
+are able to print text.
+*/
+package interpreter
+
+import "io"
+import "fmt"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// maxDepth is the amount of nested function calls that are allowed before the
+// program is considered to have overflowed its stack.
+const maxDepth = 10000
+
+// node is anything in the section table that an error can be reported at.
+type node interface {
+	NewError (message string, kind infoerr.ErrorKind) (err error)
+}
+
+// interpretingOperation holds the state of a running program.
+type interpretingOperation struct {
+	platform target.Target
+	output   io.Writer
+
+	// hosts holds the name of each host module, indexed by its path.
+	hosts map[string] string
+
+	// data holds the value of each data section that has been used so far.
+	data map[*analyzer.DataSection] *cell
+
+	// frames holds one frame for each function call that has not returned
+	// yet. The last one belongs to the function that is currently running.
+	frames []*frame
+}
+
+// frame holds the variables and deferred blocks of a single function call.
+type frame struct {
+	variables map[*analyzer.Variable] *cell
+	deferred  []analyzer.Block
+}
+
+// Run analyzes the module at modulePath, and runs its main function with the
+// specified arguments. Anything that the program prints is written to output.
+// The platform determines the size of machine dependent types such as Int and
+// UInt. If main has an output, it is returned as the exit status.
+func Run (
+	modulePath string,
+	arguments  []string,
+	platform   target.Target,
+	output     io.Writer,
+) (
+	status int,
+	err    error,
+) {
+	modulePath, err = filepath.Abs(modulePath)
+	if err != nil { return }
+
+	hosts, unregister, err := registerHostModules()
+	if err != nil { return }
+	defer unregister()
+
+	// required modules are normally skimmed, which throws away the bodies
+	// of their functions. they are parsed fully here so that they can be
+	// run. afterwards, they are forgotten, because they require host
+	// modules that will no longer exist.
+	fetched := make(map[string] bool)
+	defer func () {
+		for modulePath := range fetched { parser.Forget(modulePath) }
+	} ()
+	err = fetchAll(modulePath, fetched)
+	if err != nil { return }
+
+	table, err := analyzer.Analyze(modulePath, false, platform)
+	if err != nil { return }
+
+	operation := interpretingOperation {
+		platform: platform,
+		output:   output,
+		hosts:    hosts,
+		data:     make(map[*analyzer.DataSection] *cell),
+	}
+
+	main, err := operation.findMain(table, modulePath)
+	if err != nil { return }
+
+	inputs := []any { }
+	if len(main.Inputs()) > 0 {
+		inputs = append(inputs, stringArray(arguments))
+	}
+
+	outputs, err := operation.call(main, nil, inputs, main)
+	if err != nil { return }
+
+	if len(outputs) > 0 {
+		what  := main.Outputs()[0].What()
+		shape := operation.shapeOf(what)
+		if shape.scalar.signed {
+			signed := shape.scalar.signedValue(outputs[0].(uint64))
+			status = int(signed)
+		} else {
+			status = int(outputs[0].(uint64))
+		}
+	}
+	return
+}
+
+// fetchAll parses a module and every module it requires without skimming any
+// of them, so that the analyzer will find the bodies of all functions.
+func fetchAll (modulePath string, fetched map[string] bool) (err error) {
+	if fetched[modulePath] { return }
+	fetched[modulePath] = true
+
+	// the module may have been parsed before host modules were registered,
+	// in which case its requires would lead somewhere else
+	parser.Forget(modulePath)
+	tree, err := parser.Fetch(modulePath, false)
+	if err != nil { return }
+
+	requires := tree.Requires()
+	for !requires.End() {
+		err = fetchAll(requires.Value(), fetched)
+		if err != nil { return }
+		requires.Next()
+	}
+	return
+}
+
+// findMain finds the main function of a module, and makes sure that it can be
+// called by the interpreter. It may take in a list of arguments, and it may
+// output an integer exit status.
+func (operation *interpretingOperation) findMain (
+	table      analyzer.SectionTable,
+	modulePath string,
+) (
+	main *analyzer.FuncSection,
+	err  error,
+) {
+	for _, section := range table.Sorted() {
+		if section.ModulePath() != modulePath { continue }
+		if section.Name() != "main"           { continue }
+		main, _ = section.(*analyzer.FuncSection)
+	}
+
+	if main == nil {
+		err = fmt.Errorf("%s has no main function", modulePath)
+		return
+	}
+
+	inputs := main.Inputs()
+	if len(inputs) > 1 || len(inputs) == 1 && !isStringArray(inputs[0]) {
+		err = main.NewError (
+			"main must take in either nothing, or one {String ..}",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	outputs := main.Outputs()
+	integer := len(outputs) == 1 && operation.isInteger(outputs[0].What())
+	if len(outputs) > 1 || len(outputs) == 1 && !integer {
+		err = main.NewError (
+			"main must output either nothing, or one integer",
+			infoerr.ErrorKindError)
+		return
+	}
+	return
+}
+
+// isStringArray returns whether a variable is a variable length array of
+// strings.
+func isStringArray (variable *analyzer.Variable) (is bool) {
+	what := variable.What()
+	if what.Kind() != analyzer.TypeKindVariableArray { return }
+	if what.Length() != 1 { return }
+
+	points := what.Points()
+	is =
+		points.Kind() == analyzer.TypeKindBasic &&
+		points.Length() == 1 &&
+		points.Actual() == &analyzer.BuiltInString
+	return
+}
+
+// isInteger returns whether a type is a single integer.
+func (operation *interpretingOperation) isInteger (
+	what analyzer.Type,
+) (
+	is bool,
+) {
+	shape := operation.shapeOf(what)
+	is =
+		shape.kind == shapeScalar &&
+		shape.scalar.kind == scalarInteger
+	return
+}
+
+// frame returns the frame of the function that is currently running.
+func (operation *interpretingOperation) frame () (current *frame) {
+	current = operation.frames[len(operation.frames) - 1]
+	return
+}
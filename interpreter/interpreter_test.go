@@ -0,0 +1,110 @@
+package interpreter
+
+import "os"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/parser"
+
+func TestArguments (test *testing.T) {
+	checkRun ("../tests/interpreter/arguments", []string { "a", "b c" },
+`arguments:
+a
+b c
+`, 0, test)
+}
+
+func TestArithmetic (test *testing.T) {
+	checkRun ("../tests/interpreter/arithmetic", nil,
+`constant
+unsigned wrap
+signed wrap
+divide
+remainder
+shift
+complement
+assign
+recursion
+float
+truncate
+truth
+logic
+`, 3, test)
+}
+
+func TestStructures (test *testing.T) {
+	checkRun ("../tests/interpreter/structures", nil,
+`defaults
+method
+copy
+pointer
+array
+string
+bit field
+object cast
+enum
+enum value
+data
+`, 0, test)
+}
+
+func TestControl (test *testing.T) {
+	checkRun ("../tests/interpreter/control", nil,
+`second deferred
+first deferred
+four
+equal
+`, 4, test)
+}
+
+func TestDivisionByZero (test *testing.T) {
+	checkRunError (
+		"../tests/interpreter/divisionByZero",
+		"division by zero", 7, 10, test)
+}
+
+func TestOutOfBounds (test *testing.T) {
+	checkRunError (
+		"../tests/interpreter/outOfBounds",
+		"offset 3 is out of bounds for an array of length 3",
+		7, 10, test)
+}
+
+func TestArbitrary (test *testing.T) {
+	checkRunError (
+		"../tests/interpreter/arbitrary",
+		"'puts' runs arbitrary code, and cannot be interpreted",
+		5, 1, test)
+}
+
+func TestOverflow (test *testing.T) {
+	checkRunError (
+		"../tests/interpreter/overflow",
+		"stack overflow: too many nested function calls",
+		7, 10, test)
+}
+
+func TestHostModules (test *testing.T) {
+	installed := filepath.Join(parser.IncludePath, "io")
+	if _, err := os.Stat(installed); err == nil {
+		test.Skip("an io module is installed")
+	}
+
+	checkRun (
+		"../tests/interpreter/arguments", nil,
+		"arguments:\n", 0, test)
+
+	// the io module provided by the interpreter should be gone once the
+	// program is done running, rather than standing in for the real one
+	_, err := parser.Fetch(installed, true)
+	if err == nil {
+		test.Log("host module was left in the include path")
+		test.Fail()
+	}
+}
+
+func TestGreeter (test *testing.T) {
+	checkRun ("../tests/interpreter/greeter", nil,
+`Hi.
+Hello, world!
+`, 0, test)
+}
@@ -0,0 +1,261 @@
+package interpreter
+
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// assignedOperators maps each operator that stores its result in its first
+// operand to the operation it performs.
+var assignedOperators = map[lexer.TokenKind] lexer.TokenKind {
+	lexer.TokenKindIncrement:           lexer.TokenKindPlus,
+	lexer.TokenKindDecrement:           lexer.TokenKindMinus,
+	lexer.TokenKindPercentAssignment:   lexer.TokenKindPercent,
+	lexer.TokenKindTildeAssignment:     lexer.TokenKindTilde,
+	lexer.TokenKindBinaryOrAssignment:  lexer.TokenKindBinaryOr,
+	lexer.TokenKindBinaryAndAssignment: lexer.TokenKindBinaryAnd,
+	lexer.TokenKindBinaryXorAssignment: lexer.TokenKindBinaryXor,
+	lexer.TokenKindLShiftAssignment:    lexer.TokenKindLShift,
+	lexer.TokenKindRShiftAssignment:    lexer.TokenKindRShift,
+}
+
+// isShift returns whether an operator shifts the bits of an integer.
+func isShift (operator lexer.TokenKind) (shift bool) {
+	shift =
+		operator == lexer.TokenKindLShift ||
+		operator == lexer.TokenKindRShift
+	return
+}
+
+// operatorPhrase evaluates an operator phrase. The operands of logical
+// operators are only evaluated until the result is known.
+func (operation *interpretingOperation) operatorPhrase (
+	phrase analyzer.OperatorPhrase,
+) (
+	value any,
+	err   error,
+) {
+	if phrase.Untyped() {
+		var constant analyzer.Argument
+		constant, err = phrase.Resolve()
+		if err != nil { return }
+		value, err = operation.literal(constant, constant.What())
+		return
+	}
+
+	operator := phrase.Operator()
+	what     := phrase.OperandType()
+
+	switch operator {
+	case lexer.TokenKindLogicalAnd, lexer.TokenKindLogicalOr:
+		stopAt := operator == lexer.TokenKindLogicalOr
+		for _, argument := range phrase.Arguments() {
+			value, err = operation.evaluate(argument)
+			if err != nil { return }
+			if value.(bool) == stopAt { return }
+		}
+		return
+	}
+
+	operands := []any { }
+	for index, argument := range phrase.Arguments() {
+		var operand any
+		if isShift(operator) && index == 1 {
+			operand, err = operation.shiftAmount(argument)
+		} else {
+			operand, err = operation.evaluateAs(argument, what)
+		}
+		if err != nil { return }
+		operands = append(operands, operand)
+	}
+
+	value, err = operation.operate(operator, what, operands, phrase)
+	return
+}
+
+// shiftAmount evaluates the amount that an integer is being shifted by.
+func (operation *interpretingOperation) shiftAmount (
+	argument analyzer.Argument,
+) (
+	amount uint64,
+	err    error,
+) {
+	value, err := operation.evaluate(argument)
+	if err != nil { return }
+
+	amount = value.(uint64)
+	description := operation.shapeOf(argument.What()).scalar
+	if description.signed && description.signedValue(amount) < 0 {
+		err = argument.NewError (
+			"cannot shift by a negative amount",
+			infoerr.ErrorKindError)
+	}
+	return
+}
+
+// operate performs an operation on a list of operands that are all of the
+// specified type. The amount of a shift operation is a plain uint64.
+func (operation *interpretingOperation) operate (
+	operator lexer.TokenKind,
+	what     analyzer.Type,
+	operands []any,
+	where    node,
+) (
+	result any,
+	err    error,
+) {
+	description := operation.shapeOf(what).scalar
+	switch operator {
+	case lexer.TokenKindExclamation:
+		result = !operands[0].(bool)
+		return
+	case lexer.TokenKindEqualTo:
+		result = equal(operands[0], operands[1])
+		return
+	case lexer.TokenKindNotEqualTo:
+		result = !equal(operands[0], operands[1])
+		return
+	}
+
+	switch operator {
+	case lexer.TokenKindLessThan:
+		result = description.compare(operands[0], operands[1]) < 0
+	case lexer.TokenKindLessThanEqualTo:
+		result = description.compare(operands[0], operands[1]) <= 0
+	case lexer.TokenKindGreaterThan:
+		result = description.compare(operands[0], operands[1]) > 0
+	case lexer.TokenKindGreaterThanEqualTo:
+		result = description.compare(operands[0], operands[1]) >= 0
+	default:
+		if description.kind == scalarFloat {
+			result = description.operateFloat(operator, operands)
+		} else {
+			result, err = description.operateInteger (
+				operator,
+				operands,
+				where)
+		}
+	}
+	return
+}
+
+// operateFloat performs an arithmetic operation on floats. Division by zero
+// results in infinity, the same way it does in C.
+func (description scalar) operateFloat (
+	operator lexer.TokenKind,
+	operands []any,
+) (
+	result float64,
+) {
+	result = operands[0].(float64)
+	if operator == lexer.TokenKindMinus && len(operands) == 1 {
+		result = -result
+		return
+	}
+
+	for _, operand := range operands[1:] {
+		value := operand.(float64)
+		switch operator {
+		case lexer.TokenKindPlus:
+			result += value
+		case lexer.TokenKindMinus:
+			result -= value
+		case lexer.TokenKindAsterisk:
+			result *= value
+		case lexer.TokenKindSlash:
+			result /= value
+		}
+		result = description.round(result)
+	}
+	return
+}
+
+// operateInteger performs an arithmetic, bitwise, or shift operation on
+// integers. Results that do not fit wrap around.
+func (description scalar) operateInteger (
+	operator lexer.TokenKind,
+	operands []any,
+	where    node,
+) (
+	result any,
+	err    error,
+) {
+	integer := operands[0].(uint64)
+	if len(operands) == 1 {
+		switch operator {
+		case lexer.TokenKindMinus:
+			integer = -integer
+		case lexer.TokenKindTilde:
+			integer = ^integer
+		}
+		result = description.wrap(integer)
+		return
+	}
+
+	for _, operand := range operands[1:] {
+		value := operand.(uint64)
+		switch operator {
+		case lexer.TokenKindPlus:
+			integer += value
+		case lexer.TokenKindMinus:
+			integer -= value
+		case lexer.TokenKindAsterisk:
+			integer *= value
+		case lexer.TokenKindSlash, lexer.TokenKindPercent:
+			if value == 0 {
+				err = where.NewError (
+					"division by zero",
+					infoerr.ErrorKindError)
+				return
+			}
+			integer = description.divide(operator, integer, value)
+		case lexer.TokenKindBinaryOr:
+			integer |= value
+		case lexer.TokenKindBinaryAnd:
+			integer &= value
+		case lexer.TokenKindBinaryXor:
+			integer ^= value
+		case lexer.TokenKindLShift:
+			integer <<= value
+		case lexer.TokenKindRShift:
+			if description.signed {
+				signed := description.signedValue(integer)
+				integer = uint64(signed >> value)
+			} else {
+				integer >>= value
+			}
+		}
+		integer = description.wrap(integer)
+	}
+
+	result = integer
+	return
+}
+
+// divide divides two integers, or finds the remainder of dividing them. The
+// divisor must not be zero.
+func (description scalar) divide (
+	operator lexer.TokenKind,
+	dividend uint64,
+	divisor  uint64,
+) (
+	result uint64,
+) {
+	remainder := operator == lexer.TokenKindPercent
+	if !description.signed {
+		if remainder {
+			result = dividend % divisor
+		} else {
+			result = dividend / divisor
+		}
+		return
+	}
+
+	signedDividend := description.signedValue(dividend)
+	signedDivisor  := description.signedValue(divisor)
+	if remainder {
+		result = uint64(signedDividend % signedDivisor)
+	} else {
+		result = uint64(signedDividend / signedDivisor)
+	}
+	return
+}
@@ -0,0 +1,365 @@
+package interpreter
+
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// block runs each phrase in a block, in order.
+func (operation *interpretingOperation) block (
+	block analyzer.Block,
+) (
+	err error,
+) {
+	for _, phrase := range block.Phrases() {
+		err = operation.phrase(phrase)
+		if err != nil { return }
+	}
+	return
+}
+
+// phrase runs a single phrase.
+func (operation *interpretingOperation) phrase (
+	phrase analyzer.Phrase,
+) (
+	err error,
+) {
+	switch phrase.(type) {
+	case analyzer.ArbitraryPhrase:
+		err = phrase.NewError (
+			"'" + phrase.(analyzer.ArbitraryPhrase).Command() +
+			"' runs arbitrary code, and cannot be interpreted",
+			infoerr.ErrorKindError)
+
+	case analyzer.AssignPhrase:
+		err = operation.assignPhrase(phrase.(analyzer.AssignPhrase))
+
+	case analyzer.IfPhrase:
+		err = operation.ifPhrase(phrase.(analyzer.IfPhrase))
+
+	case analyzer.SwitchPhrase:
+		err = operation.switchPhrase(phrase.(analyzer.SwitchPhrase))
+
+	case analyzer.WhilePhrase:
+		err = operation.whilePhrase(phrase.(analyzer.WhilePhrase))
+
+	case analyzer.ForPhrase:
+		err = operation.forPhrase(phrase.(analyzer.ForPhrase))
+
+	case analyzer.DeferPhrase:
+		current := operation.frame()
+		current.deferred = append (
+			current.deferred,
+			phrase.(analyzer.DeferPhrase).Block())
+
+	case analyzer.VariableReference:
+		reference := phrase.(analyzer.VariableReference)
+		_, err = operation.variable (
+			reference.Variable(),
+			reference.Declaration())
+
+	case analyzer.Argument:
+		_, err = operation.evaluate(phrase.(analyzer.Argument))
+	}
+	return
+}
+
+// assignPhrase runs a phrase that stores a value, or that performs an
+// operation on its target and stores the result there.
+func (operation *interpretingOperation) assignPhrase (
+	phrase analyzer.AssignPhrase,
+) (
+	err error,
+) {
+	target    := phrase.Target()
+	arguments := phrase.Arguments()
+	what      := target.What()
+
+	if phrase.Operator() == lexer.TokenKindAssignment {
+		var value any
+		value, err = operation.evaluateAs(arguments[0], what)
+		if err != nil { return }
+		err = operation.assign(target, value)
+		return
+	}
+
+	operator := assignedOperators[phrase.Operator()]
+	operands := []any { }
+	for index, argument := range arguments {
+		var operand any
+		if isShift(operator) && index == 0 {
+			operand, err = operation.shiftAmount(argument)
+		} else {
+			operand, err = operation.evaluateAs(argument, what)
+		}
+		if err != nil { return }
+		operands = append(operands, operand)
+	}
+
+	place, err := operation.locate(target)
+	if err != nil { return }
+
+	switch phrase.Operator() {
+	case lexer.TokenKindIncrement, lexer.TokenKindDecrement:
+		one := operation.shapeOf(what).scalar.one()
+		operands = append(operands, one)
+	}
+	operands = append([]any { place.target().value }, operands...)
+
+	result, err := operation.operate(operator, what, operands, phrase)
+	if err != nil { return }
+	operation.store(target, place, result)
+	return
+}
+
+// ifPhrase runs the block of the first branch whose condition is true. If none
+// of them are, the fallback block is run instead.
+func (operation *interpretingOperation) ifPhrase (
+	phrase analyzer.IfPhrase,
+) (
+	err error,
+) {
+	for _, branch := range phrase.Branches() {
+		var condition any
+		condition, err = operation.evaluate(branch.Condition())
+		if err != nil { return }
+
+		if condition.(bool) {
+			err = operation.block(branch.Block())
+			return
+		}
+	}
+
+	if phrase.Fallback() != nil {
+		err = operation.block(*phrase.Fallback())
+	}
+	return
+}
+
+// switchPhrase runs the block of the first case that has a value equal to the
+// subject. If none of them do, the default case is run, if there is one.
+func (operation *interpretingOperation) switchPhrase (
+	phrase analyzer.SwitchPhrase,
+) (
+	err error,
+) {
+	subject, err := operation.evaluate(phrase.Subject())
+	if err != nil { return }
+	what := phrase.Subject().What()
+
+	cases    := phrase.Cases()
+	fallback := -1
+	for index, casePhrase := range cases {
+		if casePhrase.IsDefault() {
+			fallback = index
+			continue
+		}
+
+		for _, value := range casePhrase.Values() {
+			var caseValue any
+			caseValue, err = operation.evaluateAs(value, what)
+			if err != nil { return }
+
+			if equal(subject, caseValue) {
+				err = operation.block(casePhrase.Block())
+				return
+			}
+		}
+	}
+
+	if fallback >= 0 {
+		err = operation.block(cases[fallback].Block())
+	}
+	return
+}
+
+// whilePhrase runs a block over and over for as long as its condition is true.
+func (operation *interpretingOperation) whilePhrase (
+	phrase analyzer.WhilePhrase,
+) (
+	err error,
+) {
+	for {
+		var condition any
+		condition, err = operation.evaluate(phrase.Condition())
+		if err != nil { return }
+		if !condition.(bool) { return }
+
+		err = operation.block(phrase.Block())
+		if err != nil { return }
+	}
+}
+
+// forPhrase runs a block once for each element of an array. Each element is
+// copied into the element variable before the block is run.
+func (operation *interpretingOperation) forPhrase (
+	phrase analyzer.ForPhrase,
+) (
+	err error,
+) {
+	collection, err := operation.evaluate(phrase.Collection())
+	if err != nil { return }
+
+	var elements []*cell
+	switch collection.(type) {
+	case array:
+		elements = collection.(array)
+	case pointer:
+		elements = collection.(pointer).elements()
+	}
+
+	current := operation.frame()
+	for index, element := range elements {
+		if phrase.Index() != nil {
+			indexShape := operation.shapeOf(phrase.Index().What())
+			current.variables[phrase.Index()] = &cell {
+				indexShape.scalar.wrap(uint64(index)),
+			}
+		}
+		current.variables[phrase.Element()] = &cell {
+			copyValue(element.value),
+		}
+
+		err = operation.block(phrase.Block())
+		if err != nil { return }
+	}
+	return
+}
+
+// callPhrase calls the function of a phrase, and stores its outputs in the
+// phrase's returnees.
+func (operation *interpretingOperation) callPhrase (
+	phrase analyzer.CallPhrase,
+) (
+	outputs []any,
+	err     error,
+) {
+	function := phrase.Function()
+
+	var receiver any
+	if function.Receiver() != nil {
+		receiver, err = operation.receiver (
+			phrase.Receiver(),
+			function.Receiver().What())
+		if err != nil { return }
+	}
+
+	inputs := []any { }
+	for index, argument := range phrase.Arguments() {
+		var input any
+		input, err = operation.evaluateAs (
+			argument,
+			function.Inputs()[index].What())
+		if err != nil { return }
+		inputs = append(inputs, input)
+	}
+
+	outputs, err = operation.call(function, receiver, inputs, phrase)
+	if err != nil { return }
+
+	for index, returnee := range phrase.ReturnsTo() {
+		err = operation.assign(returnee, outputs[index])
+		if err != nil { return }
+	}
+	return
+}
+
+// receiver evaluates the value that a method is called on. If the method takes
+// in a pointer and the value is not one, the location of the value is passed
+// instead. If the method does not take in a pointer and the value is one, the
+// value that it points to is passed.
+func (operation *interpretingOperation) receiver (
+	argument analyzer.Argument,
+	what     analyzer.Type,
+) (
+	value any,
+	err   error,
+) {
+	given        := argument.What()
+	wantsPointer := what.Kind()  == analyzer.TypeKindPointer
+	isPointer    := given.Kind() == analyzer.TypeKindPointer
+
+	switch {
+	case wantsPointer && !isPointer:
+		value, err = operation.locate(argument)
+
+	case !wantsPointer && isPointer:
+		value, err = operation.evaluate(argument)
+		if err != nil { return }
+
+		var place pointer
+		place, err = operation.offset(value.(pointer), 0, argument)
+		if err != nil { return }
+		value = place.target().value
+
+	default:
+		value, err = operation.evaluate(argument)
+	}
+	return
+}
+
+// call runs a function with the specified receiver and inputs, and returns the
+// values of its outputs. Deferred blocks are run after the root block of the
+// function, in the opposite order that they were deferred in.
+func (operation *interpretingOperation) call (
+	function *analyzer.FuncSection,
+	receiver any,
+	inputs   []any,
+	caller   node,
+) (
+	outputs []any,
+	err     error,
+) {
+	if len(operation.frames) >= maxDepth {
+		err = caller.NewError (
+			"stack overflow: too many nested function calls",
+			infoerr.ErrorKindError)
+		return
+	}
+
+	if function.External() {
+		outputs, err = operation.callHost(function, inputs, caller)
+		return
+	}
+
+	current := &frame { variables: make(map[*analyzer.Variable] *cell) }
+	operation.frames = append(operation.frames, current)
+	defer func () {
+		operation.frames = operation.frames[:len(operation.frames) - 1]
+	} ()
+
+	if function.Receiver() != nil {
+		current.variables[function.Receiver()] = &cell {
+			copyValue(receiver),
+		}
+	}
+	for index, input := range function.Inputs() {
+		current.variables[input] = &cell { copyValue(inputs[index]) }
+	}
+	for _, output := range function.Outputs() {
+		var value any
+		if output.Argument() == nil {
+			value, err = operation.zero(output.What())
+		} else {
+			value, err = operation.evaluateAs (
+				output.Argument(),
+				output.What())
+		}
+		if err != nil { return }
+		current.variables[&output.Variable] = &cell { value }
+	}
+
+	err = operation.block(function.Root())
+	if err != nil { return }
+
+	for index := len(current.deferred) - 1; index >= 0; index -- {
+		err = operation.block(current.deferred[index])
+		if err != nil { return }
+	}
+
+	for _, output := range function.Outputs() {
+		outputs = append (
+			outputs,
+			current.variables[&output.Variable].value)
+	}
+	return
+}
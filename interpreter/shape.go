@@ -0,0 +1,388 @@
+package interpreter
+
+import "math"
+import "math/big"
+import "git.tebibyte.media/arf/arf/analyzer"
+
+// scalarKind determines how a value of a primitive type is stored.
+type scalarKind int
+
+const (
+	scalarInteger scalarKind = iota
+	scalarFloat
+	scalarBool
+)
+
+// scalar describes how the values of a primitive type are stored, and how
+// operations are performed on them.
+type scalar struct {
+	kind   scalarKind
+	signed bool
+	width  uint64
+}
+
+// shapeKind determines how a value of some type is stored.
+type shapeKind int
+
+const (
+	shapeScalar shapeKind = iota
+	shapePointer
+	shapeArray
+	shapeObject
+	shapeFace
+)
+
+// shape describes how the values of a type are stored.
+type shape struct {
+	kind shapeKind
+
+	// scalar describes a primitive value.
+	scalar scalar
+
+	// element is the type of the elements of an array, or of what a
+	// pointer points to.
+	element analyzer.Type
+
+	// length is the length of a fixed length array.
+	length uint64
+
+	// members lists the members of an object, including inherited ones.
+	members []analyzer.ObjectMember
+}
+
+// shapeOf ascends up the inheritence chain of a type, and finds out how its
+// values are stored.
+func (operation *interpretingOperation) shapeOf (
+	what analyzer.Type,
+) (
+	result shape,
+) {
+	var outermost *analyzer.TypeSection
+	for {
+		if what.Length() > 1 {
+			result.kind       = shapeArray
+			result.element, _ = what.Element()
+			result.length     = what.Length()
+			return
+		}
+
+		if what.Kind() != analyzer.TypeKindBasic {
+			result.kind    = shapePointer
+			result.element = *what.Points()
+			return
+		}
+
+		switch what.Actual().(type) {
+		case *analyzer.TypeSection:
+			section := what.Actual().(*analyzer.TypeSection)
+			if outermost == nil { outermost = section }
+
+			inherits := section.What()
+			isRoot :=
+				inherits.Kind() == analyzer.TypeKindBasic &&
+				inherits.Actual() == nil
+			if !isRoot {
+				what = inherits
+				continue
+			}
+
+			if section == &analyzer.PrimitiveObj {
+				result.kind    = shapeObject
+				result.members = outermost.Members()
+			} else {
+				result.kind   = shapeScalar
+				result.scalar = operation.scalarOf(section)
+			}
+			return
+
+		case *analyzer.EnumSection:
+			what = what.Actual().(*analyzer.EnumSection).What()
+
+		default:
+			result.kind = shapeFace
+			return
+		}
+	}
+}
+
+// scalarOf describes how the values of a primitive are stored.
+func (operation *interpretingOperation) scalarOf (
+	primitive *analyzer.TypeSection,
+) (
+	result scalar,
+) {
+	switch primitive {
+	case &analyzer.PrimitiveF32:
+		result = scalar { kind: scalarFloat, width: 32 }
+	case &analyzer.PrimitiveF64:
+		result = scalar { kind: scalarFloat, width: 64 }
+	case &analyzer.PrimitiveBool:
+		result = scalar { kind: scalarBool }
+	case &analyzer.PrimitiveI8:
+		result = scalar { signed: true, width: 8 }
+	case &analyzer.PrimitiveI16:
+		result = scalar { signed: true, width: 16 }
+	case &analyzer.PrimitiveI32:
+		result = scalar { signed: true, width: 32 }
+	case &analyzer.PrimitiveI64:
+		result = scalar { signed: true, width: 64 }
+	case &analyzer.PrimitiveU8:
+		result = scalar { width: 8 }
+	case &analyzer.PrimitiveU16:
+		result = scalar { width: 16 }
+	case &analyzer.PrimitiveU32:
+		result = scalar { width: 32 }
+	case &analyzer.PrimitiveU64:
+		result = scalar { width: 64 }
+	case &analyzer.PrimitiveInt:
+		result = scalar { signed: true, width: operation.wordSize() }
+	case &analyzer.PrimitiveUInt:
+		result = scalar { width: operation.wordSize() }
+	}
+	return
+}
+
+// wordSize returns the size of Int and UInt in bits.
+func (operation *interpretingOperation) wordSize () (size uint64) {
+	size = uint64(operation.platform.WordSize) * 8
+	return
+}
+
+// zero returns the value that something of the specified type starts out
+// with. This is the default value of the nearest type in its inheritence chain
+// that has one, and zero otherwise.
+func (operation *interpretingOperation) zero (
+	what analyzer.Type,
+) (
+	value any,
+	err   error,
+) {
+	argument := defaultOf(what)
+	if argument != nil {
+		value, err = operation.evaluateAs(argument, what)
+		return
+	}
+
+	shape := operation.shapeOf(what)
+	switch shape.kind {
+	case shapeScalar:
+		value = shape.scalar.zero()
+
+	case shapePointer:
+		value = pointer { }
+
+	case shapeArray:
+		elements := make(array, shape.length)
+		for index := range elements {
+			var element any
+			element, err = operation.zero(shape.element)
+			if err != nil { return }
+			elements[index] = &cell { element }
+		}
+		value = elements
+
+	case shapeObject:
+		members := make(object, len(shape.members))
+		for _, member := range shape.members {
+			var memberValue any
+			if member.Argument() == nil {
+				memberValue, err = operation.zero(member.What())
+			} else {
+				memberValue, err = operation.evaluateAs (
+					member.Argument(),
+					member.What())
+			}
+			if err != nil { return }
+			members[member.Name()] = &cell { memberValue }
+		}
+		value = members
+	}
+	return
+}
+
+// defaultOf returns the default value of the nearest type in the inheritence
+// chain of what that has one. If none of them do, it returns nil.
+func defaultOf (what analyzer.Type) (argument analyzer.Argument) {
+	for what.Length() == 1 && what.Kind() == analyzer.TypeKindBasic {
+		switch what.Actual().(type) {
+		case *analyzer.TypeSection:
+			section := what.Actual().(*analyzer.TypeSection)
+			argument = section.Argument()
+			what     = section.What()
+		case *analyzer.EnumSection:
+			section := what.Actual().(*analyzer.EnumSection)
+			argument = section.Argument()
+			what     = section.What()
+		default:
+			return
+		}
+		if argument != nil { return }
+	}
+	return
+}
+
+// zero returns the zero value of a scalar.
+func (description scalar) zero () (value any) {
+	switch description.kind {
+	case scalarInteger:
+		value = uint64(0)
+	case scalarFloat:
+		value = float64(0)
+	case scalarBool:
+		value = false
+	}
+	return
+}
+
+// one returns the value one as a scalar. It is used to increment and decrement
+// numbers.
+func (description scalar) one () (value any) {
+	switch description.kind {
+	case scalarInteger:
+		value = uint64(1)
+	case scalarFloat:
+		value = float64(1)
+	case scalarBool:
+		value = true
+	}
+	return
+}
+
+// wrap wraps an integer around so that it fits within the width of the scalar.
+func (description scalar) wrap (bits uint64) (wrapped uint64) {
+	wrapped = bits
+	if description.width < 64 {
+		wrapped &= 1 << description.width - 1
+	}
+	return
+}
+
+// signedValue sign extends an integer that is stored in the scalar.
+func (description scalar) signedValue (bits uint64) (value int64) {
+	shift := 64 - description.width
+	value = int64(bits << shift) >> shift
+	return
+}
+
+// truncate cuts an integer down to a bit field that is width bits wide, and
+// then extends it back to the width of the scalar.
+func (description scalar) truncate (bits uint64, width uint64) (field uint64) {
+	field = scalar { signed: description.signed, width: width }.wrap(bits)
+	if description.signed {
+		field = uint64(scalar { width: width }.signedValue(field))
+	}
+	field = description.wrap(field)
+	return
+}
+
+// round rounds a float to the precision of the scalar.
+func (description scalar) round (float float64) (rounded float64) {
+	rounded = float
+	if description.width == 32 {
+		rounded = float64(float32(float))
+	}
+	return
+}
+
+// convert converts a value that is stored as from into a value stored as the
+// scalar, the way a numeric cast does. Integers that do not fit wrap around,
+// and floats are truncated towards zero. If a float cannot be represented as
+// an integer at all, valid is false.
+func (description scalar) convert (
+	value any,
+	from  scalar,
+) (
+	result any,
+	valid  bool,
+) {
+	valid = true
+	switch description.kind {
+	case scalarBool:
+		switch from.kind {
+		case scalarBool:
+			result = value
+		case scalarFloat:
+			result = value.(float64) != 0
+		case scalarInteger:
+			result = value.(uint64) != 0
+		}
+
+	case scalarFloat:
+		var float float64
+		switch from.kind {
+		case scalarBool:
+			if value.(bool) { float = 1 }
+		case scalarFloat:
+			float = value.(float64)
+		case scalarInteger:
+			if from.signed {
+				signed := from.signedValue(value.(uint64))
+				float = float64(signed)
+			} else {
+				float = float64(value.(uint64))
+			}
+		}
+		result = description.round(float)
+
+	case scalarInteger:
+		var integer uint64
+		switch from.kind {
+		case scalarBool:
+			if value.(bool) { integer = 1 }
+		case scalarFloat:
+			float := math.Trunc(value.(float64))
+			if math.IsNaN(float) || math.IsInf(float, 0) {
+				valid = false
+				return
+			}
+			whole, _ := big.NewFloat(float).Int(nil)
+			mask := new(big.Int).SetUint64(math.MaxUint64)
+			integer = whole.And(whole, mask).Uint64()
+		case scalarInteger:
+			integer = value.(uint64)
+			if from.signed {
+				integer = uint64(from.signedValue(integer))
+			}
+		}
+		result = description.wrap(integer)
+	}
+	return
+}
+
+// compare compares two values that are stored in the scalar. The comparison is
+// negative if left is smaller, positive if left is larger, and zero if they
+// are the same.
+func (description scalar) compare (left, right any) (comparison int) {
+	switch {
+	case description.kind == scalarFloat:
+		leftFloat  := left.(float64)
+		rightFloat := right.(float64)
+		switch {
+		case leftFloat < rightFloat:
+			comparison = -1
+		case leftFloat > rightFloat:
+			comparison = 1
+		}
+
+	case description.signed:
+		leftInteger  := description.signedValue(left.(uint64))
+		rightInteger := description.signedValue(right.(uint64))
+		switch {
+		case leftInteger < rightInteger:
+			comparison = -1
+		case leftInteger > rightInteger:
+			comparison = 1
+		}
+
+	default:
+		leftInteger  := left.(uint64)
+		rightInteger := right.(uint64)
+		switch {
+		case leftInteger < rightInteger:
+			comparison = -1
+		case leftInteger > rightInteger:
+			comparison = 1
+		}
+	}
+	return
+}
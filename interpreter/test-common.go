@@ -0,0 +1,95 @@
+package interpreter
+
+import "os"
+import "bytes"
+import "testing"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+func checkRun (
+	modulePath    string,
+	arguments     []string,
+	correctOutput string,
+	correctStatus int,
+	test *testing.T,
+) {
+	test.Log("running", modulePath)
+	cwd, _ := os.Getwd()
+	modulePath = filepath.Join(cwd, modulePath)
+	output := bytes.Buffer { }
+	status, err := Run(modulePath, arguments, target.Default, &output)
+
+	if err != nil {
+		test.Log("program failed to run:")
+		test.Log(err)
+		test.Fail()
+		return
+	}
+
+	if output.String() != correctOutput {
+		test.Log("mismatched output")
+		test.Log("- want:\n" + correctOutput)
+		test.Log("- have:\n" + output.String())
+		test.Fail()
+	}
+
+	if status != correctStatus {
+		test.Log("mismatched status")
+		test.Log("- want:", correctStatus)
+		test.Log("- have:", status)
+		test.Fail()
+	}
+}
+
+func checkRunError (
+	modulePath     string,
+	correctMessage string,
+	correctRow     int,
+	correctColumn  int,
+	test *testing.T,
+) {
+	test.Log("testing error in", modulePath)
+	cwd, _ := os.Getwd()
+	modulePath = filepath.Join(cwd, modulePath)
+	output := bytes.Buffer { }
+	_, err := Run(modulePath, nil, target.Default, &output)
+	check, isCorrectType := err.(infoerr.Error)
+
+	if err == nil {
+		test.Log("no error was recieved, test failed.")
+		test.Fail()
+		return
+	}
+
+	test.Log("error that was recieved:")
+	test.Log(err)
+
+	if !isCorrectType {
+		test.Log (
+			"error is not infoerr.Error, something has gone wrong.")
+		test.Fail()
+		return
+	}
+
+	if check.Message() != correctMessage {
+		test.Log("mismatched error message")
+		test.Log("- want:", correctMessage)
+		test.Log("- have:", check.Message())
+		test.Fail()
+	}
+
+	if check.Row() != correctRow {
+		test.Log("mismatched error row")
+		test.Log("- want:", correctRow)
+		test.Log("- have:", check.Row())
+		test.Fail()
+	}
+
+	if check.Column() != correctColumn {
+		test.Log("mismatched error column")
+		test.Log("- want:", correctColumn)
+		test.Log("- have:", check.Column())
+		test.Fail()
+	}
+}
@@ -0,0 +1,141 @@
+package interpreter
+
+// Values are stored as one of the following:
+//
+// 	integers            uint64, wrapped around to the width of their type
+// 	floating point      float64, rounded to the precision of their type
+// 	truth values        bool
+// 	pointers            pointer
+// 	variable arrays     pointer
+// 	fixed length arrays array
+// 	objects             object
+// 	interfaces          nil
+//
+// Signed integers are stored as their two's complement bits, so they have to
+// be sign extended before they can be compared or divided.
+
+// cell is a place where a single value is stored, such as a variable, a member
+// of an object, or an element of an array. Pointers refer to cells.
+type cell struct {
+	value any
+}
+
+// pointer is the value of a pointer or a variable length array. It refers to a
+// run of cells, starting at index. A nil pointer has no cells.
+type pointer struct {
+	cells []*cell
+	index int
+}
+
+// array is the value of a fixed length array.
+type array []*cell
+
+// object is the value of an object. It holds each of its members by name.
+type object map[string] *cell
+
+// target returns the cell that a pointer points to. If the pointer is nil or
+// points past the end of its cells, it returns nil.
+func (value pointer) target () (target *cell) {
+	if value.index < 0 || value.index >= len(value.cells) { return }
+	target = value.cells[value.index]
+	return
+}
+
+// elements returns the cells that a variable length array is made of.
+func (value pointer) elements () (elements []*cell) {
+	if value.index >= len(value.cells) { return }
+	elements = value.cells[value.index:]
+	return
+}
+
+// copyValue returns a copy of a value that can be stored somewhere else without
+// being affected by changes to the original. Fixed length arrays and objects
+// are copied along with everything in them, but pointers and variable arrays
+// still refer to the same cells.
+func copyValue (value any) (copied any) {
+	switch value.(type) {
+	case array:
+		original := value.(array)
+		elements := make(array, len(original))
+		for index, element := range original {
+			elements[index] = &cell { copyValue(element.value) }
+		}
+		copied = elements
+
+	case object:
+		original := value.(object)
+		members  := make(object, len(original))
+		for name, member := range original {
+			members[name] = &cell { copyValue(member.value) }
+		}
+		copied = members
+
+	default:
+		copied = value
+	}
+	return
+}
+
+// equal returns whether two values of the same type are equal. Pointers are
+// equal if they point to the same cell.
+func equal (left, right any) (same bool) {
+	switch left.(type) {
+	case pointer:
+		same = left.(pointer).target() == right.(pointer).target()
+
+	case array:
+		leftArray  := left.(array)
+		rightArray := right.(array)
+		if len(leftArray) != len(rightArray) { return }
+		for index := range leftArray {
+			leftValue  := leftArray[index].value
+			rightValue := rightArray[index].value
+			if !equal(leftValue, rightValue) { return }
+		}
+		same = true
+
+	case object:
+		leftObject  := left.(object)
+		rightObject := right.(object)
+		for name, member := range leftObject {
+			other, exists := rightObject[name]
+			if !exists || !equal(member.value, other.value) {
+				return
+			}
+		}
+		same = true
+
+	default:
+		same = left == right
+	}
+	return
+}
+
+// stringValue creates a String from Go text.
+func stringValue (text string) (value pointer) {
+	characters := []rune(text)
+	value.cells = make([]*cell, len(characters))
+	for index, character := range characters {
+		value.cells[index] = &cell { uint64(character) }
+	}
+	return
+}
+
+// stringArray creates a variable length array of Strings from Go text.
+func stringArray (texts []string) (value pointer) {
+	value.cells = make([]*cell, len(texts))
+	for index, text := range texts {
+		value.cells[index] = &cell { stringValue(text) }
+	}
+	return
+}
+
+// goString converts a String into Go text.
+func goString (value pointer) (text string) {
+	characters := []rune { }
+	for _, element := range value.elements() {
+		characters = append(characters, rune(element.value.(uint64)))
+	}
+	text = string(characters)
+	return
+}
@@ -15,6 +15,11 @@ type lexingOperation struct {
 	file   *file.File
 	char   rune
 	tokens []Token
+
+	// if lossless is true, every rune that is read is kept in source so
+	// that the text of each token can be recovered once lexing is done.
+	lossless bool
+	source   []rune
 }
 
 // Tokenize converts a file into a slice of tokens (lexemes).
@@ -31,6 +36,30 @@ func Tokenize (file *file.File) (tokens []Token, err error) {
 	return
 }
 
+// TokenizeLossless is like Tokenize, but it keeps enough information to
+// reproduce the file exactly. Each token holds the text it was lexed from,
+// which can be retrieved with Text, and the text between it and the token
+// before it, which can be retrieved with Trivia. The trivia of the first token
+// includes the file header. Any text after the last token is returned as
+// trailing.
+func TokenizeLossless (
+	file *file.File,
+) (
+	tokens   []Token,
+	trailing string,
+	err      error,
+) {
+	lexer := lexingOperation { file: file, lossless: true }
+	err    = lexer.tokenize()
+	if err == io.EOF {
+		err = nil
+	}
+
+	trailing = lexer.keepTrivia()
+	tokens   = lexer.tokens
+	return
+}
+
 // tokenize converts a file into a slice of tokens (lexemes). It will always
 // return a non-nil error, but if nothing went wrong it will return io.EOF.
 func (lexer *lexingOperation) tokenize () (err error) {
@@ -57,7 +86,8 @@ func (lexer *lexingOperation) tokenize () (err error) {
 		number    := lexer.char >= '0' && lexer.char <= '9'
 
 		if number {
-			err = lexer.tokenizeNumberBeginning(false)
+			token := lexer.newToken()
+			err = lexer.tokenizeNumberBeginning(token, false)
 			if err != nil { return }
 		} else if lowercase || uppercase {
 			err = lexer.tokenizeAlphaBeginning()
@@ -119,11 +149,21 @@ func (lexer *lexingOperation) tokenizeAlphaBeginning () (err error) {
 func (lexer *lexingOperation) tokenizeSymbolBeginning () (err error) {
 	switch lexer.char {
 	case '#':
-		// comment
-		for lexer.char != '\n' {
+		// comment. the value is everything after the #, up until the
+		// end of the line.
+		token := lexer.newToken()
+		token.kind = TokenKindComment
+
+		comment := ""
+		for {
 			err = lexer.nextRune()
-			if err != nil { return }
+			if err != nil || lexer.char == '\n' { break }
+			comment += string(lexer.char)
 		}
+
+		token.value = comment
+		token.location.SetWidth(len([]rune(comment)) + 1)
+		lexer.addToken(token)
 	case '\t':
 		// indent level
 		previousToken := lexer.tokens[len(lexer.tokens) - 1]
@@ -419,7 +459,7 @@ func (lexer *lexingOperation) tokenizeDashBeginning () (err error) {
 
 		lexer.addToken(token)
 	} else if lexer.char >= '0' && lexer.char <= '9' {
-		lexer.tokenizeNumberBeginning(true)
+		lexer.tokenizeNumberBeginning(token, true)
 	} else {
 		token.kind = TokenKindMinus
 		lexer.addToken(token)
@@ -456,5 +496,40 @@ func (lexer *lexingOperation) nextRune () (err error) {
 			lexer.file.Location(1),
 			err.Error(), infoerr.ErrorKindError)
 	}
+	if err == nil && lexer.lossless {
+		lexer.source = append(lexer.source, lexer.char)
+	}
+	return
+}
+
+// keepTrivia fills in the text and trivia of each token using the source that
+// has been read, and returns the text after the last token. Tokens are located
+// by row and column, so each row is first converted into an offset.
+func (lexer *lexingOperation) keepTrivia () (trailing string) {
+	rowOffsets := []int { 0 }
+	for offset, char := range lexer.source {
+		if char == '\n' {
+			rowOffsets = append(rowOffsets, offset + 1)
+		}
+	}
+
+	previousEnd := 0
+	for index := range lexer.tokens {
+		token    := &lexer.tokens[index]
+		location := token.location
+		if location.Row() >= len(rowOffsets) { break }
+
+		start := rowOffsets[location.Row()] + location.Column()
+		end   := start + location.Width()
+		if start < previousEnd      { start = previousEnd }
+		if end   > len(lexer.source) { end   = len(lexer.source) }
+		if end   < start             { end   = start }
+
+		token.trivia = string(lexer.source[previousEnd:start])
+		token.text   = string(lexer.source[start:end])
+		previousEnd  = end
+	}
+
+	trailing = string(lexer.source[previousEnd:])
 	return
 }
@@ -1,6 +1,10 @@
 package lexer
 
+import "os"
+import "io/fs"
+import "strings"
 import "testing"
+import "path/filepath"
 import "git.tebibyte.media/arf/arf/file"
 import "git.tebibyte.media/arf/arf/types"
 import "git.tebibyte.media/arf/arf/infoerr"
@@ -245,6 +249,9 @@ func TestTokenizeText (test *testing.T) {
 		quickToken(1, TokenKindNewline, nil),
 		quickToken(35, TokenKindString, "hello world \x40\u0040\U00000040!"),
 		quickToken(1, TokenKindNewline, nil),
+		quickToken(2, TokenKindString, ""),
+		quickToken(3, TokenKindString, "a"),
+		quickToken(1, TokenKindNewline, nil),
 	)
 }
 
@@ -266,6 +273,101 @@ func TestTokenizeIndent (test *testing.T) {
 	)
 }
 
+func TestTokenizeComment (test *testing.T) {
+	checkTokenSlice("../tests/lexer/comment.arf", test,
+		quickToken(11, TokenKindComment, " a comment"),
+		quickToken(1, TokenKindNewline, nil),
+		quickToken(4, TokenKindName, "name"),
+		quickToken(10, TokenKindComment, " trailing"),
+		quickToken(1, TokenKindNewline, nil),
+		quickToken(1, TokenKindIndent, 1),
+		quickToken(9, TokenKindComment, "indented"),
+		quickToken(1, TokenKindNewline, nil),
+		quickToken(1, TokenKindComment, ""),
+	)
+}
+
+func TestTokenizeLossless (test *testing.T) {
+	file, err := file.Open("../tests/lexer/comment.arf")
+	if err != nil {
+		test.Log(err)
+		test.FailNow()
+	}
+	tokens, trailing, err := TokenizeLossless(file)
+	if err != nil {
+		test.Log("returned error:", err)
+		test.FailNow()
+	}
+
+	correct := [][2]string {
+		{ ":arf\n", "# a comment" },
+		{ "",        "\n" },
+		{ "",        "name" },
+		{ " ",       "# trailing" },
+		{ "",        "\n" },
+		{ "",        "\t" },
+		{ "",        "#indented" },
+		{ "",        "\n" },
+		{ "",        "#" },
+	}
+	if len(tokens) != len(correct) || trailing != "" {
+		test.Log("lexed", len(tokens), "tokens, want", len(correct))
+		test.FailNow()
+	}
+	for index, token := range tokens {
+		trivia, text := correct[index][0], correct[index][1]
+		if token.Trivia() != trivia || token.Text() != text {
+			test.Logf (
+				"token %d is %q %q, want %q %q", index,
+				token.Trivia(), token.Text(), trivia, text)
+			test.Fail()
+		}
+	}
+}
+
+func TestTokenizeLosslessAll (test *testing.T) {
+	filepath.WalkDir("../tests", func (
+		path  string,
+		entry fs.DirEntry,
+		err   error,
+	) error {
+		if err != nil || filepath.Ext(path) != ".arf" { return err }
+
+		file, err := file.Open(path)
+		if err != nil { return err }
+		tokens, trailing, err := TokenizeLossless(file)
+		// files that are meant to have errors in them are skipped
+		if err != nil { return nil }
+
+		output := ""
+		for index, token := range tokens {
+			output += token.Trivia() + token.Text()
+
+			// trivia should only ever be whitespace, or else a
+			// token has the wrong width
+			trivia := token.Trivia()
+			if index == 0 {
+				trivia = strings.TrimPrefix(trivia, ":arf\n")
+			}
+			if strings.Trim(trivia, " \t\n") != "" {
+				test.Logf (
+					"token %d of %s has trivia %q",
+					index, path, token.Trivia())
+				test.Fail()
+			}
+		}
+		output += trailing
+
+		source, _ := os.ReadFile(path)
+		if output != string(source) {
+			test.Log("tokens of", path, "do not match source:")
+			test.Log(output)
+			test.Fail()
+		}
+		return nil
+	})
+}
+
 func TestTokenizeErrUnexpectedSymbol (test *testing.T) {
 	compareErr (
 		"../tests/lexer/error/unexpectedSymbol.arf",
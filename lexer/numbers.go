@@ -3,16 +3,20 @@ package lexer
 import "strconv"
 import "git.tebibyte.media/arf/arf/infoerr"
 
-// tokenizeSymbolBeginning lexes a token that starts with a number.
-func (lexer *lexingOperation) tokenizeNumberBeginning (negative bool) (err error) {
+// tokenizeNumberBeginning lexes a token that starts with a number. If the
+// number is negative, token should be located at its minus sign.
+func (lexer *lexingOperation) tokenizeNumberBeginning (
+	token    Token,
+	negative bool,
+) (
+	err error,
+) {
 	var intNumber   uint64
 	var floatNumber float64
 	var isFloat     bool
 	var amountRead  int
 	var totalRead   int
 
-	token := lexer.newToken()
-
 	if lexer.char == '0' {
 		lexer.nextRune()
 		totalRead ++
@@ -14,6 +14,8 @@ func (lexer *lexingOperation) tokenizeString () (err error) {
 	tokenWidth := 2
 
 	for {
+		if lexer.char == '\'' { break }
+
 		if lexer.char == '\\' {
 			err = lexer.nextRune()
 			tokenWidth ++
@@ -33,8 +35,6 @@ func (lexer *lexingOperation) tokenizeString () (err error) {
 			tokenWidth ++
 			if err != nil { return }
 		}
-
-		if lexer.char == '\'' { break }
 	}
 	
 	err = lexer.nextRune()
@@ -10,6 +10,7 @@ type TokenKind int
 const (
 	TokenKindNewline TokenKind = iota
 	TokenKindIndent
+	TokenKindComment
 
         TokenKindSeparator
         TokenKindPermission
@@ -75,6 +76,10 @@ type Token struct {
 	kind     TokenKind
 	location file.Location
 	value    any
+
+	// text and trivia are only set by TokenizeLossless.
+	text   string
+	trivia string
 }
 
 // NewToken provides a way for a new token to be created by other modules for
@@ -111,6 +116,19 @@ func (token Token) Location () (location file.Location) {
 	return token.location
 }
 
+// Text returns the source code that the token was lexed from, exactly as it
+// was written. It is empty unless the token was produced by TokenizeLossless.
+func (token Token) Text () (text string) {
+	return token.text
+}
+
+// Trivia returns the source code between this token and the one before it,
+// such as spaces and empty lines. It is empty unless the token was produced by
+// TokenizeLossless.
+func (token Token) Trivia () (trivia string) {
+	return token.trivia
+}
+
 // NewError creates a new error at this token's location.
 func (token Token) NewError (
 	message string,
@@ -141,6 +159,8 @@ func (tokenKind TokenKind) Describe () (description string) {
 		description = "Newline"
 	case TokenKindIndent:
 		description = "Indent"
+	case TokenKindComment:
+		description = "Comment"
 	case TokenKindSeparator:
 		description = "Separator"
 	case TokenKindPermission:
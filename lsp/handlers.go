@@ -0,0 +1,548 @@
+package lsp
+
+import "os"
+import "sort"
+import "net/url"
+import "path/filepath"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/infoerr"
+import "git.tebibyte.media/arf/arf/analyzer"
+
+// initialize tells the client what the server is capable of.
+func (server *server) initialize (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	server.initialized = true
+	result = map[string] any {
+		"capabilities": map[string] any {
+			"textDocumentSync": map[string] any {
+				"openClose": true,
+				"change":    1,
+				"save":      true,
+			},
+			"hoverProvider":          true,
+			"definitionProvider":     true,
+			"documentSymbolProvider": true,
+		},
+		"serverInfo": map[string] any {
+			"name": "arf",
+		},
+	}
+	return
+}
+
+// ignore handles notifications that the server does not need to act on.
+func (server *server) ignore (params json.RawMessage) (result any, err error) {
+	return
+}
+
+// shutdownRequest makes the server refuse every request other than exit.
+func (server *server) shutdownRequest (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	server.shutdown = true
+	return
+}
+
+// didOpen starts reading a document from memory, and analyzes the module it is
+// in.
+func (server *server) didOpen (params json.RawMessage) (result any, err error) {
+	var arguments DidOpenParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	server.setDocument(path, arguments.TextDocument.Text)
+	err = server.analyze(filepath.Dir(path))
+	return
+}
+
+// didChange updates the text of a document, and analyzes the module it is in
+// again.
+func (server *server) didChange (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	var arguments DidChangeParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	// the server only asks for full document syncing, so the last change
+	// contains the entire document
+	changes := arguments.ContentChanges
+	if len(changes) == 0 { return }
+	server.setDocument(path, changes[len(changes) - 1].Text)
+	err = server.analyze(filepath.Dir(path))
+	return
+}
+
+// didSave analyzes the module a document is in again, in case another file in
+// it was changed on the filesystem.
+func (server *server) didSave (params json.RawMessage) (result any, err error) {
+	var arguments DocumentParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	parser.Forget(filepath.Dir(path))
+	err = server.analyze(filepath.Dir(path))
+	return
+}
+
+// didClose goes back to reading a document from the filesystem.
+func (server *server) didClose (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	var arguments DocumentParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	delete(server.documents, path)
+	parser.ClearOverlay(path)
+	err = server.analyze(filepath.Dir(path))
+	return
+}
+
+// hover shows the type of the name under the cursor.
+func (server *server) hover (params json.RawMessage) (result any, err error) {
+	reference, found, err := server.referenceAt(params)
+	if err != nil || !found { return }
+
+	location := locationRange(reference.Location)
+	description := describeReference(reference)
+	result = Hover {
+		Contents: MarkupContent {
+			Kind:  "markdown",
+			Value: "```arf\n" + description + "\n```",
+		},
+		Range: &location,
+	}
+	return
+}
+
+// definition finds where the name under the cursor is defined. If the cursor
+// is on a require in the metadata header, the first file of the required
+// module is returned.
+func (server *server) definition (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	var arguments TextDocumentPositionParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	tree, err := parser.Fetch(filepath.Dir(path), false)
+	if err == nil {
+		requires := tree.Requires()
+		for !requires.End() {
+			location, _ := tree.RequireLocation(requires.Key())
+			modulePath  := requires.Value()
+			requires.Next()
+
+			if !covers(location, path, arguments.Position) {
+				continue
+			}
+
+			firstFile, exists := moduleFile(modulePath)
+			if !exists { return }
+			result = Location { URI: pathToURI(firstFile) }
+			return
+		}
+	}
+	err = nil
+
+	reference, found, err := server.referenceAt(params)
+	if err != nil || !found { return }
+	definition := reference.Definition
+	if definition.File() == nil { return }
+
+	result = Location {
+		URI:   pathToURI(definition.File().Path()),
+		Range: locationRange(definition),
+	}
+	return
+}
+
+// documentSymbol lists the sections that are defined within a document.
+func (server *server) documentSymbol (
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+) {
+	var arguments DocumentParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	symbols := []DocumentSymbol { }
+	result = symbols
+
+	tree, err := parser.Fetch(filepath.Dir(path), false)
+	if err != nil {
+		// the document cannot be parsed, which has already been
+		// reported as a diagnostic
+		err = nil
+		return
+	}
+
+	sections := tree.Sections()
+	for !sections.End() {
+		section := sections.Value()
+		sections.Next()
+
+		location := section.Location()
+		if location.File() == nil || location.File().Path() != path {
+			continue
+		}
+		symbols = append(symbols, sectionSymbol(section))
+	}
+
+	sort.Slice(symbols, func (left, right int) bool {
+		return symbols[left].Range.Start.Line <
+			symbols[right].Range.Start.Line
+	})
+	result = symbols
+	return
+}
+
+// setDocument makes the parser read a document from memory.
+func (server *server) setDocument (path string, text string) {
+	server.documents[path] = text
+	parser.SetOverlay(path, text)
+}
+
+// closeAll makes the parser read every open document from the filesystem
+// again. It is called when the server stops.
+func (server *server) closeAll () {
+	for path := range server.documents {
+		parser.ClearOverlay(path)
+	}
+}
+
+// analyze analyzes a module, and publishes diagnostics for every file within
+// it. If the analysis succeeds, the result is kept so that names within the
+// module can be looked up. Warnings are published rather than printed, since
+// nobody reads the standard error of the server.
+func (server *server) analyze (modulePath string) (err error) {
+	diagnostics := make(map[string] []Diagnostic)
+	for _, path := range server.moduleFiles(modulePath) {
+		diagnostics[path] = []Diagnostic { }
+	}
+
+	table, failure := analyzer.AnalyzeQuietly (
+		modulePath, false,
+		server.platform)
+	if failure == nil {
+		server.tables[modulePath] = table
+		for _, section := range table.Sorted() {
+			if section.ModulePath() != modulePath { continue }
+			for _, warning := range section.Warnings() {
+				addDiagnostic(diagnostics, warning)
+			}
+		}
+	} else {
+		problem, isProblem := failure.(infoerr.Error)
+		if isProblem {
+			addDiagnostic(diagnostics, problem)
+		} else {
+			err = server.log(failure.Error())
+			if err != nil { return }
+		}
+	}
+
+	paths := []string { }
+	for path := range diagnostics {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		err = server.notify (
+			"textDocument/publishDiagnostics",
+			PublishDiagnosticsParams {
+				URI:         pathToURI(path),
+				Diagnostics: diagnostics[path],
+			})
+		if err != nil { return }
+	}
+	return
+}
+
+// moduleFiles returns the full path of every source file in a module, along
+// with any open documents in it that have not been saved yet.
+func (server *server) moduleFiles (modulePath string) (paths []string) {
+	entries, _ := os.ReadDir(modulePath)
+	found := make(map[string] bool)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".arf" {
+			continue
+		}
+		path := filepath.Join(modulePath, entry.Name())
+		found[path] = true
+		paths = append(paths, path)
+	}
+
+	for path := range server.documents {
+		if filepath.Dir(path) != modulePath || found[path] { continue }
+		paths = append(paths, path)
+	}
+	return
+}
+
+// referenceAt looks up the name at the position given in the parameters of a
+// request, using the most recent analysis of the module it is in.
+func (server *server) referenceAt (
+	params json.RawMessage,
+) (
+	reference analyzer.Reference,
+	found     bool,
+	err       error,
+) {
+	var arguments TextDocumentPositionParams
+	err = decodeParams(params, &arguments)
+	if err != nil { return }
+
+	path, err := uriToPath(arguments.TextDocument.URI)
+	if err != nil { return }
+
+	table, exists := server.tables[filepath.Dir(path)]
+	if !exists { return }
+
+	reference, found = table.ReferenceAt (
+		path,
+		arguments.Position.Line,
+		arguments.Position.Character)
+	return
+}
+
+// addDiagnostic converts an error from the compiler into a diagnostic, and adds
+// it to the list of diagnostics for the file it is in.
+func addDiagnostic (
+	diagnostics map[string] []Diagnostic,
+	problem     infoerr.Error,
+) {
+	if problem.File() == nil { return }
+	path := problem.File().Path()
+
+	severity := SeverityError
+	if problem.Kind() == infoerr.ErrorKindWarn {
+		severity = SeverityWarning
+	}
+
+	diagnostics[path] = append(diagnostics[path], Diagnostic {
+		Range:    locationRange(problem.Location),
+		Severity: severity,
+		Source:   "arf",
+		Message:  problem.Message(),
+	})
+}
+
+// describeReference describes what a name refers to, in a form that looks like
+// ARF code.
+func describeReference (reference analyzer.Reference) (description string) {
+	switch reference.Section.(type) {
+	case *analyzer.FuncSection:
+		description = "func "
+	case *analyzer.DataSection:
+		description = "data "
+	case *analyzer.TypeSection:
+		description = "type "
+	case *analyzer.EnumSection:
+		if reference.Name == reference.Section.Name() {
+			description = "enum "
+		}
+	case *analyzer.FaceSection:
+		description = "face "
+	}
+
+	description += reference.Name
+	if reference.Typed {
+		description += ":" + reference.What.Describe()
+	}
+	return
+}
+
+// sectionSymbol describes a section as a document symbol. The members of enums
+// and object types are listed as its children.
+func sectionSymbol (section parser.Section) (symbol DocumentSymbol) {
+	header := section.Location()
+	symbol = DocumentSymbol {
+		Name:           section.Name(),
+		Detail:         section.Permission().ToString(),
+		Range:          lineRange(header),
+		SelectionRange: locationRange(header),
+	}
+
+	switch section.(type) {
+	case parser.DataSection:
+		symbol.Kind = SymbolKindVariable
+
+	case parser.TypeSection:
+		symbol.Kind = SymbolKindClass
+		typeSection := section.(parser.TypeSection)
+		for index := 0; index < typeSection.MembersLength(); index ++ {
+			member := typeSection.Member(index)
+			location := member.Location()
+			symbol.Children = append (
+				symbol.Children,
+				DocumentSymbol {
+					Name:           member.Name(),
+					Kind:           SymbolKindField,
+					Range:          lineRange(location),
+					SelectionRange: locationRange(location),
+				})
+		}
+
+	case parser.EnumSection:
+		symbol.Kind = SymbolKindEnum
+		enumSection := section.(parser.EnumSection)
+		for index := 0; index < enumSection.Length(); index ++ {
+			member := enumSection.Item(index)
+			location := member.Location()
+			symbol.Children = append (
+				symbol.Children,
+				DocumentSymbol {
+					Name:           member.Name(),
+					Kind:           SymbolKindEnumMember,
+					Range:          lineRange(location),
+					SelectionRange: locationRange(location),
+				})
+		}
+
+	case parser.FaceSection:
+		symbol.Kind = SymbolKindInterface
+
+	case parser.FuncSection:
+		symbol.Kind = SymbolKindFunction
+		if section.(parser.FuncSection).Receiver() != nil {
+			symbol.Kind = SymbolKindMethod
+		}
+	}
+	return
+}
+
+// locationRange converts a location into a range that spans the same text.
+func locationRange (location file.Location) (span Range) {
+	span.Start = Position {
+		Line:      location.Row(),
+		Character: location.Column(),
+	}
+	span.End = span.Start
+	span.End.Character += location.Width()
+	return
+}
+
+// lineRange returns a range that spans the entire line that a location is on.
+func lineRange (location file.Location) (span Range) {
+	span.Start.Line = location.Row()
+	span.End.Line   = location.Row()
+	if location.File() != nil {
+		line := location.File().GetLine(location.Row())
+		span.End.Character = len([]rune(line))
+	}
+	return
+}
+
+// covers returns whether a location in a file covers a position.
+func covers (
+	location file.Location,
+	path     string,
+	position Position,
+) (
+	does bool,
+) {
+	if location.File() == nil || location.File().Path() != path { return }
+	if location.Row() != position.Line { return }
+	does =
+		position.Character >= location.Column() &&
+		position.Character <  location.Column() + location.Width()
+	return
+}
+
+// moduleFile returns the full path of the first source file in a module, in
+// alphabetical order.
+func moduleFile (modulePath string) (path string, exists bool) {
+	entries, err := os.ReadDir(modulePath)
+	if err != nil { return }
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".arf" {
+			continue
+		}
+		path   = filepath.Join(modulePath, entry.Name())
+		exists = true
+		return
+	}
+	return
+}
+
+// decodeParams decodes the parameters of a message. If they are not valid, an
+// error that can be sent to the client is returned.
+func decodeParams (params json.RawMessage, into any) (err error) {
+	err = json.Unmarshal(params, into)
+	if err != nil {
+		err = responseError {
+			Code:    codeInvalidParams,
+			Message: err.Error(),
+		}
+	}
+	return
+}
+
+// uriToPath converts a file URI into a full path on the filesystem.
+func uriToPath (uri string) (path string, err error) {
+	parsed, err := url.Parse(uri)
+	if err != nil { return }
+
+	if parsed.Scheme != "file" {
+		err = responseError {
+			Code:    codeInvalidParams,
+			Message: "only file URIs are supported, not " + uri,
+		}
+		return
+	}
+
+	path = filepath.Clean(parsed.Path)
+	return
+}
+
+// pathToURI converts a full path on the filesystem into a file URI.
+func pathToURI (path string) (uri string) {
+	location := url.URL {
+		Scheme: "file",
+		Path:   path,
+	}
+	uri = location.String()
+	return
+}
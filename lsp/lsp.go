@@ -0,0 +1,285 @@
+/*
+Package lsp implements a language server for the ARF language. It speaks the
+Language Server Protocol over a pair of streams, which are usually the standard
+input and output of the process. The function Serve runs the server until the
+client tells it to exit.
+
+The server publishes the errors and warnings found by the analyzer, shows the
+type of whatever name is under the cursor, finds where sections, variables,
+and required modules are defined, and lists the sections of each document.
+
+Documents that are open in the editor are read from memory instead of from the
+filesystem, so that they can be analyzed without being saved first.
+*/
+package lsp
+
+import "io"
+import "fmt"
+import "bufio"
+import "strconv"
+import "strings"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/analyzer"
+
+// server holds the state of a running language server.
+type server struct {
+	reader   *bufio.Reader
+	writer   io.Writer
+	platform target.Target
+
+	initialized bool
+	shutdown    bool
+
+	// documents contains the text of each open document, indexed by the
+	// full path of the file.
+	documents map[string] string
+
+	// tables contains the most recent successful analysis of each module
+	// that has had a document opened in it, indexed by the full path of
+	// the module.
+	tables map[string] analyzer.SectionTable
+}
+
+// handler responds to a request or notification from the client. The result
+// is ignored for notifications.
+type handler func (
+	server *server,
+	params json.RawMessage,
+) (
+	result any,
+	err    error,
+)
+
+// handlers contains the handler for each method that the server understands.
+var handlers map[string] handler
+
+func init () {
+	handlers = map[string] handler {
+		"initialize":  (*server).initialize,
+		"initialized": (*server).ignore,
+		"shutdown":    (*server).shutdownRequest,
+
+		"textDocument/didOpen":   (*server).didOpen,
+		"textDocument/didChange": (*server).didChange,
+		"textDocument/didSave":   (*server).didSave,
+		"textDocument/didClose":  (*server).didClose,
+
+		"textDocument/hover":          (*server).hover,
+		"textDocument/definition":     (*server).definition,
+		"textDocument/documentSymbol": (*server).documentSymbol,
+	}
+}
+
+// Serve runs a language server that reads messages from input, and writes
+// messages to output. The platform is passed on to the analyzer. Serve returns
+// once the client sends an exit notification, or input ends.
+func Serve (
+	input    io.Reader,
+	output   io.Writer,
+	platform target.Target,
+) (
+	err error,
+) {
+	server := server {
+		reader:    bufio.NewReader(input),
+		writer:    output,
+		platform:  platform,
+		documents: make(map[string] string),
+		tables:    make(map[string] analyzer.SectionTable),
+	}
+	defer server.closeAll()
+
+	for {
+		var body []byte
+		body, err = server.readMessage()
+		if err == io.EOF {
+			err = nil
+			return
+		}
+		if err != nil { return }
+
+		var message request
+		err = json.Unmarshal(body, &message)
+		if err != nil {
+			err = server.respondError(nil, responseError {
+				Code:    codeParseError,
+				Message: err.Error(),
+			})
+			if err != nil { return }
+			continue
+		}
+
+		if message.Method == "exit" { return }
+
+		err = server.handle(message)
+		if err != nil { return }
+	}
+}
+
+// handle runs the handler for a message, and responds to it if it is a
+// request.
+func (server *server) handle (message request) (err error) {
+	isRequest := len(message.ID) > 0
+	method, exists := handlers[message.Method]
+
+	var result any
+	var failure error
+	switch {
+	case !exists:
+		failure = responseError {
+			Code:    codeMethodNotFound,
+			Message: "method not found: " + message.Method,
+		}
+
+	case server.shutdown:
+		failure = responseError {
+			Code:    codeInvalidRequest,
+			Message: "the server has been shut down",
+		}
+
+	case !server.initialized && message.Method != "initialize":
+		failure = responseError {
+			Code:    codeServerNotInitialized,
+			Message: "the server has not been initialized",
+		}
+
+	default:
+		result, failure = server.call(method, message.Params)
+	}
+
+	if !isRequest {
+		// there is nobody to respond to, so at least let the user know
+		// that something went wrong
+		if failure != nil { err = server.log(failure.Error()) }
+		return
+	}
+	if failure == nil {
+		err = server.respond(message.ID, result)
+		return
+	}
+
+	protocolError, isProtocolError := failure.(responseError)
+	if !isProtocolError {
+		protocolError = responseError {
+			Code:    codeInternalError,
+			Message: failure.Error(),
+		}
+	}
+	err = server.respondError(message.ID, protocolError)
+	return
+}
+
+// call runs a handler. If the handler panics, the panic is turned into an
+// internal error so that the server can keep serving other messages.
+func (server *server) call (
+	method handler,
+	params json.RawMessage,
+) (
+	result  any,
+	failure error,
+) {
+	defer func () {
+		recovered := recover()
+		if recovered == nil { return }
+		result  = nil
+		failure = responseError {
+			Code:    codeInternalError,
+			Message: fmt.Sprint("internal error: ", recovered),
+		}
+	} ()
+
+	result, failure = method(server, params)
+	return
+}
+
+// readMessage reads the body of the next message sent by the client.
+func (server *server) readMessage () (body []byte, err error) {
+	length := -1
+	for {
+		var line string
+		line, err = server.reader.ReadString('\n')
+		if err != nil { return }
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" { break }
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			err = fmt.Errorf("malformed header: %s", line)
+			return
+		}
+		if strings.EqualFold(name, "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil { return }
+		}
+	}
+
+	if length < 0 {
+		err = fmt.Errorf("message has no Content-Length header")
+		return
+	}
+
+	body = make([]byte, length)
+	_, err = io.ReadFull(server.reader, body)
+	return
+}
+
+// writeMessage sends a message to the client.
+func (server *server) writeMessage (message any) (err error) {
+	body, err := json.Marshal(message)
+	if err != nil { return }
+
+	_, err = fmt.Fprintf (
+		server.writer,
+		"Content-Length: %d\r\n\r\n",
+		len(body))
+	if err != nil { return }
+	_, err = server.writer.Write(body)
+	return
+}
+
+// respond sends the result of a request to the client.
+func (server *server) respond (id json.RawMessage, result any) (err error) {
+	err = server.writeMessage(response {
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	})
+	return
+}
+
+// respondError tells the client that a request has failed.
+func (server *server) respondError (
+	id      json.RawMessage,
+	failure responseError,
+) (
+	err error,
+) {
+	if id == nil { id = json.RawMessage("null") }
+	err = server.writeMessage(errorResponse {
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   failure,
+	})
+	return
+}
+
+// notify sends a notification to the client.
+func (server *server) notify (method string, params any) (err error) {
+	err = server.writeMessage(notification {
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	return
+}
+
+// log sends a message to the client to be written to its log.
+func (server *server) log (message string) (err error) {
+	err = server.notify("window/logMessage", LogMessageParams {
+		Type:    3,
+		Message: message,
+	})
+	return
+}
@@ -0,0 +1,366 @@
+package lsp
+
+import "os"
+import "fmt"
+import "bytes"
+import "bufio"
+import "testing"
+import "path/filepath"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/target"
+
+// received holds every message that the server sent during a test.
+type received struct {
+	responses     map[int] json.RawMessage
+	errors        map[int] responseError
+	notifications []notification
+}
+
+// runScript sends a list of messages to a language server, and returns
+// everything it sent back. Messages that have an id field are treated as
+// requests, and everything else as notifications.
+func runScript (test *testing.T, messages ...map[string] any) (got received) {
+	input := bytes.Buffer { }
+	for _, message := range messages {
+		message["jsonrpc"] = "2.0"
+		body, err := json.Marshal(message)
+		if err != nil { test.Fatal(err) }
+		fmt.Fprintf(&input, "Content-Length: %d\r\n\r\n", len(body))
+		input.Write(body)
+	}
+
+	output := bytes.Buffer { }
+	err := Serve(&input, &output, target.Default)
+	if err != nil { test.Fatal(err) }
+
+	got.responses = make(map[int] json.RawMessage)
+	got.errors    = make(map[int] responseError)
+	reader := server { reader: bufio.NewReader(&output) }
+	for output.Len() > 0 || reader.reader.Buffered() > 0 {
+		body, err := reader.readMessage()
+		if err != nil { test.Fatal(err) }
+
+		var message struct {
+			ID     *int            `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+			Result json.RawMessage `json:"result"`
+			Error  *responseError  `json:"error"`
+		}
+		err = json.Unmarshal(body, &message)
+		if err != nil { test.Fatal(err) }
+
+		switch {
+		case message.ID == nil:
+			got.notifications = append (
+				got.notifications,
+				notification {
+					Method: message.Method,
+					Params: message.Params,
+				})
+		case message.Error != nil:
+			got.errors[*message.ID] = *message.Error
+		default:
+			got.responses[*message.ID] = message.Result
+		}
+	}
+	return
+}
+
+// diagnostics returns every diagnostic that was published for a file, in the
+// order that they were published in.
+func (got received) diagnostics (uri string) (published [][]Diagnostic) {
+	for _, message := range got.notifications {
+		if message.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params PublishDiagnosticsParams
+		json.Unmarshal(message.Params.(json.RawMessage), &params)
+		if params.URI == uri {
+			published = append(published, params.Diagnostics)
+		}
+	}
+	return
+}
+
+// modulePath returns the full path to a file within the tests directory, and
+// its URI.
+func modulePath (test *testing.T, path string) (full string, uri string) {
+	full, err := filepath.Abs(filepath.Join("../tests/lsp", path))
+	if err != nil { test.Fatal(err) }
+	uri = pathToURI(full)
+	return
+}
+
+// position returns the parameters of a request about a position within a
+// document.
+func position (uri string, line, character int) (params map[string] any) {
+	params = map[string] any {
+		"textDocument": map[string] any { "uri": uri },
+		"position": map[string] any {
+			"line":      line,
+			"character": character,
+		},
+	}
+	return
+}
+
+func TestLanguageServer (test *testing.T) {
+	path, uri := modulePath(test, "basic/main.arf")
+	_, libraryURI := modulePath(test, "library/main.arf")
+	text, err := os.ReadFile(path)
+	if err != nil { test.Fatal(err) }
+
+	broken := bytes.Replace(text, []byte("aLimit]"), []byte("aMissing]"), 1)
+
+	got := runScript (test,
+		map[string] any { "id": 1, "method": "initialize",
+			"params": map[string] any { } },
+		map[string] any { "method": "initialized",
+			"params": map[string] any { } },
+		map[string] any { "method": "textDocument/didOpen",
+			"params": map[string] any {
+				"textDocument": map[string] any {
+					"uri": uri, "languageId": "arf",
+					"version": 1, "text": string(text),
+				},
+			} },
+		map[string] any { "id": 2, "method": "textDocument/hover",
+			"params": position(uri, 10, 19) },
+		map[string] any { "id": 3, "method": "textDocument/hover",
+			"params": position(uri, 10, 13) },
+		map[string] any { "id": 4, "method": "textDocument/definition",
+			"params": position(uri, 10, 19) },
+		map[string] any { "id": 5, "method": "textDocument/definition",
+			"params": position(uri, 1, 12) },
+		map[string] any { "id": 6, "method": "textDocument/definition",
+			"params": position(uri, 11, 22) },
+		map[string] any { "id": 7,
+			"method": "textDocument/documentSymbol",
+			"params": map[string] any {
+				"textDocument": map[string] any { "uri": uri },
+			} },
+		map[string] any { "method": "textDocument/didChange",
+			"params": map[string] any {
+				"textDocument": map[string] any {
+					"uri": uri, "version": 2,
+				},
+				"contentChanges": []any { map[string] any {
+					"text": string(broken),
+				} },
+			} },
+		map[string] any { "method": "textDocument/didClose",
+			"params": map[string] any {
+				"textDocument": map[string] any { "uri": uri },
+			} },
+		map[string] any { "id": 8, "method": "shutdown" },
+		map[string] any { "id": 9, "method": "textDocument/hover",
+			"params": position(uri, 10, 19) },
+		map[string] any { "method": "exit" },
+	)
+
+	checkResult := func (id int, correct string) {
+		result, exists := got.responses[id]
+		if !exists {
+			test.Errorf("request %d: no response, error %v", id,
+				got.errors[id])
+			return
+		}
+		if string(result) != correct {
+			test.Errorf (
+				"request %d:\ncorrect: %s\nresult:  %s",
+				id, correct, result)
+		}
+	}
+
+	checkResult(2,
+		`{"contents":{"kind":"markdown","value":"` +
+		"```arf\\ndata aLimit:Int\\n```" + `"},` +
+		`"range":{"start":{"line":10,"character":18},` +
+		`"end":{"line":10,"character":24}}}`)
+	checkResult(3,
+		`{"contents":{"kind":"markdown","value":"` +
+		"```arf\\nstart:Int\\n```" + `"},` +
+		`"range":{"start":{"line":10,"character":12},` +
+		`"end":{"line":10,"character":17}}}`)
+	checkResult(4,
+		`{"uri":"` + uri + `","range":{"start":{"line":4,` +
+		`"character":0},"end":{"line":4,"character":4}}}`)
+	checkResult(5,
+		`{"uri":"` + libraryURI + `","range":{"start":{"line":0,` +
+		`"character":0},"end":{"line":0,"character":0}}}`)
+	checkResult(6,
+		`{"uri":"` + libraryURI + `","range":{"start":{"line":3,` +
+		`"character":0},"end":{"line":3,"character":4}}}`)
+	checkResult(8, `null`)
+
+	var symbols []DocumentSymbol
+	json.Unmarshal(got.responses[7], &symbols)
+	correctSymbols := []struct {
+		name string
+		kind SymbolKind
+		line int
+	} {
+		{ "aLimit",  SymbolKindVariable, 4  },
+		{ "bCount",  SymbolKindFunction, 6  },
+		{ "cIgnore", SymbolKindFunction, 13 },
+	}
+	if len(symbols) != len(correctSymbols) {
+		test.Fatalf("expected %d symbols, got %v", len(correctSymbols),
+			symbols)
+	}
+	for index, correct := range correctSymbols {
+		symbol := symbols[index]
+		if
+			symbol.Name != correct.name ||
+			symbol.Kind != correct.kind ||
+			symbol.Range.Start.Line != correct.line {
+
+			test.Errorf("symbol %d: expected %v, got %v", index,
+				correct, symbol)
+		}
+	}
+
+	if got.errors[9].Code != codeInvalidRequest {
+		test.Errorf("request after shutdown was not refused")
+	}
+
+	published := got.diagnostics(uri)
+	if len(published) != 3 {
+		test.Fatalf("expected 3 sets of diagnostics, got %v", published)
+	}
+
+	// when opened, the unused input should be warned about
+	checkDiagnostics(test, published[0], Diagnostic {
+		Range: Range {
+			Start: Position { Line: 14, Character: 1 },
+			End:   Position { Line: 14, Character: 2 },
+		},
+		Severity: SeverityWarning,
+		Source:   "arf",
+		Message:  "input unused is never used",
+	})
+
+	// after the change, the missing name should be an error
+	if len(published[1]) != 1 || published[1][0].Severity != SeverityError {
+		test.Errorf("expected one error, got %v", published[1])
+	} else if published[1][0].Range.Start.Line != 10 {
+		test.Errorf("error is on the wrong line: %v", published[1][0])
+	}
+
+	// after closing, the file on disk should be used again
+	checkDiagnostics(test, published[2], published[0]...)
+}
+
+// checkDiagnostics makes sure that a list of diagnostics is correct.
+func checkDiagnostics (
+	test    *testing.T,
+	result  []Diagnostic,
+	correct ...Diagnostic,
+) {
+	mismatch := len(result) != len(correct)
+	for index := 0; !mismatch && index < len(result); index ++ {
+		mismatch = result[index] != correct[index]
+	}
+	if mismatch {
+		test.Errorf("diagnostics:\ncorrect: %v\nresult:  %v",
+			correct, result)
+	}
+}
+
+func TestUnknownMethod (test *testing.T) {
+	got := runScript (test,
+		map[string] any { "id": 1, "method": "initialize",
+			"params": map[string] any { } },
+		map[string] any { "id": 2, "method": "workspace/unknown" },
+		map[string] any { "method": "exit" },
+	)
+
+	if got.errors[2].Code != codeMethodNotFound {
+		test.Errorf("unknown method was not refused: %v", got.errors)
+	}
+}
+
+func TestHandlerPanic (test *testing.T) {
+	handlers["test/panic"] = func (
+		server *server,
+		params json.RawMessage,
+	) (
+		result any,
+		err    error,
+	) {
+		panic("something went wrong")
+	}
+	defer delete(handlers, "test/panic")
+
+	got := runScript (test,
+		map[string] any { "id": 1, "method": "initialize",
+			"params": map[string] any { } },
+		map[string] any { "id": 2, "method": "test/panic" },
+		map[string] any { "method": "test/panic" },
+		map[string] any { "id": 3, "method": "shutdown" },
+		map[string] any { "method": "exit" },
+	)
+
+	if got.errors[2].Code != codeInternalError {
+		test.Errorf("panic was not reported: %v", got.errors)
+	}
+	logged := false
+	for _, message := range got.notifications {
+		if message.Method == "window/logMessage" { logged = true }
+	}
+	if !logged {
+		test.Error("panic in a notification was not logged")
+	}
+	if _, responded := got.responses[3]; !responded {
+		test.Error("server stopped serving after a panic")
+	}
+}
+
+func TestWarnings (test *testing.T) {
+	path, uri := modulePath(test, "warnings/main.arf")
+	text, err := os.ReadFile(path)
+	if err != nil { test.Fatal(err) }
+
+	got := runScript (test,
+		map[string] any { "id": 1, "method": "initialize",
+			"params": map[string] any { } },
+		map[string] any { "method": "textDocument/didOpen",
+			"params": map[string] any {
+				"textDocument": map[string] any {
+					"uri": uri, "languageId": "arf",
+					"version": 1, "text": string(text),
+				},
+			} },
+		map[string] any { "method": "exit" },
+	)
+
+	// warnings that are found outside of the flow checks should be
+	// published too
+	published := got.diagnostics(uri)
+	if len(published) != 1 {
+		test.Fatalf("expected 1 set of diagnostics, got %v", published)
+	}
+	checkDiagnostics(test, published[0], Diagnostic {
+		Range: Range {
+			Start: Position { Line: 3, Character: 21 },
+			End:   Position { Line: 3, Character: 24 },
+		},
+		Severity: SeverityWarning,
+		Source:   "arf",
+		Message:
+			"literal cannot be represented exactly by F32, and " +
+			"will be rounded to 0.10000000149011612",
+	}, Diagnostic {
+		Range: Range {
+			Start: Position { Line: 16, Character: 1 },
+			End:   Position { Line: 16, Character: 8 },
+		},
+		Severity: SeverityWarning,
+		Source:   "arf",
+		Message:
+			"bDivide has 2 outputs, but only 1 of them is used. " +
+			"the rest will be discarded",
+	})
+}
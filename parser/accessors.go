@@ -1,6 +1,8 @@
 package parser
 
+import "git.tebibyte.media/arf/arf/file"
 import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/lexer"
 
 // LookupSection looks returns the section under the give name. If the section
 // does not exist, nil is returned. If a method is being searched for, the type
@@ -32,6 +34,38 @@ func (tree SyntaxTree) ResolveRequire (name string) (path string, exists bool) {
 	return
 }
 
+// Author returns the author named in the module's metadata, if any.
+func (tree SyntaxTree) Author () (author string) {
+	author = tree.author
+	return
+}
+
+// License returns the license named in the module's metadata, if any.
+func (tree SyntaxTree) License () (license string) {
+	license = tree.license
+	return
+}
+
+// Requires returns an iterator for the tree's requires. Each one is the full
+// path of a module, indexed by the name it is referred to by.
+func (tree SyntaxTree) Requires () (iterator types.Iterator[string]) {
+	iterator = types.NewIterator(tree.requires)
+	return
+}
+
+// RequireLocation returns the location of the string that a module was
+// required with in the metadata header. This method will return false for
+// exists if the module has not been imported.
+func (tree SyntaxTree) RequireLocation (
+	name string,
+) (
+	location file.Location,
+	exists   bool,
+) {
+	location, exists = tree.requireLocations[name]
+	return
+}
+
 // Length returns the amount of names in the identifier.
 func (identifier Identifier) Length () (length int) {
 	length = len(identifier.trail)
@@ -143,6 +177,13 @@ func (argument Argument) Value () (value any) {
 	return
 }
 
+// Offset returns the offset of the dereference. If a simple dereference was
+// parsed, this is zero.
+func (dereference Dereference) Offset () (offset uint64) {
+	offset = dereference.offset
+	return
+}
+
 // Length returns the amount of members in the section.
 func (section EnumSection) Length () (length int) {
 	length = len(section.members)
@@ -156,7 +197,7 @@ func (section EnumSection) Item (index int) (member EnumMember) {
 }
 
 // InputsLength returns the amount of inputs in the behavior.
-func (behavior FaceBehavior) IntputsLength () (length int) {
+func (behavior FaceBehavior) InputsLength () (length int) {
 	length = len(behavior.inputs)
 	return
 }
@@ -179,6 +220,20 @@ func (behavior FaceBehavior) Output (index int) (output Declaration) {
 	return
 }
 
+// Kind returns whether the interface is an object interface or a function
+// interface.
+func (section FaceSection) Kind () (kind FaceKind) {
+	kind = section.kind
+	return
+}
+
+// Inherits returns the identifier of the interface that the interface inherits
+// from.
+func (section FaceSection) Inherits () (inherits Identifier) {
+	inherits = section.inherits
+	return
+}
+
 // Behaviors returns an iterator for the interface's behaviors.
 func (section FaceSection) Behaviors () (iterator types.Iterator[FaceBehavior]) {
 	iterator = types.NewIterator(section.behaviors)
@@ -203,6 +258,13 @@ func (phrase Phrase) Kind () (kind PhraseKind) {
 	return
 }
 
+// Operator returns the operator token of the phrase, if it is an operator
+// phrase.
+func (phrase Phrase) Operator () (operator lexer.TokenKind) {
+	operator = phrase.operator
+	return
+}
+
 // ReturneesLength returns the amount of things the phrase returns to.
 func (phrase Phrase) ReturneesLength () (length int) {
 	length = len(phrase.returnees)
@@ -246,6 +308,12 @@ func (section FuncSection) OutputsLength () (length int) {
 	return
 }
 
+// Output returns the output at index.
+func (section FuncSection) Output (index int) (output FuncOutput) {
+	output = section.outputs[index]
+	return
+}
+
 // Root returns the root block of the section.
 func (section FuncSection) Root () (root Block) {
 	root = section.root
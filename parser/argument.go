@@ -47,6 +47,7 @@ func (parser *parsingOperation) parseArgument () (argument Argument, err error)
 			declaration.what = what
 			declaration.name = identifier.trail[0]
 			declaration.location = argument.Location()
+			declaration.end      = parser.endLocation()
 
 			argument.kind  = ArgumentKindDeclaration
 			argument.value = declaration
@@ -94,5 +95,6 @@ func (parser *parsingOperation) parseArgument () (argument Argument, err error)
 			parser.token.Kind().Describe())
 	}
 
+	argument.end = parser.endLocation()
 	return
 }
@@ -13,30 +13,35 @@ func (parser *parsingOperation) parseBody () (err error) {
 		switch sectionType {
 		case "data":
 			section, parseErr := parser.parseDataSection()
+			section.end = parser.endLocation()
 			err = parser.tree.addSection(section)
 			if err      != nil { return }
 			if parseErr != nil { return parseErr }
 			
 		case "type":
 			section, parseErr := parser.parseTypeSection()
+			section.end = parser.endLocation()
 			err = parser.tree.addSection(section)
 			if err      != nil { return }
 			if parseErr != nil { return parseErr }
 			
 		case "face":
 			section, parseErr := parser.parseFaceSection()
+			section.end = parser.endLocation()
 			err = parser.tree.addSection(section)
 			if err      != nil { return }
 			if parseErr != nil { return parseErr }
 			
 		case "enum":
 			section, parseErr := parser.parseEnumSection()
+			section.end = parser.endLocation()
 			err = parser.tree.addSection(section)
 			if err      != nil { return }
 			if parseErr != nil { return parseErr }
 			
 		case "func":
 			section, parseErr := parser.parseFuncSection()
+			section.end = parser.endLocation()
 			err = parser.tree.addSection(section)
 			if err      != nil { return }
 			if parseErr != nil { return parseErr }
@@ -59,6 +64,13 @@ func (tree *SyntaxTree) addSection (section Section) (err error) {
 	if isFuncSection {
 		receiver := funcSection.receiver
 		if receiver != nil {
+			if !validReceiver(*receiver) {
+				err = receiver.NewError (
+					"method receiver must point to a " +
+					"basic type",
+					infoerr.ErrorKindError)
+				return
+			}
 			index = receiver.what.points.name.trail[0] + "_" + index
 		}
 	}
@@ -74,3 +86,15 @@ func (tree *SyntaxTree) addSection (section Section) (err error) {
 	tree.sections[index] = section
 	return
 }
+
+// validReceiver returns whether a method receiver points to a basic type. The
+// name of that type is used to tell apart methods of different types.
+func validReceiver (receiver Declaration) (valid bool) {
+	points := receiver.what.points
+	valid =
+		receiver.what.kind == TypeKindPointer &&
+		points != nil &&
+		points.kind == TypeKindBasic &&
+		len(points.name.trail) > 0
+	return
+}
@@ -0,0 +1,376 @@
+package parser
+
+import "fmt"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/lexer"
+
+// The functions and methods in this file build syntax trees without parsing
+// anything, so that programs can generate ARF code. Nodes that are built this
+// way have no location. Converting a built tree to a string with ToString
+// produces code that parses back into an equivalent tree.
+
+// NewSyntaxTree creates a new syntax tree with no sections.
+func NewSyntaxTree () (tree SyntaxTree) {
+	tree = SyntaxTree {
+		requires:         make(map[string] string),
+		requireLocations: make(map[string] file.Location),
+		sections:         make(map[string] Section),
+	}
+	return
+}
+
+// SetAuthor sets the author named in the module's metadata.
+func (tree *SyntaxTree) SetAuthor (author string) {
+	tree.author = author
+}
+
+// SetLicense sets the license named in the module's metadata.
+func (tree *SyntaxTree) SetLicense (license string) {
+	tree.license = license
+}
+
+// AddRequire adds a module to the tree's requires. The module path must be the
+// full path of the module, starting with '/', and the module is referred to by
+// the last element of it.
+func (tree *SyntaxTree) AddRequire (modulePath string) {
+	tree.requires[filepath.Base(modulePath)] = modulePath
+}
+
+// AddSection adds a section to the tree. If the tree already has a section with
+// the same name, or the section is a method whose receiver does not point to a
+// basic type, an error is returned.
+func (tree *SyntaxTree) AddSection (section Section) (err error) {
+	err = tree.addSection(section)
+	return
+}
+
+// NewIdentifier creates an identifier out of a chain of names.
+func NewIdentifier (trail ...string) (identifier Identifier) {
+	identifier.trail = trail
+	return
+}
+
+// NewBasicType creates a basic type that inherits from the named type.
+func NewBasicType (name Identifier) (what Type) {
+	what.kind = TypeKindBasic
+	what.name = name
+	return
+}
+
+// NewPointerType creates a type that points to another type.
+func NewPointerType (points Type) (what Type) {
+	what.kind   = TypeKindPointer
+	what.points = &points
+	return
+}
+
+// NewVariableArrayType creates a type that is an array of variable length of
+// another type.
+func NewVariableArrayType (points Type) (what Type) {
+	what.kind   = TypeKindVariableArray
+	what.points = &points
+	return
+}
+
+// SetLength makes the type a fixed length array, if length is greater than 1.
+func (what *Type) SetLength (length uint64) {
+	what.length = length
+}
+
+// SetMutable sets whether or not the type's data is mutable.
+func (what *Type) SetMutable (mutable bool) {
+	what.mutable = mutable
+}
+
+// NewDeclaration creates a variable declaration.
+func NewDeclaration (name string, what Type) (declaration Declaration) {
+	declaration.name = name
+	declaration.what = what
+	return
+}
+
+// NewArgument creates an argument holding value, which must be a Phrase, List,
+// Dereference, Identifier, Declaration, int64, uint64, float64, or string. Just
+// like the parser does, non negative int64 values are stored as uint64 values,
+// because only negative numbers are signed when they are written. Any other
+// value causes a panic.
+func NewArgument (value any) (argument Argument) {
+	argument.value = value
+	switch value.(type) {
+	case Phrase:      argument.kind = ArgumentKindPhrase
+	case List:        argument.kind = ArgumentKindList
+	case Dereference: argument.kind = ArgumentKindDereference
+	case Identifier:  argument.kind = ArgumentKindIdentifier
+	case Declaration: argument.kind = ArgumentKindDeclaration
+	case uint64:      argument.kind = ArgumentKindUInt
+	case float64:     argument.kind = ArgumentKindFloat
+	case string:      argument.kind = ArgumentKindString
+	case int64:
+		if value.(int64) < 0 {
+			argument.kind  = ArgumentKindInt
+		} else {
+			argument.kind  = ArgumentKindUInt
+			argument.value = uint64(value.(int64))
+		}
+	default:
+		panic(fmt.Sprintf("cannot make an argument out of %T", value))
+	}
+	return
+}
+
+// NewList creates an array or object literal.
+func NewList (arguments ...Argument) (list List) {
+	list.arguments = arguments
+	return
+}
+
+// NewDereference creates a dereference of argument. If offset is zero, it is a
+// simple dereference, and otherwise it is an array subscript.
+func NewDereference (
+	argument Argument,
+	offset   uint64,
+) (
+	dereference Dereference,
+) {
+	dereference.argument = argument
+	dereference.offset   = offset
+	return
+}
+
+// NewPhrase creates a phrase with the specified command. The kind of the
+// phrase is determined from the command in the same way that the parser does,
+// so a command of "if" creates an if phrase, and a string command creates an
+// arbitrary phrase.
+func NewPhrase (command Argument, arguments ...Argument) (phrase Phrase) {
+	phrase.command   = command
+	phrase.kind      = phraseKindOf(command)
+	phrase.arguments = arguments
+	return
+}
+
+// NewOperatorPhrase creates a phrase whose command is an operator token, such
+// as lexer.TokenKindPlus. A colon creates a case phrase, and an assignment
+// operator creates an assignment phrase. If the token is not an operator, this
+// function panics.
+func NewOperatorPhrase (
+	operator  lexer.TokenKind,
+	arguments ...Argument,
+) (
+	phrase Phrase,
+) {
+	if !isTokenOperator(lexer.NewToken(operator, nil)) {
+		panic (
+			"phrase command is not an operator: " +
+			operator.Describe())
+	}
+
+	switch operator {
+	case lexer.TokenKindColon:
+		phrase.kind = PhraseKindCase
+	case lexer.TokenKindAssignment:
+		phrase.kind = PhraseKindAssign
+	default:
+		phrase.kind     = PhraseKindOperator
+		phrase.operator = operator
+	}
+	phrase.arguments = arguments
+	return
+}
+
+// SetReturnees sets the things that the phrase returns to.
+func (phrase *Phrase) SetReturnees (returnees ...Argument) {
+	phrase.returnees = returnees
+}
+
+// SetBlock sets the block under the phrase. Only control flow phrases have a
+// block under them.
+func (phrase *Phrase) SetBlock (block Block) {
+	phrase.block = block
+}
+
+// NewDataSection creates a data section. Its value can be set with
+// SetArgument.
+func NewDataSection (
+	name       string,
+	permission types.Permission,
+	what       Type,
+) (
+	section DataSection,
+) {
+	section.name       = name
+	section.permission = permission
+	section.what       = what
+	return
+}
+
+// SetExternal sets whether or not the data section is external.
+func (section *DataSection) SetExternal (external bool) {
+	section.external = external
+}
+
+// NewTypeSection creates a type section. Its default value can be set with
+// SetArgument.
+func NewTypeSection (
+	name       string,
+	permission types.Permission,
+	what       Type,
+) (
+	section TypeSection,
+) {
+	section.name       = name
+	section.permission = permission
+	section.what       = what
+	return
+}
+
+// AddMember adds a new member to the type section.
+func (section *TypeSection) AddMember (member TypeSectionMember) {
+	section.members = append(section.members, member)
+}
+
+// NewTypeSectionMember creates a member of a type section. If what is a zero
+// value type, the member keeps the type it already has in the parent type. Its
+// default value can be set with SetArgument.
+func NewTypeSectionMember (
+	name       string,
+	permission types.Permission,
+	what       Type,
+) (
+	member TypeSectionMember,
+) {
+	member.name       = name
+	member.permission = permission
+	member.what       = what
+	return
+}
+
+// SetBitWidth sets the bit width of the type member. If it is zero, it is
+// unspecified.
+func (member *TypeSectionMember) SetBitWidth (width uint64) {
+	member.bitWidth = width
+}
+
+// NewEnumSection creates an enum section.
+func NewEnumSection (
+	name       string,
+	permission types.Permission,
+	what       Type,
+) (
+	section EnumSection,
+) {
+	section.name       = name
+	section.permission = permission
+	section.what       = what
+	return
+}
+
+// AddMember adds a member to the enum section.
+func (section *EnumSection) AddMember (member EnumMember) {
+	section.members = append(section.members, member)
+}
+
+// NewEnumMember creates a member of an enum section. Its value can be set with
+// SetArgument.
+func NewEnumMember (name string) (member EnumMember) {
+	member.name = name
+	return
+}
+
+// NewFaceSection creates an interface section. It is an empty interface until
+// behaviors, inputs, or outputs are added to it.
+func NewFaceSection (
+	name       string,
+	permission types.Permission,
+	inherits   Identifier,
+) (
+	section FaceSection,
+) {
+	section.name       = name
+	section.permission = permission
+	section.inherits   = inherits
+	return
+}
+
+// AddBehavior adds a behavior to the interface, making it a type interface.
+func (section *FaceSection) AddBehavior (behavior FaceBehavior) {
+	section.kind = FaceKindType
+	if section.behaviors == nil {
+		section.behaviors = make(map[string] FaceBehavior)
+	}
+	section.behaviors[behavior.name] = behavior
+}
+
+// AddInput adds an input to the interface, making it a function interface.
+func (section *FaceSection) AddInput (input Declaration) {
+	section.kind = FaceKindFunc
+	section.FaceBehavior.AddInput(input)
+}
+
+// AddOutput adds an output to the interface, making it a function interface.
+func (section *FaceSection) AddOutput (output Declaration) {
+	section.kind = FaceKindFunc
+	section.FaceBehavior.AddOutput(output)
+}
+
+// NewFaceBehavior creates a behavior of a type interface.
+func NewFaceBehavior (name string) (behavior FaceBehavior) {
+	behavior.name = name
+	return
+}
+
+// AddInput adds an input to the behavior.
+func (behavior *FaceBehavior) AddInput (input Declaration) {
+	behavior.inputs = append(behavior.inputs, input)
+}
+
+// AddOutput adds an output to the behavior.
+func (behavior *FaceBehavior) AddOutput (output Declaration) {
+	behavior.outputs = append(behavior.outputs, output)
+}
+
+// NewFuncSection creates a function section.
+func NewFuncSection (
+	name       string,
+	permission types.Permission,
+) (
+	section FuncSection,
+) {
+	section.name       = name
+	section.permission = permission
+	return
+}
+
+// SetReceiver makes the function a method of the type that the receiver points
+// to.
+func (section *FuncSection) SetReceiver (receiver Declaration) {
+	section.receiver = &receiver
+}
+
+// AddInput adds an input to the function.
+func (section *FuncSection) AddInput (input Declaration) {
+	section.inputs = append(section.inputs, input)
+}
+
+// AddOutput adds an output to the function.
+func (section *FuncSection) AddOutput (output FuncOutput) {
+	section.outputs = append(section.outputs, output)
+}
+
+// SetRoot sets the root block of the function.
+func (section *FuncSection) SetRoot (root Block) {
+	section.root = root
+}
+
+// SetExternal sets whether or not the function is an external function.
+func (section *FuncSection) SetExternal (external bool) {
+	section.external = external
+}
+
+// NewFuncOutput creates a function output. Its default value can be set with
+// SetArgument.
+func NewFuncOutput (declaration Declaration) (output FuncOutput) {
+	output.Declaration = declaration
+	return
+}
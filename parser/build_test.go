@@ -0,0 +1,278 @@
+package parser
+
+import "os"
+import "reflect"
+import "testing"
+import "io/fs"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/lexer"
+
+// locationType is skipped when comparing trees, because a tree that has been
+// built or printed and parsed again will not have the same locations.
+var locationType = reflect.TypeOf(file.Location { })
+
+// equivalent returns whether two tree nodes are the same, apart from their
+// locations. Nil and empty slices and maps are considered the same.
+func equivalent (left reflect.Value, right reflect.Value) (same bool) {
+	if left.Kind() != right.Kind() { return }
+
+	switch left.Kind() {
+	case reflect.Struct:
+		if left.Type() != right.Type() { return }
+		if left.Type() == locationType { return true }
+		for index := 0; index < left.NumField(); index ++ {
+			if !equivalent(left.Field(index), right.Field(index)) {
+				return
+			}
+		}
+
+	case reflect.Slice:
+		if left.Len() != right.Len() { return }
+		for index := 0; index < left.Len(); index ++ {
+			if !equivalent(left.Index(index), right.Index(index)) {
+				return
+			}
+		}
+
+	case reflect.Map:
+		if left.Len() != right.Len() { return }
+		for _, key := range left.MapKeys() {
+			rightValue := right.MapIndex(key)
+			if !rightValue.IsValid() { return }
+			leftValue := left.MapIndex(key)
+			if !equivalent(leftValue, rightValue) { return }
+		}
+
+	case reflect.Pointer, reflect.Interface:
+		if left.IsNil() || right.IsNil() {
+			return left.IsNil() == right.IsNil()
+		}
+		if left.Elem().Type() != right.Elem().Type() { return }
+		return equivalent(left.Elem(), right.Elem())
+
+	case reflect.Bool:
+		return left.Bool() == right.Bool()
+	case reflect.Int, reflect.Int64:
+		return left.Int() == right.Int()
+	case reflect.Uint64:
+		return left.Uint() == right.Uint()
+	case reflect.Float64:
+		return left.Float() == right.Float()
+	case reflect.String:
+		return left.String() == right.String()
+
+	default:
+		panic("cannot compare " + left.Kind().String())
+	}
+	return true
+}
+
+// reparse converts a tree to a string and parses it again, as a module that is
+// only in memory.
+func reparse (
+	tree  SyntaxTree,
+	skim  bool,
+	test *testing.T,
+) (
+	reparsed SyntaxTree,
+	err      error,
+) {
+	modulePath := filepath.Join(test.TempDir(), "module")
+	filePath   := filepath.Join(modulePath, "main.arf")
+	SetOverlay(filePath, tree.ToString(0))
+	defer ClearOverlay(filePath)
+	reparsed, err = Fetch(modulePath, skim)
+	return
+}
+
+// checkRoundTrip checks that a tree parses back into an equivalent tree after
+// it is converted to a string.
+func checkRoundTrip (tree SyntaxTree, skim bool, test *testing.T) {
+	reparsed, err := reparse(tree, skim, test)
+	if err != nil {
+		test.Log("SOURCE:\n" + tree.ToString(0))
+		test.Log("reparsing returned error:")
+		test.Log(err.Error())
+		test.Fail()
+		return
+	}
+
+	if !equivalent(reflect.ValueOf(tree), reflect.ValueOf(reparsed)) {
+		test.Log("BEFORE:\n" + tree.ToString(0))
+		test.Log("AFTER:\n"  + reparsed.ToString(0))
+		test.Fail()
+	}
+}
+
+func TestRoundTrip (test *testing.T) {
+	cwd, _ := os.Getwd()
+	modules := map[string] bool { }
+	filepath.WalkDir (
+		filepath.Join(cwd, "../tests/parser"),
+		func (path string, entry fs.DirEntry, err error) error {
+			if filepath.Ext(path) == ".arf" {
+				modules[filepath.Dir(path)] = true
+			}
+			return err
+		})
+
+	for modulePath := range modules {
+		// the full module still uses old syntax, and does not parse
+		if filepath.Base(modulePath) == "full" { continue }
+
+		// the skim module has function bodies that only make sense
+		// when skimming
+		for _, skim := range []bool { true, false } {
+			skimOnly := filepath.Base(modulePath) == "skim"
+			if !skim && skimOnly { continue }
+			test.Log("round trip of", modulePath, "skim:", skim)
+			tree, err := Fetch(modulePath, skim)
+			if err != nil {
+				test.Log("returned error:")
+				test.Log(err.Error())
+				test.Fail()
+				continue
+			}
+			checkRoundTrip(tree, skim, test)
+		}
+	}
+}
+
+// buildWalkTree builds the same tree as the module in tests/parser/walk.
+func buildWalkTree (test *testing.T) (tree SyntaxTree) {
+	name := func (trail ...string) (argument Argument) {
+		return NewArgument(NewIdentifier(trail...))
+	}
+	basic := func (name string) (what Type) {
+		return NewBasicType(NewIdentifier(name))
+	}
+
+	tree = NewSyntaxTree()
+
+	data := NewDataSection (
+		"aData", types.PermissionReadOnly,
+		NewVariableArrayType(basic("Int")))
+	data.SetArgument(NewArgument(NewList (
+		NewArgument(uint64(1)),
+		NewArgument(uint64(2)))))
+
+	bType := NewTypeSection("bType", types.PermissionReadOnly, basic("Obj"))
+	memberType := basic("Int")
+	memberType.SetLength(8)
+	member := NewTypeSectionMember (
+		"member", types.PermissionReadWrite,
+		memberType)
+	member.SetArgument(NewArgument(NewDereference(name("aData"), 1)))
+	bType.AddMember(member)
+
+	cEnum := NewEnumSection("cEnum", types.PermissionReadOnly, basic("U8"))
+	cEnum.AddMember(NewEnumMember("first"))
+	second := NewEnumMember("second")
+	second.SetArgument(NewArgument(int64(5)))
+	cEnum.AddMember(second)
+
+	dFace := NewFaceSection (
+		"dFace", types.PermissionReadOnly,
+		NewIdentifier("Face"))
+	behave := NewFaceBehavior("behave")
+	behave.AddInput(NewDeclaration("input", basic("Int")))
+	dFace.AddBehavior(behave)
+
+	eFace := NewFaceSection (
+		"eFace", types.PermissionReadOnly,
+		NewIdentifier("Func"))
+	eFace.AddOutput(NewDeclaration("output", basic("Int")))
+
+	fFunc := NewFuncSection("fFunc", types.PermissionReadOnly)
+	receiverType := NewPointerType(basic("bType"))
+	fFunc.SetReceiver(NewDeclaration("receiver", receiverType))
+	fFunc.AddInput(NewDeclaration("input", basic("Int")))
+	output := NewFuncOutput(NewDeclaration("output", basic("Int")))
+	output.SetArgument(NewArgument(uint64(3)))
+	fFunc.AddOutput(output)
+
+	condition := NewOperatorPhrase (
+		lexer.TokenKindLessThan,
+		name("input"), NewArgument(uint64(2)))
+	ifPhrase := NewPhrase(name("if"), NewArgument(condition))
+	ifPhrase.SetBlock(Block {
+		NewOperatorPhrase (
+			lexer.TokenKindAssignment,
+			NewArgument(NewDeclaration("x", basic("Int"))),
+			NewArgument(NewPhrase (
+				name("cast"),
+				name("input"), name("Int")))),
+	})
+	call := NewPhrase(name("fn"), name("input"))
+	call.SetReturnees(name("output"))
+	fFunc.SetRoot(Block { ifPhrase, call })
+
+	for _, section := range []Section {
+		data, bType, cEnum, dFace, eFace, fFunc,
+	} {
+		err := tree.AddSection(section)
+		if err != nil {
+			test.Log("adding section returned error:", err)
+			test.FailNow()
+		}
+	}
+	return
+}
+
+func TestBuild (test *testing.T) {
+	built  := buildWalkTree(test)
+	parsed := walkTree(test)
+	if !equivalent(reflect.ValueOf(built), reflect.ValueOf(parsed)) {
+		test.Log("BUILT:\n"  + built.ToString(0))
+		test.Log("PARSED:\n" + parsed.ToString(0))
+		test.Fail()
+	}
+	checkRoundTrip(built, false, test)
+}
+
+func TestBuildLiterals (test *testing.T) {
+	tree := NewSyntaxTree()
+	tree.SetAuthor("Someone 'Quoted'")
+	tree.SetLicense("line\nbreak")
+
+	values := []any {
+		int64(-5), int64(7), uint64(0), float64(2), float64(-0.125),
+		"it's a \\ \t \x00 string", "🐛",
+	}
+	for index, value := range values {
+		section := NewDataSection (
+			string(rune('a' + index)), types.PermissionPrivate,
+			NewBasicType(NewIdentifier("Value")))
+		section.SetDoc("documentation\n\nwith a blank line")
+		section.SetArgument(NewArgument(value))
+		tree.AddSection(section)
+	}
+	checkRoundTrip(tree, false, test)
+}
+
+func TestBuildErrors (test *testing.T) {
+	tree := NewSyntaxTree()
+	check := func (section Section, correct string) {
+		err := tree.AddSection(section)
+		if err == nil || err.Error() != correct {
+			test.Log("CORRECT:", correct)
+			test.Log("RESULT: ", err)
+			test.Fail()
+		}
+	}
+
+	method := NewFuncSection("aMethod", types.PermissionReadOnly)
+	method.SetReceiver(NewDeclaration (
+		"r", NewBasicType(NewIdentifier("Int"))))
+	check(method, "method receiver must point to a basic type")
+
+	method.SetReceiver(NewDeclaration ("r", NewPointerType (
+		NewVariableArrayType(NewBasicType(NewIdentifier("Int"))))))
+	check(method, "method receiver must point to a basic type")
+
+	function := NewFuncSection("aFunc", types.PermissionReadOnly)
+	tree.AddSection(function)
+	check(function, "cannot have multiple sections with the same name")
+}
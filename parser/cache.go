@@ -1,5 +1,7 @@
 package parser
 
+import "path/filepath"
+
 // cacheItem stores an item of the parser cache.
 type cacheItem struct {
 	tree    SyntaxTree
@@ -9,3 +11,49 @@ type cacheItem struct {
 // cache stores all modules that have been parsed so far. They are indexed with
 // their full path on the filesystem, starting with '/'.
 var cache = make(map[string] cacheItem)
+
+// overlays stores the contents of files that should be parsed instead of what
+// is on the filesystem. They are indexed with the full path of the file.
+var overlays = make(map[string] string)
+
+// hosts stores the paths of modules that are provided by the program using the
+// parser, indexed by the name they are required with.
+var hosts = make(map[string] string)
+
+// SetOverlay makes the parser read the file at path from contents instead of
+// from the filesystem. This is used by editors to analyze files that have not
+// been saved yet. The module containing the file is removed from the cache, so
+// that it will be parsed again.
+func SetOverlay (path string, contents string) {
+	overlays[path] = contents
+	Forget(filepath.Dir(path))
+}
+
+// ClearOverlay makes the parser read the file at path from the filesystem
+// again, and removes the module containing it from the cache.
+func ClearOverlay (path string) {
+	delete(overlays, path)
+	Forget(filepath.Dir(path))
+}
+
+// SetHostModule makes requiring the module called name find the module at
+// modulePath, instead of the one in the include path. This lets programs such
+// as the interpreter provide modules of their own without hiding the ones that
+// are installed.
+func SetHostModule (name string, modulePath string) {
+	hosts[name] = modulePath
+}
+
+// ClearHostModule makes requiring the module called name find the one in the
+// include path again.
+func ClearHostModule (name string) {
+	delete(hosts, name)
+}
+
+// Forget removes the module at the specified path from the cache, so that it is
+// parsed again the next time it is fetched.
+func Forget (modulePath string) {
+	modulePath = filepath.Clean(modulePath)
+	delete(cache, modulePath)
+	delete(cache, modulePath + "/")
+}
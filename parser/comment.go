@@ -0,0 +1,77 @@
+package parser
+
+import "strings"
+import "git.tebibyte.media/arf/arf/lexer"
+
+// takeComments removes comments from a list of tokens, along with any lines
+// that only contain a comment. Comments that are on the lines directly above a
+// line of code are remembered as the documentation for whatever that line
+// defines, which can be retrieved using the documentation method.
+func (parser *parsingOperation) takeComments (
+	tokens []lexer.Token,
+) (
+	filtered []lexer.Token,
+) {
+	parser.docs = make(map[int] string)
+
+	// block holds the comment lines directly above the current line
+	block     := []string { }
+	lastRow   := -1
+	lineStart := 0
+
+	for index, token := range tokens {
+		lastOfLine :=
+			token.Is(lexer.TokenKindNewline) ||
+			index == len(tokens) - 1
+		if !lastOfLine { continue }
+
+		line := tokens[lineStart:index + 1]
+		lineStart = index + 1
+
+		var code    []lexer.Token
+		var comment lexer.Token
+		codeRow    := -1
+		hasComment := false
+		for _, token := range line {
+			switch token.Kind() {
+			case lexer.TokenKindComment:
+				comment    = token
+				hasComment = true
+				continue
+			case lexer.TokenKindIndent, lexer.TokenKindNewline:
+			default:
+				if codeRow < 0 {
+					codeRow = token.Location().Row()
+				}
+			}
+			code = append(code, token)
+		}
+
+		if codeRow >= 0 {
+			if len(block) > 0 && lastRow == codeRow - 1 {
+				parser.docs[codeRow] = strings.Join(block, "\n")
+			}
+			block = block[:0]
+			filtered = append(filtered, code...)
+			continue
+		}
+
+		if !hasComment { continue }
+
+		// a comment that is not directly below the one before it
+		// starts a new block
+		row := comment.Location().Row()
+		if lastRow != row - 1 { block = block[:0] }
+		text := comment.Value().(string)
+		block   = append(block, strings.TrimPrefix(text, " "))
+		lastRow = row
+	}
+	return
+}
+
+// documentation returns the doc comment of the line that the current token is
+// on.
+func (parser *parsingOperation) documentation () (doc string) {
+	doc = parser.docs[parser.token.Location().Row()]
+	return
+}
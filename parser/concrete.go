@@ -0,0 +1,228 @@
+package parser
+
+import "io"
+import "fmt"
+import "sort"
+import "strings"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// ConcreteTree is a lossless syntax tree of a single file. Along with the
+// syntax tree of the file, it keeps every token in the file, including
+// comments, indentation, and brackets, as well as the spaces and empty lines
+// between them. This means that the file can be printed back out exactly as it
+// was written, and that nodes can be replaced with new code without disturbing
+// anything around them. It is meant to be used by refactoring tools.
+type ConcreteTree struct {
+	tree     SyntaxTree
+	file     *file.File
+	tokens   []lexer.Token
+	trailing string
+
+	// indices maps the position of each token to its index in tokens.
+	indices map[position] int
+
+	// replacements holds new code for the nodes that have been replaced.
+	// It is sorted by where each replacement starts, and no two
+	// replacements overlap.
+	replacements []replacement
+}
+
+// position is the row and column of a token in a file.
+type position struct {
+	row    int
+	column int
+}
+
+// spanned is any node that knows where it starts and ends.
+type spanned interface {
+	Location () (location file.Location)
+	End      () (end file.Location)
+}
+
+// replacement holds new code for a span of tokens, from start to end
+// inclusive.
+type replacement struct {
+	start int
+	end   int
+	text  string
+}
+
+// ParseConcrete parses the file at filePath into a concrete tree. Just like
+// Fetch, it reads the file from its overlay if it has one. Only the sections
+// in this file are in the syntax tree, rather than every section in the
+// module.
+func ParseConcrete (filePath string) (tree *ConcreteTree, err error) {
+	var sourceFile *file.File
+	contents, overlaid := overlays[filePath]
+	if overlaid {
+		sourceFile = file.Load(filePath, contents)
+	} else {
+		sourceFile, err = file.Open(filePath)
+		if err != nil { return }
+	}
+
+	tokens, trailing, err := lexer.TokenizeLossless(sourceFile)
+	sourceFile.Close()
+	if err != nil { return }
+
+	parser := parsingOperation {
+		modulePath: filepath.Dir(filePath) + "/",
+		tree:       NewSyntaxTree(),
+	}
+	err = parser.parseTokens(tokens)
+	if err == io.EOF { err = nil }
+	if err != nil { return }
+
+	tree = &ConcreteTree {
+		tree:     parser.tree,
+		file:     sourceFile,
+		tokens:   tokens,
+		trailing: trailing,
+		indices:  make(map[position] int),
+	}
+	for index, token := range tokens {
+		location := token.Location()
+		tree.indices[position {
+			row:    location.Row(),
+			column: location.Column(),
+		}] = index
+	}
+	return
+}
+
+// Tree returns the syntax tree of the file.
+func (tree *ConcreteTree) Tree () (syntaxTree SyntaxTree) {
+	return tree.tree
+}
+
+// Tokens returns every token in the file, in order. The Text and Trivia methods
+// of each token show exactly how it was written.
+func (tree *ConcreteTree) Tokens () (tokens []lexer.Token) {
+	return tree.tokens
+}
+
+// Source returns the code that a node was parsed from, exactly as it is written
+// in the file. Replacements do not affect it.
+func (tree *ConcreteTree) Source (node Node) (source string, err error) {
+	start, end, err := tree.span(node)
+	if err != nil { return }
+
+	source = tree.tokens[start].Text()
+	for _, token := range tree.tokens[start + 1:end + 1] {
+		source += token.Trivia() + token.Text()
+	}
+	return
+}
+
+// Replace replaces the code of a node with text, which takes effect when the
+// tree is printed with ToString. Everything outside of the node stays the
+// same, including the spaces before it. Replacing the same node again
+// overrides the previous replacement, but a node cannot overlap with a
+// different node that has already been replaced. The syntax tree itself is not
+// changed.
+func (tree *ConcreteTree) Replace (node Node, text string) (err error) {
+	start, end, err := tree.span(node)
+	if err != nil { return }
+
+	// find the first replacement that is not entirely before this one
+	index := sort.Search(len(tree.replacements), func (other int) bool {
+		return tree.replacements[other].end >= start
+	})
+
+	if index < len(tree.replacements) {
+		existing := &tree.replacements[index]
+		if existing.start == start && existing.end == end {
+			existing.text = text
+			return
+		}
+		if existing.start <= end {
+			err = infoerr.NewError (
+				tree.tokens[start].Location(),
+				"cannot replace code that overlaps with code " +
+				"that has already been replaced",
+				infoerr.ErrorKindError)
+			return
+		}
+	}
+
+	tree.replacements = append(tree.replacements, replacement { })
+	copy(tree.replacements[index + 1:], tree.replacements[index:])
+	tree.replacements[index] = replacement {
+		start: start,
+		end:   end,
+		text:  text,
+	}
+	return
+}
+
+// ToString prints the file with every replacement applied. If nothing has been
+// replaced, the output is identical to the file.
+func (tree *ConcreteTree) ToString () (output string) {
+	builder      := strings.Builder { }
+	replacements := tree.replacements
+
+	for index := 0; index < len(tree.tokens); index ++ {
+		token := tree.tokens[index]
+		builder.WriteString(token.Trivia())
+
+		if len(replacements) > 0 && replacements[0].start == index {
+			builder.WriteString(replacements[0].text)
+			index = replacements[0].end
+			replacements = replacements[1:]
+			continue
+		}
+		builder.WriteString(token.Text())
+	}
+
+	builder.WriteString(tree.trailing)
+	return builder.String()
+}
+
+// span finds the indices of the first and last tokens of a node. Blocks span
+// from the start of their first phrase to the end of their last phrase.
+func (tree *ConcreteTree) span (node Node) (start, end int, err error) {
+	var startLocation file.Location
+	var endLocation   file.Location
+
+	switch node.(type) {
+	case Block:
+		block := node.(Block)
+		if len(block) == 0 {
+			err = fmt.Errorf("an empty block has no span")
+			return
+		}
+		startLocation = block[0].Location()
+		endLocation   = block[len(block) - 1].End()
+
+	case spanned:
+		startLocation = node.(spanned).Location()
+		endLocation   = node.(spanned).End()
+
+	default:
+		err = fmt.Errorf("cannot find the span of %T", node)
+		return
+	}
+
+	start, startExists := tree.indices[position {
+		row:    startLocation.Row(),
+		column: startLocation.Column(),
+	}]
+	end, endExists := tree.indices[position {
+		row:    endLocation.Row(),
+		column: endLocation.Column(),
+	}]
+
+	parsedHere :=
+		startLocation.File() == tree.file &&
+		endLocation.File()   == tree.file &&
+		startExists && endExists && start <= end
+	if !parsedHere {
+		err = fmt.Errorf (
+			"%T was not parsed from %s", node,
+			tree.file.Path())
+	}
+	return
+}
@@ -0,0 +1,213 @@
+package parser
+
+import "os"
+import "io/fs"
+import "testing"
+import "path/filepath"
+
+func concreteTree (test *testing.T) (tree *ConcreteTree, source string) {
+	cwd, _ := os.Getwd()
+	filePath := filepath.Join(cwd, "../tests/parser/concrete/main.arf")
+	tree, err := ParseConcrete(filePath)
+	if err != nil {
+		test.Log("returned error:", err)
+		test.FailNow()
+	}
+	contents, _ := os.ReadFile(filePath)
+	source = string(contents)
+	return
+}
+
+// skipConcrete returns whether a file in tests/parser cannot be parsed on its
+// own. The skim module only parses when skimming, and the full module still
+// uses old syntax.
+func skipConcrete (filePath string) (skip bool) {
+	module := filepath.Base(filepath.Dir(filePath))
+	skip = module == "skim" || module == "full"
+	return
+}
+
+// checkString checks that output is what it should be.
+func checkString (output string, correct string, test *testing.T) {
+	if output != correct {
+		test.Log("CORRECT:\n" + correct)
+		test.Log("RESULT:\n"  + output)
+		test.Fail()
+	}
+}
+
+func TestConcreteUnchanged (test *testing.T) {
+	cwd, _ := os.Getwd()
+	filepath.WalkDir (
+		filepath.Join(cwd, "../tests/parser"),
+		func (path string, entry fs.DirEntry, err error) error {
+			if err != nil || filepath.Ext(path) != ".arf" {
+				return err
+			}
+			if skipConcrete(path) { return nil }
+
+			test.Log("printing", path)
+			tree, err := ParseConcrete(path)
+			if err != nil {
+				test.Log("returned error:", err)
+				test.Fail()
+				return nil
+			}
+			contents, _ := os.ReadFile(path)
+			checkString(tree.ToString(), string(contents), test)
+			return nil
+		})
+}
+
+func TestConcreteSource (test *testing.T) {
+	tree, _  := concreteTree(test)
+	sections := tree.Tree().sections
+	data     := sections["aData"].(DataSection)
+	member   := sections["bType"].(TypeSection).members[0]
+	function := sections["cFunc"].(FuncSection)
+	ifPhrase := function.root[0]
+
+	checks := []struct {
+		node    Node
+		correct string
+	} {
+		{
+			data,
+			"data ro aData:{Int ..}\n" +
+			"\t# first and second\n\t(1   2)",
+		},
+		{ data.what, "{Int ..}" },
+		{ data.argument, "(1   2)" },
+		{ member, "rw member:Int:8   {aData 1}" },
+		{ member.argument.value, "{aData 1}" },
+		{ function.outputs[0], "< output:Int 3" },
+		{ ifPhrase.arguments[0], "[<  input 2]" },
+		{ ifPhrase.block[0], "= x:Int [cast input Int]" },
+		{ ifPhrase.block[0].arguments[0].value, "x:Int" },
+		{ function.root[1].returnees[0], "output" },
+		{
+			function.root,
+			"if [<  input 2]\n" +
+			"\t\t= x:Int [cast input Int]   # cast it\n" +
+			"\tfn input -> output",
+		},
+	}
+	for _, check := range checks {
+		source, err := tree.Source(check.node)
+		if err != nil {
+			test.Log("returned error:", err)
+			test.Fail()
+			continue
+		}
+		checkString(source, check.correct, test)
+	}
+}
+
+func TestConcreteSpans (test *testing.T) {
+	cwd, _ := os.Getwd()
+	pattern := filepath.Join(cwd, "../tests/parser/*/*.arf")
+	filePaths, _ := filepath.Glob(pattern)
+	for _, filePath := range filePaths {
+		if skipConcrete(filePath) { continue }
+		tree, err := ParseConcrete(filePath)
+		if err != nil {
+			test.Log("returned error:", err)
+			test.Fail()
+			continue
+		}
+
+		// every node that was parsed should have a span, and
+		// identifiers should be written the same way that they print
+		Inspect(tree.Tree(), func (node Node) (proceed bool) {
+			switch node.(type) {
+			case nil, SyntaxTree: return true
+			case Block:
+				if len(node.(Block)) == 0 { return true }
+			}
+
+			source, err := tree.Source(node)
+			if err != nil {
+				test.Log(filePath, "returned error:", err)
+				test.Fail()
+				return true
+			}
+
+			identifier, isIdentifier := node.(Identifier)
+			if !isIdentifier { return true }
+			correct := identifier.ToString()
+			if source != correct {
+				test.Logf (
+					"%s: source %q does not match %q",
+					filePath, source, correct)
+				test.Fail()
+			}
+			return true
+		})
+	}
+}
+
+func TestConcreteReplace (test *testing.T) {
+	tree, source := concreteTree(test)
+	sections := tree.Tree().sections
+	data     := sections["aData"].(DataSection)
+	member   := sections["bType"].(TypeSection).members[0]
+	ifPhrase := sections["cFunc"].(FuncSection).root[0]
+
+	replace := func (node Node, text string) {
+		err := tree.Replace(node, text)
+		if err != nil {
+			test.Log("returned error:", err)
+			test.Fail()
+		}
+	}
+	replace(data.argument, "(3 4 5)")
+	replace(member.what, "U8:mut")
+	replace(ifPhrase.arguments[0], "[> input 5]")
+	replace(ifPhrase.arguments[0], "[> input 6]")
+
+	checkString(tree.ToString(), `:arf
+author  'Someone'   # spacing is kept
+---
+
+# aData holds some numbers
+data ro aData:{Int ..}
+	# first and second
+	(3 4 5)
+
+type ro bType:Obj
+	rw member:U8:mut   {aData 1}
+
+func ro cFunc
+	> input:Int
+	< output:Int 3
+	---
+	# check the input
+	if [> input 6]
+		= x:Int [cast input Int]   # cast it
+	fn input -> output
+`, test)
+
+	// the source of a node is not affected by replacements
+	original, _ := tree.Source(data)
+	checkString (
+		original,
+		"data ro aData:{Int ..}\n\t# first and second\n\t(1   2)",
+		test)
+
+	// nodes that overlap with a replacement cannot be replaced
+	err := tree.Replace(ifPhrase, "fn")
+	if err == nil {
+		test.Log("replacing an overlapping node did not return error")
+		test.Fail()
+	}
+
+	// nodes that were not parsed from the file cannot be replaced
+	err = tree.Replace(NewIdentifier("something"), "fn")
+	if err == nil {
+		test.Log("replacing a built node did not return error")
+		test.Fail()
+	}
+
+	unchanged, _ := concreteTree(test)
+	checkString(unchanged.ToString(), source, test)
+}
@@ -12,6 +12,7 @@ func (parser *parsingOperation) parseDataSection () (
 	if err != nil { return }
 	
 	section.location = parser.token.Location()
+	section.doc      = parser.documentation()
 
 	err = parser.nextToken(lexer.TokenKindPermission)
 	if err != nil { return }
@@ -37,7 +38,8 @@ func (parser *parsingOperation) parseDataSection () (
 
 	// see if value exists
 	if parser.token.Is(lexer.TokenKindNewline) {
-		parser.nextToken()
+		err = parser.nextToken()
+		if err != nil { return }
 		// if we have exited the section, return
 		if !parser.token.Is(lexer.TokenKindIndent) { return }
 		if parser.token.Value().(int) != 1         { return }
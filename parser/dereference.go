@@ -21,11 +21,13 @@ func (parser *parsingOperation) parseDereference () (
 	if err != nil { return }
 	if parser.token.Is(lexer.TokenKindUInt) {
 		dereference.offset = parser.token.Value().(uint64)
+		err = parser.nextToken(lexer.TokenKindRBrace)
+		if err != nil { return }
 	}
 	
-	err = parser.nextToken(lexer.TokenKindRBrace)
-	if err != nil { return }
 	err = parser.nextToken()
 	if err != nil { return }
+
+	dereference.end = parser.endLocation()
 	return
 }
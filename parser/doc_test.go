@@ -0,0 +1,58 @@
+package parser
+
+import "os"
+import "testing"
+import "path/filepath"
+
+func TestDoc (test *testing.T) {
+	checkTree ("../tests/parser/doc", false,
+`:arf
+---
+# aCounter counts things.
+#
+# it starts at zero.
+data ro aCounter:Int
+	0
+type ro bPoint:Obj
+	# the horizontal position
+	ro x:Int
+	ro y:Int
+# cColor is a color.
+enum ro cColor:U8
+	# red is the first color.
+	- red
+	- green
+# dDrawer draws things.
+face ro dDrawer:Face
+	# draw draws a thing.
+	draw
+		> thing:Int
+# eDraw draws a point.
+func ro eDraw
+	> point:bPoint
+	---
+	[something]
+`, test)
+}
+
+func TestDocAccessor (test *testing.T) {
+	cwd, _ := os.Getwd()
+	tree, err := Fetch(filepath.Join(cwd, "../tests/parser/doc"), false)
+	if err != nil { test.Fatal(err) }
+
+	correct := "aCounter counts things.\n\nit starts at zero."
+	doc := tree.LookupSection("", "aCounter").Doc()
+	if doc != correct {
+		test.Errorf("doc of aCounter:\ncorrect: %q\nresult:  %q",
+			correct, doc)
+	}
+
+	point := tree.LookupSection("", "bPoint").(TypeSection)
+	if point.Doc() != "" {
+		test.Errorf("bPoint should not have a doc, but has %q",
+			point.Doc())
+	}
+	if point.Member(0).Doc() != "the horizontal position" {
+		test.Errorf("wrong doc for member x: %q", point.Member(0).Doc())
+	}
+}
@@ -13,6 +13,7 @@ func (parser *parsingOperation) parseEnumSection () (
 	if err != nil { return }
 	
 	section.location = parser.token.Location()
+	section.doc      = parser.documentation()
 
 	// get permission
 	err = parser.nextToken(lexer.TokenKindPermission)
@@ -64,6 +65,7 @@ func (parser *parsingOperation) parseEnumMembers (
 
 		var member EnumMember
 		member, err = parser.parseEnumMember()
+		member.end   = parser.endLocation()
 		into.members = append(into.members, member)
 		if err != nil { return }
 	}
@@ -81,7 +83,8 @@ func (parser *parsingOperation) parseEnumMember () (
 	err = parser.nextToken(lexer.TokenKindName)
 	if err != nil { return }
 	member.location = parser.token.Location()
-	member.name = parser.token.Value().(string)
+	member.doc      = parser.documentation()
+	member.name     = parser.token.Value().(string)
 
 	// see if value exists
 	err = parser.nextToken()
@@ -13,6 +13,7 @@ func (parser *parsingOperation) parseFaceSection () (
 	if err != nil { return }
 	
 	section.location = parser.token.Location()
+	section.doc      = parser.documentation()
 
 	// get permission
 	err = parser.nextToken(lexer.TokenKindPermission)
@@ -54,9 +55,11 @@ func (parser *parsingOperation) parseFaceSection () (
 	} else {
 		// parse function interface
 		section.kind = FaceKindFunc
+		section.FaceBehavior.location = section.location
 		parser.previousToken()
 		section.inputs,
 		section.outputs, err = parser.parseFaceBehaviorArguments(1)
+		section.FaceBehavior.end = parser.endLocation()
 		if err != nil { return }
 	}
 
@@ -83,6 +86,7 @@ func (parser *parsingOperation) parseFaceBehaviors () (
 		// parse behavior
 		var behavior FaceBehavior
 		behavior, err = parser.parseFaceBehavior(1)
+		behavior.end  = parser.endLocation()
 
 		// add to section
 		_, exists := behaviors[behavior.name]
@@ -110,7 +114,9 @@ func (parser *parsingOperation) parseFaceBehavior (
 	// get name
 	err = parser.expect(lexer.TokenKindName)
 	if err != nil { return }
-	behavior.name = parser.token.Value().(string)
+	behavior.location = parser.token.Location()
+	behavior.doc      = parser.documentation()
+	behavior.name     = parser.token.Value().(string)
 
 	err = parser.nextToken(lexer.TokenKindNewline)
 	if err != nil { return }
@@ -146,6 +152,7 @@ func (parser *parsingOperation) parseFaceBehaviorArguments (
 		kind := parser.token.Kind()
 
 		var declaration Declaration
+		declaration.location = parser.token.Location()
 
 		// get name
 		err = parser.nextToken(lexer.TokenKindName)
@@ -159,6 +166,7 @@ func (parser *parsingOperation) parseFaceBehaviorArguments (
 		if err != nil { return }
 		declaration.what, err = parser.parseType()
 		if err != nil { return }
+		declaration.end = parser.endLocation()
 
 		if kind == lexer.TokenKindGreaterThan {
 			inputs = append(inputs, declaration)
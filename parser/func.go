@@ -13,6 +13,7 @@ func (parser *parsingOperation) parseFuncSection () (
 	if err != nil { return }
 	
 	section.location = parser.token.Location()
+	section.doc      = parser.documentation()
 
 	// get permission
 	err = parser.nextToken(lexer.TokenKindPermission)
@@ -130,6 +131,7 @@ func (parser *parsingOperation) parseFuncArguments (
 			if err != nil { return }
 			reciever.what, err = parser.parseType()
 			if err != nil { return }
+			reciever.end = parser.endLocation()
 			
 			if into.receiver != nil {
 				err = startToken.NewError (
@@ -162,6 +164,7 @@ func (parser *parsingOperation) parseFuncArguments (
 			if err != nil { return }
 			input.what, err = parser.parseType()
 			if err != nil { return }
+			input.end = parser.endLocation()
 
 			into.inputs = append(into.inputs, input)
 			
@@ -186,6 +189,7 @@ func (parser *parsingOperation) parseFuncArguments (
 			if err != nil { return }
 			output.what, err = parser.parseType()
 			if err != nil { return }
+			output.end = parser.endLocation()
 
 			// skip newline if it is there
 			if parser.token.Is(lexer.TokenKindNewline) {
@@ -206,6 +210,7 @@ func (parser *parsingOperation) parseFuncArguments (
 
 			// get default value
 			output.argument, err = parser.parseArgument()
+			output.end   = parser.endLocation()
 			into.outputs = append(into.outputs, output)
 			if err != nil { return }
 			
@@ -0,0 +1,901 @@
+package parser
+
+import "fmt"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/types"
+
+// JSONVersion is the version of the JSON schema that syntax trees are encoded
+// with. It is increased whenever the schema changes in a way that would break
+// existing decoders, and trees encoded with a different version cannot be
+// decoded.
+const JSONVersion = 1
+
+// The JSON schema mirrors the structure of the tree. Every node that has a
+// location has a "location" field, which is left out for nodes that were not
+// parsed from a file. Nodes that are one of several kinds have a "kind" field,
+// and fields that do not apply to a kind are left out. 64 bit integers are
+// encoded as strings, so that they survive being read by programs that store
+// all numbers as floats.
+
+type jsonLocation struct {
+	File   string `json:"file"`
+	Row    int    `json:"row"`
+	Column int    `json:"column"`
+	Width  int    `json:"width"`
+}
+
+type jsonTree struct {
+	Version  int           `json:"version"`
+	Author   string        `json:"author,omitempty"`
+	License  string        `json:"license,omitempty"`
+	Requires []jsonRequire `json:"requires"`
+	Sections []jsonSection `json:"sections"`
+}
+
+type jsonRequire struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Location *jsonLocation `json:"location,omitempty"`
+}
+
+type jsonSection struct {
+	Kind       string        `json:"kind"`
+	Location   *jsonLocation `json:"location,omitempty"`
+	Name       string        `json:"name"`
+	Permission string        `json:"permission"`
+	Doc        string        `json:"doc,omitempty"`
+
+	// data, type, and enum sections
+	Type     *jsonType     `json:"type,omitempty"`
+	Value    *jsonArgument `json:"value,omitempty"`
+	External bool          `json:"external,omitempty"`
+	Members  []jsonMember  `json:"members,omitempty"`
+
+	// face sections
+	Inherits  *jsonIdentifier `json:"inherits,omitempty"`
+	Face      string          `json:"face,omitempty"`
+	Behaviors []jsonBehavior  `json:"behaviors,omitempty"`
+
+	// face and func sections
+	Receiver *jsonDeclaration  `json:"receiver,omitempty"`
+	Inputs   []jsonDeclaration `json:"inputs,omitempty"`
+	Outputs  []jsonDeclaration `json:"outputs,omitempty"`
+	Root     []jsonPhrase      `json:"root,omitempty"`
+}
+
+type jsonMember struct {
+	Location   *jsonLocation `json:"location,omitempty"`
+	Name       string        `json:"name"`
+	Permission string        `json:"permission,omitempty"`
+	Doc        string        `json:"doc,omitempty"`
+	Type       *jsonType     `json:"type,omitempty"`
+	Value      *jsonArgument `json:"value,omitempty"`
+	BitWidth   uint64        `json:"bitWidth,omitempty,string"`
+}
+
+type jsonBehavior struct {
+	Location *jsonLocation     `json:"location,omitempty"`
+	Name     string            `json:"name"`
+	Doc      string            `json:"doc,omitempty"`
+	Inputs   []jsonDeclaration `json:"inputs,omitempty"`
+	Outputs  []jsonDeclaration `json:"outputs,omitempty"`
+}
+
+type jsonDeclaration struct {
+	Location *jsonLocation `json:"location,omitempty"`
+	Name     string        `json:"name"`
+	Type     *jsonType     `json:"type,omitempty"`
+
+	// only applicable for function outputs
+	Value *jsonArgument `json:"value,omitempty"`
+}
+
+type jsonIdentifier struct {
+	Location *jsonLocation `json:"location,omitempty"`
+	Trail    []string      `json:"trail"`
+}
+
+type jsonType struct {
+	Location *jsonLocation   `json:"location,omitempty"`
+	Kind     string          `json:"kind"`
+	Mutable  bool            `json:"mutable,omitempty"`
+	Length   uint64          `json:"length,omitempty,string"`
+	Name     *jsonIdentifier `json:"name,omitempty"`
+	Points   *jsonType       `json:"points,omitempty"`
+}
+
+type jsonArgument struct {
+	Kind        string           `json:"kind"`
+	Location    *jsonLocation    `json:"location,omitempty"`
+	Phrase      *jsonPhrase      `json:"phrase,omitempty"`
+	List        *jsonList        `json:"list,omitempty"`
+	Dereference *jsonDereference `json:"dereference,omitempty"`
+	Identifier  *jsonIdentifier  `json:"identifier,omitempty"`
+	Declaration *jsonDeclaration `json:"declaration,omitempty"`
+	Int         int64            `json:"int,omitempty,string"`
+	UInt        uint64           `json:"uint,omitempty,string"`
+	Float       float64          `json:"float,omitempty"`
+	String      string           `json:"string,omitempty"`
+}
+
+type jsonList struct {
+	Location  *jsonLocation `json:"location,omitempty"`
+	Arguments []jsonArgument `json:"arguments"`
+}
+
+type jsonDereference struct {
+	Location *jsonLocation `json:"location,omitempty"`
+	Argument *jsonArgument `json:"argument"`
+	Offset   uint64        `json:"offset,omitempty,string"`
+}
+
+type jsonPhrase struct {
+	Location  *jsonLocation  `json:"location,omitempty"`
+	Kind      string         `json:"kind"`
+	Command   *jsonArgument  `json:"command,omitempty"`
+	Operator  string         `json:"operator,omitempty"`
+	Arguments []jsonArgument `json:"arguments,omitempty"`
+	Returnees []jsonArgument `json:"returnees,omitempty"`
+	Block     []jsonPhrase   `json:"block,omitempty"`
+}
+
+// typeKindNames, argumentKindNames, phraseKindNames, and faceKindNames hold the
+// names that each kind is encoded with.
+var typeKindNames = map[TypeKind] string {
+	TypeKindBasic:         "basic",
+	TypeKindPointer:       "pointer",
+	TypeKindVariableArray: "variableArray",
+}
+
+var argumentKindNames = map[ArgumentKind] string {
+	ArgumentKindPhrase:      "phrase",
+	ArgumentKindList:        "list",
+	ArgumentKindDereference: "dereference",
+	ArgumentKindIdentifier:  "identifier",
+	ArgumentKindDeclaration: "declaration",
+	ArgumentKindInt:         "int",
+	ArgumentKindUInt:        "uint",
+	ArgumentKindFloat:       "float",
+	ArgumentKindString:      "string",
+}
+
+var phraseKindNames = map[PhraseKind] string {
+	PhraseKindCall:      "call",
+	PhraseKindArbitrary: "arbitrary",
+	PhraseKindOperator:  "operator",
+	PhraseKindAssign:    "assign",
+	PhraseKindReference: "reference",
+	PhraseKindCast:      "cast",
+	PhraseKindDefer:     "defer",
+	PhraseKindIf:        "if",
+	PhraseKindElseIf:    "elseif",
+	PhraseKindElse:      "else",
+	PhraseKindSwitch:    "switch",
+	PhraseKindCase:      "case",
+	PhraseKindWhile:     "while",
+	PhraseKindFor:       "for",
+}
+
+var faceKindNames = map[FaceKind] string {
+	FaceKindEmpty: "empty",
+	FaceKindType:  "type",
+	FaceKindFunc:  "func",
+}
+
+// kindFromName looks up the kind that a name was encoded from.
+func kindFromName[KIND comparable] (
+	names map[KIND] string,
+	name  string,
+	what  string,
+) (
+	kind KIND,
+	err  error,
+) {
+	for candidate, candidateName := range names {
+		if candidateName == name {
+			kind = candidate
+			return
+		}
+	}
+	err = fmt.Errorf("unknown %s kind %q", what, name)
+	return
+}
+
+// MarshalJSON encodes the tree as JSON. Sections and requires are sorted by
+// name, so encoding the same tree twice produces the same output.
+func (tree SyntaxTree) MarshalJSON () (data []byte, err error) {
+	encoded := jsonTree {
+		Version:  JSONVersion,
+		Author:   tree.author,
+		License:  tree.license,
+		Requires: []jsonRequire { },
+		Sections: []jsonSection { },
+	}
+
+	for _, name := range sortMapKeysAlphabetically(tree.requires) {
+		require := jsonRequire {
+			Name: name,
+			Path: tree.requires[name],
+		}
+		location, exists := tree.requireLocations[name]
+		if exists {
+			require.Location = encodeLocation(location)
+		}
+		encoded.Requires = append(encoded.Requires, require)
+	}
+
+	for _, name := range sortMapKeysAlphabetically(tree.sections) {
+		encoded.Sections = append (
+			encoded.Sections,
+			encodeSection(tree.sections[name]))
+	}
+
+	data, err = json.Marshal(encoded)
+	return
+}
+
+// encodeLocation encodes a location, or nil if it is not in a file.
+func encodeLocation (location file.Location) (encoded *jsonLocation) {
+	if location.File() == nil { return }
+	encoded = &jsonLocation {
+		File:   location.File().Path(),
+		Row:    location.Row(),
+		Column: location.Column(),
+		Width:  location.Width(),
+	}
+	return
+}
+
+// encodeSection encodes a section of any kind.
+func encodeSection (section Section) (encoded jsonSection) {
+	encoded.Location   = encodeLocation(section.Location())
+	encoded.Name       = section.Name()
+	encoded.Permission = section.Permission().ToString()
+	encoded.Doc        = section.Doc()
+
+	switch section.(type) {
+	case DataSection:
+		data := section.(DataSection)
+		encoded.Kind     = "data"
+		encoded.Type     = encodeType(data.what)
+		encoded.Value    = encodeArgument(data.argument)
+		encoded.External = data.external
+
+	case TypeSection:
+		typeSection := section.(TypeSection)
+		encoded.Kind  = "type"
+		encoded.Type  = encodeType(typeSection.what)
+		encoded.Value = encodeArgument(typeSection.argument)
+		for _, member := range typeSection.members {
+			encoded.Members = append(encoded.Members, jsonMember {
+				Location:   encodeLocation(member.location),
+				Name:       member.name,
+				Permission: member.permission.ToString(),
+				Doc:        member.doc,
+				Type:       encodeType(member.what),
+				Value:      encodeArgument(member.argument),
+				BitWidth:   member.bitWidth,
+			})
+		}
+
+	case EnumSection:
+		enum := section.(EnumSection)
+		encoded.Kind = "enum"
+		encoded.Type = encodeType(enum.what)
+		for _, member := range enum.members {
+			encoded.Members = append(encoded.Members, jsonMember {
+				Location: encodeLocation(member.location),
+				Name:     member.name,
+				Doc:      member.doc,
+				Value:    encodeArgument(member.argument),
+			})
+		}
+
+	case FaceSection:
+		face := section.(FaceSection)
+		encoded.Kind     = "face"
+		encoded.Inherits = encodeIdentifier(face.inherits)
+		encoded.Face     = faceKindNames[face.kind]
+		for _, name := range sortMapKeysAlphabetically(face.behaviors) {
+			behavior := face.behaviors[name]
+			encodedBehavior := jsonBehavior {
+				Location: encodeLocation(behavior.location),
+				Name:     behavior.name,
+				Doc:      behavior.doc,
+				Inputs:   encodeDeclarations(behavior.inputs),
+				Outputs:  encodeDeclarations(behavior.outputs),
+			}
+			encoded.Behaviors = append (
+				encoded.Behaviors,
+				encodedBehavior)
+		}
+		encoded.Inputs  = encodeDeclarations(face.inputs)
+		encoded.Outputs = encodeDeclarations(face.outputs)
+
+	case FuncSection:
+		function := section.(FuncSection)
+		encoded.Kind     = "func"
+		encoded.External = function.external
+		if function.receiver != nil {
+			receiver := encodeDeclaration(*function.receiver)
+			encoded.Receiver = &receiver
+		}
+		encoded.Inputs = encodeDeclarations(function.inputs)
+		for _, output := range function.outputs {
+			declaration := encodeDeclaration(output.Declaration)
+			declaration.Value = encodeArgument(output.argument)
+			encoded.Outputs = append(encoded.Outputs, declaration)
+		}
+		encoded.Root = encodeBlock(function.root)
+	}
+	return
+}
+
+// encodeIdentifier encodes an identifier and its location.
+func encodeIdentifier (identifier Identifier) (encoded *jsonIdentifier) {
+	encoded = &jsonIdentifier {
+		Location: encodeLocation(identifier.location),
+		Trail:    identifier.trail,
+	}
+	return
+}
+
+// encodeType encodes a type, and the types that it points to.
+func encodeType (what Type) (encoded *jsonType) {
+	if what.Nil() { return }
+	encoded = &jsonType {
+		Location: encodeLocation(what.location),
+		Kind:     typeKindNames[what.kind],
+		Mutable:  what.mutable,
+		Length:   what.length,
+	}
+	if what.kind == TypeKindBasic {
+		encoded.Name = encodeIdentifier(what.name)
+	} else if what.points != nil {
+		encoded.Points = encodeType(*what.points)
+	}
+	return
+}
+
+// encodeDeclaration encodes a variable declaration.
+func encodeDeclaration (declaration Declaration) (encoded jsonDeclaration) {
+	encoded = jsonDeclaration {
+		Location: encodeLocation(declaration.location),
+		Name:     declaration.name,
+		Type:     encodeType(declaration.what),
+	}
+	return
+}
+
+// encodeDeclarations encodes a list of variable declarations.
+func encodeDeclarations (
+	declarations []Declaration,
+) (
+	encoded []jsonDeclaration,
+) {
+	for _, declaration := range declarations {
+		encoded = append(encoded, encodeDeclaration(declaration))
+	}
+	return
+}
+
+// encodeArgument encodes an argument, or nil if it has no value.
+func encodeArgument (argument Argument) (encoded *jsonArgument) {
+	if argument.Nil() { return }
+	encoded = &jsonArgument {
+		Kind:     argumentKindNames[argument.kind],
+		Location: encodeLocation(argument.location),
+	}
+
+	switch argument.kind {
+	case ArgumentKindPhrase:
+		phrase := encodePhrase(argument.value.(Phrase))
+		encoded.Phrase = &phrase
+	case ArgumentKindList:
+		list := argument.value.(List)
+		encoded.List = &jsonList {
+			Location:  encodeLocation(list.location),
+			Arguments: encodeArguments(list.arguments),
+		}
+	case ArgumentKindDereference:
+		dereference := argument.value.(Dereference)
+		encoded.Dereference = &jsonDereference {
+			Location: encodeLocation(dereference.location),
+			Argument: encodeArgument(dereference.argument),
+			Offset:   dereference.offset,
+		}
+	case ArgumentKindIdentifier:
+		identifier := argument.value.(Identifier)
+		encoded.Identifier = encodeIdentifier(identifier)
+	case ArgumentKindDeclaration:
+		declaration := encodeDeclaration(argument.value.(Declaration))
+		encoded.Declaration = &declaration
+	case ArgumentKindInt:
+		encoded.Int = argument.value.(int64)
+	case ArgumentKindUInt:
+		encoded.UInt = argument.value.(uint64)
+	case ArgumentKindFloat:
+		encoded.Float = argument.value.(float64)
+	case ArgumentKindString:
+		encoded.String = argument.value.(string)
+	}
+	return
+}
+
+// encodeArguments encodes a list of arguments.
+func encodeArguments (arguments []Argument) (encoded []jsonArgument) {
+	encoded = []jsonArgument { }
+	for _, argument := range arguments {
+		encodedArgument := encodeArgument(argument)
+		if encodedArgument == nil { continue }
+		encoded = append(encoded, *encodedArgument)
+	}
+	return
+}
+
+// encodePhrase encodes a phrase along with the block under it.
+func encodePhrase (phrase Phrase) (encoded jsonPhrase) {
+	encoded = jsonPhrase {
+		Location:  encodeLocation(phrase.location),
+		Kind:      phraseKindNames[phrase.kind],
+		Command:   encodeArgument(phrase.command),
+		Operator:  operatorSymbols[phrase.operator],
+		Arguments: encodeArguments(phrase.arguments),
+		Returnees: encodeArguments(phrase.returnees),
+		Block:     encodeBlock(phrase.block),
+	}
+	return
+}
+
+// encodeBlock encodes each phrase in a block.
+func encodeBlock (block Block) (encoded []jsonPhrase) {
+	for _, phrase := range block {
+		encoded = append(encoded, encodePhrase(phrase))
+	}
+	return
+}
+
+// jsonDecoder holds the state of a tree that is being decoded from JSON.
+type jsonDecoder struct {
+	// files holds a file for each path that a location refers to. These
+	// files are empty, because only the locations are stored in the JSON.
+	files map[string] *file.File
+}
+
+// UnmarshalJSON decodes a tree from JSON that was produced by MarshalJSON.
+// Locations in the decoded tree refer to files that are empty, so errors
+// created on its nodes will not show the line that they are on.
+func (tree *SyntaxTree) UnmarshalJSON (data []byte) (err error) {
+	var encoded jsonTree
+	err = json.Unmarshal(data, &encoded)
+	if err != nil { return }
+	if encoded.Version != JSONVersion {
+		err = fmt.Errorf (
+			"cannot decode syntax tree with schema version %d, " +
+			"expected version %d",
+			encoded.Version, JSONVersion)
+		return
+	}
+
+	decoder := jsonDecoder { files: make(map[string] *file.File) }
+	decoded := NewSyntaxTree()
+	decoded.author  = encoded.Author
+	decoded.license = encoded.License
+
+	for _, require := range encoded.Requires {
+		decoded.requires[require.Name] = require.Path
+		if require.Location != nil {
+			decoded.requireLocations[require.Name] =
+				decoder.location(require.Location)
+		}
+	}
+
+	for _, encodedSection := range encoded.Sections {
+		var section Section
+		section, err = decoder.section(encodedSection)
+		if err != nil { return }
+		err = decoded.addSection(section)
+		if err != nil { return }
+	}
+
+	*tree = decoded
+	return
+}
+
+// location decodes a location. Nil decodes into a location with no file.
+func (decoder jsonDecoder) location (
+	encoded *jsonLocation,
+) (
+	location file.Location,
+) {
+	if encoded == nil { return }
+	sourceFile, exists := decoder.files[encoded.File]
+	if !exists {
+		sourceFile = file.Load(encoded.File, "")
+		decoder.files[encoded.File] = sourceFile
+	}
+	location = file.NewLocation (
+		sourceFile,
+		encoded.Row, encoded.Column, encoded.Width)
+	return
+}
+
+// decodePermission decodes a permission from its two letter name.
+func decodePermission (
+	encoded string,
+) (
+	permission types.Permission,
+	err        error,
+) {
+	permission, worked := types.PermissionFrom(encoded)
+	if !worked {
+		err = fmt.Errorf("unknown permission %q", encoded)
+	}
+	return
+}
+
+// section decodes a section of any kind.
+func (decoder jsonDecoder) section (
+	encoded jsonSection,
+) (
+	section Section,
+	err     error,
+) {
+	location := decoder.location(encoded.Location)
+	permission, err := decodePermission(encoded.Permission)
+	if err != nil { return }
+
+	switch encoded.Kind {
+	case "data":
+		data := NewDataSection(encoded.Name, permission, Type { })
+		data.location = location
+		data.doc      = encoded.Doc
+		data.external = encoded.External
+		data.what,     err = decoder.what(encoded.Type)
+		if err != nil { return }
+		data.argument, err = decoder.argument(encoded.Value)
+		if err != nil { return }
+		section = data
+
+	case "type":
+		typeSection := NewTypeSection (
+			encoded.Name, permission,
+			Type { })
+		typeSection.location = location
+		typeSection.doc      = encoded.Doc
+		typeSection.what,     err = decoder.what(encoded.Type)
+		if err != nil { return }
+		typeSection.argument, err = decoder.argument(encoded.Value)
+		if err != nil { return }
+		for _, encodedMember := range encoded.Members {
+			var member TypeSectionMember
+			member, err = decoder.typeMember(encodedMember)
+			if err != nil { return }
+			typeSection.members = append (
+				typeSection.members,
+				member)
+		}
+		section = typeSection
+
+	case "enum":
+		enum := NewEnumSection(encoded.Name, permission, Type { })
+		enum.location = location
+		enum.doc      = encoded.Doc
+		enum.what, err = decoder.what(encoded.Type)
+		if err != nil { return }
+		for _, encodedMember := range encoded.Members {
+			member := NewEnumMember(encodedMember.Name)
+			member.location =
+				decoder.location(encodedMember.Location)
+			member.doc = encodedMember.Doc
+			member.argument, err =
+				decoder.argument(encodedMember.Value)
+			if err != nil { return }
+			enum.members = append(enum.members, member)
+		}
+		section = enum
+
+	case "face":
+		section, err = decoder.face(encoded, permission)
+
+	case "func":
+		section, err = decoder.function(encoded, permission)
+
+	default:
+		err = fmt.Errorf("unknown section kind %q", encoded.Kind)
+	}
+	return
+}
+
+// typeMember decodes a member of a type section.
+func (decoder jsonDecoder) typeMember (
+	encoded jsonMember,
+) (
+	member TypeSectionMember,
+	err    error,
+) {
+	permission, err := decodePermission(encoded.Permission)
+	if err != nil { return }
+	member = NewTypeSectionMember(encoded.Name, permission, Type { })
+	member.location = decoder.location(encoded.Location)
+	member.doc      = encoded.Doc
+	member.bitWidth = encoded.BitWidth
+	member.what,     err = decoder.what(encoded.Type)
+	if err != nil { return }
+	member.argument, err = decoder.argument(encoded.Value)
+	return
+}
+
+// face decodes an interface section.
+func (decoder jsonDecoder) face (
+	encoded    jsonSection,
+	permission types.Permission,
+) (
+	face FaceSection,
+	err  error,
+) {
+	inherits, err := decoder.identifier(encoded.Inherits)
+	if err != nil { return }
+	face = NewFaceSection(encoded.Name, permission, inherits)
+	face.location = decoder.location(encoded.Location)
+	face.doc      = encoded.Doc
+	face.kind, err = kindFromName(faceKindNames, encoded.Face, "face")
+	if err != nil { return }
+
+	switch face.kind {
+	case FaceKindType:
+		face.behaviors = make(map[string] FaceBehavior)
+		for _, encodedBehavior := range encoded.Behaviors {
+			behavior := NewFaceBehavior(encodedBehavior.Name)
+			behavior.location =
+				decoder.location(encodedBehavior.Location)
+			behavior.doc = encodedBehavior.Doc
+			behavior.inputs, err =
+				decoder.declarations(encodedBehavior.Inputs)
+			if err != nil { return }
+			behavior.outputs, err =
+				decoder.declarations(encodedBehavior.Outputs)
+			if err != nil { return }
+			face.behaviors[behavior.name] = behavior
+		}
+
+	case FaceKindFunc:
+		face.FaceBehavior.location = face.location
+		face.inputs, err = decoder.declarations(encoded.Inputs)
+		if err != nil { return }
+		face.outputs, err = decoder.declarations(encoded.Outputs)
+		if err != nil { return }
+	}
+	return
+}
+
+// function decodes a function section.
+func (decoder jsonDecoder) function (
+	encoded    jsonSection,
+	permission types.Permission,
+) (
+	function FuncSection,
+	err      error,
+) {
+	function = NewFuncSection(encoded.Name, permission)
+	function.location = decoder.location(encoded.Location)
+	function.doc      = encoded.Doc
+	function.external = encoded.External
+
+	if encoded.Receiver != nil {
+		var receiver Declaration
+		receiver, err = decoder.declaration(*encoded.Receiver)
+		if err != nil { return }
+		if !validReceiver(receiver) {
+			err = fmt.Errorf (
+				"receiver of method %s does not point to a " +
+				"basic type", encoded.Name)
+			return
+		}
+		function.receiver = &receiver
+	}
+
+	function.inputs, err = decoder.declarations(encoded.Inputs)
+	if err != nil { return }
+
+	for _, encodedOutput := range encoded.Outputs {
+		var output FuncOutput
+		output.Declaration, err = decoder.declaration(encodedOutput)
+		if err != nil { return }
+		output.argument, err = decoder.argument(encodedOutput.Value)
+		if err != nil { return }
+		function.outputs = append(function.outputs, output)
+	}
+
+	function.root, err = decoder.block(encoded.Root)
+	return
+}
+
+// identifier decodes an identifier and its location.
+func (decoder jsonDecoder) identifier (
+	encoded *jsonIdentifier,
+) (
+	identifier Identifier,
+	err        error,
+) {
+	if encoded == nil {
+		err = fmt.Errorf("missing identifier")
+		return
+	}
+	identifier = NewIdentifier(encoded.Trail...)
+	identifier.location = decoder.location(encoded.Location)
+	return
+}
+
+// what decodes a type, and the types that it points to.
+func (decoder jsonDecoder) what (encoded *jsonType) (what Type, err error) {
+	if encoded == nil { return }
+	what.location = decoder.location(encoded.Location)
+	what.mutable  = encoded.Mutable
+	what.length   = encoded.Length
+	what.kind, err = kindFromName(typeKindNames, encoded.Kind, "type")
+	if err != nil { return }
+
+	if what.kind == TypeKindBasic {
+		what.name, err = decoder.identifier(encoded.Name)
+		return
+	}
+
+	if encoded.Points == nil {
+		err = fmt.Errorf (
+			"%s type does not point to anything",
+			encoded.Kind)
+		return
+	}
+	var points Type
+	points, err = decoder.what(encoded.Points)
+	what.points = &points
+	return
+}
+
+// declaration decodes a variable declaration.
+func (decoder jsonDecoder) declaration (
+	encoded jsonDeclaration,
+) (
+	declaration Declaration,
+	err         error,
+) {
+	declaration.location = decoder.location(encoded.Location)
+	declaration.name     = encoded.Name
+	declaration.what, err = decoder.what(encoded.Type)
+	return
+}
+
+// declarations decodes a list of variable declarations.
+func (decoder jsonDecoder) declarations (
+	encoded []jsonDeclaration,
+) (
+	declarations []Declaration,
+	err          error,
+) {
+	for _, encodedDeclaration := range encoded {
+		var declaration Declaration
+		declaration, err = decoder.declaration(encodedDeclaration)
+		if err != nil { return }
+		declarations = append(declarations, declaration)
+	}
+	return
+}
+
+// argument decodes an argument. Nil decodes into an argument with no value.
+func (decoder jsonDecoder) argument (
+	encoded *jsonArgument,
+) (
+	argument Argument,
+	err      error,
+) {
+	if encoded == nil { return }
+	argument.location = decoder.location(encoded.Location)
+	argument.kind, err = kindFromName (
+		argumentKindNames,
+		encoded.Kind, "argument")
+	if err != nil { return }
+
+	missing := false
+	switch argument.kind {
+	case ArgumentKindPhrase:
+		if encoded.Phrase == nil { missing = true; break }
+		argument.value, err = decoder.phrase(*encoded.Phrase)
+
+	case ArgumentKindList:
+		if encoded.List == nil { missing = true; break }
+		var list List
+		list.location = decoder.location(encoded.List.Location)
+		list.arguments, err = decoder.arguments(encoded.List.Arguments)
+		argument.value = list
+
+	case ArgumentKindDereference:
+		if encoded.Dereference == nil { missing = true; break }
+		var dereference Dereference
+		dereference.location =
+			decoder.location(encoded.Dereference.Location)
+		dereference.offset = encoded.Dereference.Offset
+		dereference.argument, err =
+			decoder.argument(encoded.Dereference.Argument)
+		argument.value = dereference
+
+	case ArgumentKindIdentifier:
+		argument.value, err = decoder.identifier(encoded.Identifier)
+
+	case ArgumentKindDeclaration:
+		if encoded.Declaration == nil { missing = true; break }
+		argument.value, err = decoder.declaration(*encoded.Declaration)
+
+	case ArgumentKindInt:    argument.value = encoded.Int
+	case ArgumentKindUInt:   argument.value = encoded.UInt
+	case ArgumentKindFloat:  argument.value = encoded.Float
+	case ArgumentKindString: argument.value = encoded.String
+	}
+
+	if missing {
+		err = fmt.Errorf("%s argument has no value", encoded.Kind)
+	}
+	return
+}
+
+// arguments decodes a list of arguments.
+func (decoder jsonDecoder) arguments (
+	encoded []jsonArgument,
+) (
+	arguments []Argument,
+	err       error,
+) {
+	for index := range encoded {
+		var argument Argument
+		argument, err = decoder.argument(&encoded[index])
+		if err != nil { return }
+		arguments = append(arguments, argument)
+	}
+	return
+}
+
+// phrase decodes a phrase along with the block under it.
+func (decoder jsonDecoder) phrase (
+	encoded jsonPhrase,
+) (
+	phrase Phrase,
+	err    error,
+) {
+	phrase.location = decoder.location(encoded.Location)
+	phrase.kind, err = kindFromName(phraseKindNames, encoded.Kind, "phrase")
+	if err != nil { return }
+
+	if phrase.kind == PhraseKindOperator {
+		phrase.operator, err = kindFromName (
+			operatorSymbols,
+			encoded.Operator, "operator")
+		if err != nil { return }
+	}
+
+	phrase.command, err = decoder.argument(encoded.Command)
+	if err != nil { return }
+	phrase.arguments, err = decoder.arguments(encoded.Arguments)
+	if err != nil { return }
+	phrase.returnees, err = decoder.arguments(encoded.Returnees)
+	if err != nil { return }
+	phrase.block, err = decoder.block(encoded.Block)
+	return
+}
+
+// block decodes each phrase in a block.
+func (decoder jsonDecoder) block (
+	encoded []jsonPhrase,
+) (
+	block Block,
+	err   error,
+) {
+	for _, encodedPhrase := range encoded {
+		var phrase Phrase
+		phrase, err = decoder.phrase(encodedPhrase)
+		if err != nil { return }
+		block = append(block, phrase)
+	}
+	return
+}
+
@@ -0,0 +1,191 @@
+package parser
+
+import "os"
+import "reflect"
+import "testing"
+import "io/fs"
+import "path/filepath"
+import "encoding/json"
+import "git.tebibyte.media/arf/arf/types"
+
+// checkJSON checks that a tree decodes from JSON into an equivalent tree, and
+// that the decoded tree encodes to the same JSON, locations included.
+func checkJSON (tree SyntaxTree, test *testing.T) {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		test.Log("encoding returned error:", err)
+		test.Fail()
+		return
+	}
+
+	var decoded SyntaxTree
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		test.Log("decoding returned error:", err)
+		test.Fail()
+		return
+	}
+
+	if !equivalent(reflect.ValueOf(tree), reflect.ValueOf(decoded)) {
+		test.Log("BEFORE:\n" + tree.ToString(0))
+		test.Log("AFTER:\n"  + decoded.ToString(0))
+		test.Fail()
+		return
+	}
+
+	again, err := json.Marshal(decoded)
+	if err != nil {
+		test.Log("encoding again returned error:", err)
+		test.Fail()
+		return
+	}
+	if string(again) != string(data) {
+		test.Log("BEFORE:\n" + string(data))
+		test.Log("AFTER:\n"  + string(again))
+		test.Fail()
+	}
+}
+
+func TestJSON (test *testing.T) {
+	cwd, _ := os.Getwd()
+	filepath.WalkDir (
+		filepath.Join(cwd, "../tests/parser"),
+		func (path string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.IsDir() { return err }
+			pattern    := filepath.Join(path, "*.arf")
+			matches, _ := filepath.Glob(pattern)
+			if len(matches) == 0 { return nil }
+
+			// the full module still uses old syntax, and does not
+			// parse
+			if filepath.Base(path) == "full" { return nil }
+
+			skim := filepath.Base(path) == "skim"
+			test.Log("encoding", path)
+			tree, err := Fetch(path, skim)
+			if err != nil {
+				test.Log("returned error:")
+				test.Log(err.Error())
+				test.Fail()
+				return nil
+			}
+			checkJSON(tree, test)
+			return nil
+		})
+
+	checkJSON(buildWalkTree(test), test)
+}
+
+func TestJSONSchema (test *testing.T) {
+	tree := NewSyntaxTree()
+	tree.AddRequire("/usr/local/include/arf/io")
+	section := NewDataSection (
+		"aData", types.PermissionReadOnly,
+		NewVariableArrayType(NewBasicType(NewIdentifier("Int"))))
+	section.SetDoc("some numbers")
+	section.SetArgument(NewArgument(NewList (
+		NewArgument(int64(-1)),
+		NewArgument(uint64(18446744073709551615)))))
+	tree.AddSection(section)
+
+	data, err := json.MarshalIndent(tree, "", "\t")
+	if err != nil {
+		test.Log("encoding returned error:", err)
+		test.Fail()
+		return
+	}
+
+	correct :=
+`{
+	"version": 1,
+	"requires": [
+		{
+			"name": "io",
+			"path": "/usr/local/include/arf/io"
+		}
+	],
+	"sections": [
+		{
+			"kind": "data",
+			"name": "aData",
+			"permission": "ro",
+			"doc": "some numbers",
+			"type": {
+				"kind": "variableArray",
+				"points": {
+					"kind": "basic",
+					"name": {
+						"trail": [
+							"Int"
+						]
+					}
+				}
+			},
+			"value": {
+				"kind": "list",
+				"list": {
+					"arguments": [
+						{
+							"kind": "int",
+							"int": "-1"
+						},
+						{
+							"kind": "uint",
+							"uint": "18446744073709551615"
+						}
+					]
+				}
+			}
+		}
+	]
+}`
+	if string(data) != correct {
+		test.Log("CORRECT:\n" + correct)
+		test.Log("RESULT:\n"  + string(data))
+		test.Fail()
+	}
+}
+
+func TestJSONVersion (test *testing.T) {
+	var tree SyntaxTree
+	err := json.Unmarshal([]byte(`{ "version": 2 }`), &tree)
+	correct :=
+		"cannot decode syntax tree with schema version 2, " +
+		"expected version 1"
+	if err == nil || err.Error() != correct {
+		test.Log("CORRECT:", correct)
+		test.Log("RESULT: ", err)
+		test.Fail()
+	}
+}
+
+func TestJSONReceiver (test *testing.T) {
+	check := func (receiver string) {
+		var tree SyntaxTree
+		err := json.Unmarshal([]byte(`{
+			"version": 1,
+			"sections": [ {
+				"kind": "func",
+				"name": "aMethod",
+				"permission": "ro",
+				"receiver": ` + receiver + `
+			} ]
+		}`), &tree)
+		correct :=
+			"receiver of method aMethod does not point to a " +
+			"basic type"
+		if err == nil || err.Error() != correct {
+			test.Log("RECEIVER:", receiver)
+			test.Log("CORRECT: ", correct)
+			test.Log("RESULT:  ", err)
+			test.Fail()
+		}
+	}
+
+	check(`{ "name": "r" }`)
+	check(`{ "name": "r", "type": {
+		"kind": "basic", "name": { "trail": [ "Int" ] } } }`)
+	check(`{ "name": "r", "type": {
+		"kind": "pointer", "points": {
+			"kind": "basic", "name": { "trail": [ ] } } } }`)
+}
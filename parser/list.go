@@ -27,6 +27,7 @@ func (parser *parsingOperation) parseList () (list List, err error) {
 	
 	err = parser.nextToken()
 	if err != nil { return }
-	
+
+	list.end = parser.endLocation()
 	return
 }
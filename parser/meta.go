@@ -4,6 +4,10 @@ import "path/filepath"
 import "git.tebibyte.media/arf/arf/lexer"
 import "git.tebibyte.media/arf/arf/infoerr"
 
+// IncludePath is the directory that modules are required from when they are
+// not referred to with a path.
+const IncludePath = "/usr/local/include/arf/"
+
 // parseMeta parsese the metadata header at the top of an arf file.
 func (parser *parsingOperation) parseMeta () (err error) {
 	for {
@@ -33,15 +37,16 @@ func (parser *parsingOperation) parseMeta () (err error) {
 			parser.tree.license = value
 		case "require":
 			// if import path is relative, get absolute path.
+			hostPath, hosted := hosts[value]
 			if value[0] == '.' {
 				value = filepath.Join(parser.modulePath, value)
+			} else if hosted {
+				value = hostPath
 			} else if value[0] != '/' {
 				// TODO: get arf import path from an env
 				// variable, and default to this if not found.
 				// then, search all paths.
-				value = filepath.Join (
-					"/usr/local/include/arf/",
-					value)
+				value = filepath.Join(IncludePath, value)
 			}
 
 			basename  := filepath.Base(value)
@@ -56,6 +61,8 @@ func (parser *parsingOperation) parseMeta () (err error) {
 			}
 
 			parser.tree.requires[basename] = value
+			parser.tree.requireLocations[basename] =
+				parser.token.Location()
 		default:
 			parser.token.NewError (
 				"unrecognized metadata field: " + field,
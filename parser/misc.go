@@ -39,5 +39,6 @@ func (parser *parsingOperation) parseIdentifier () (
 		}
 	}
 
+	identifier.end = parser.endLocation()
 	return
 }
@@ -1,5 +1,6 @@
 package parser
 
+import "errors"
 import "git.tebibyte.media/arf/arf/file"
 import "git.tebibyte.media/arf/arf/types"
 import "git.tebibyte.media/arf/arf/infoerr"
@@ -7,6 +8,7 @@ import "git.tebibyte.media/arf/arf/infoerr"
 // locatable allows a tree node to have a location.
 type locatable struct {
 	location file.Location
+	end      file.Location
 }
 
 // Location returns the location of the node.
@@ -15,13 +17,26 @@ func (node locatable) Location () (location file.Location) {
 	return
 }
 
-// NewError creates a new error at the node's location.
+// End returns the location of the last token of the node. Together with
+// Location, this gives the span of source code that the node was parsed from.
+// Nodes that were not parsed have no end.
+func (node locatable) End () (end file.Location) {
+	end = node.end
+	return
+}
+
+// NewError creates a new error at the node's location. Nodes that were built
+// rather than parsed have no location, so a plain error is created instead.
 func (node locatable) NewError (
 	message string,
 	kind    infoerr.ErrorKind,
 ) (
 	err error,
 ) {
+	if node.location.File() == nil {
+		err = errors.New(message)
+		return
+	}
 	err = infoerr.NewError(node.location, message, kind)
 	return
 }
@@ -69,6 +84,11 @@ func (node valuable) Argument () (argument Argument) {
 	return
 }
 
+// SetArgument sets the value argument of the node.
+func (node *valuable) SetArgument (argument Argument) {
+	node.argument = argument
+}
+
 // multiValuable allows a node to have several argument values.
 type multiValuable struct {
 	arguments []Argument
@@ -85,3 +105,22 @@ func (node multiValuable) Length () (length int) {
 	length = len(node.arguments)
 	return
 }
+
+// documentable allows a node to have a doc comment.
+type documentable struct {
+	doc string
+}
+
+// Doc returns the comment written on the lines directly above the node, with
+// the # at the start of each line removed. If there is no such comment, it
+// returns an empty string.
+func (node documentable) Doc () (doc string) {
+	doc = node.doc
+	return
+}
+
+// SetDoc sets the doc comment of the node. It should not include the # at the
+// start of each line.
+func (node *documentable) SetDoc (doc string) {
+	node.doc = doc
+}
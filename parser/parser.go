@@ -14,6 +14,7 @@ package parser
 
 import "io"
 import "os"
+import "sort"
 import "path/filepath"
 import "git.tebibyte.media/arf/arf/file"
 import "git.tebibyte.media/arf/arf/lexer"
@@ -27,6 +28,10 @@ type parsingOperation struct {
 	tokenIndex int
 	skimming   bool
 
+	// docs contains the doc comment above each row of the file currently
+	// being parsed.
+	docs map[int] string
+
 	tree SyntaxTree
 }
 
@@ -50,8 +55,9 @@ func Fetch (modulePath string, skim bool) (tree SyntaxTree, err error) {
 		modulePath: modulePath,
 		skimming:   skim,
 		tree: SyntaxTree {
-			requires: make(map[string] string),
-			sections: make(map[string] Section),			
+			requires:         make(map[string] string),
+			requireLocations: make(map[string] file.Location),
+			sections:         make(map[string] Section),			
 		},
 	}
 
@@ -59,18 +65,20 @@ func Fetch (modulePath string, skim bool) (tree SyntaxTree, err error) {
 		parser.modulePath += "/"
 	}
 
-	var moduleFiles []os.DirEntry
-	moduleFiles, err = os.ReadDir(parser.modulePath)
+	var filePaths []string
+	filePaths, err = moduleFiles(parser.modulePath)
 	if err != nil { return }
 
-	for _, entry := range moduleFiles {
-		if filepath.Ext(entry.Name()) != ".arf" || entry.IsDir() {
-			continue
-		}
-
+	for _, filePath := range filePaths {
+		// files that are being edited are read from memory
 		var sourceFile *file.File
-		sourceFile, err = file.Open(parser.modulePath + entry.Name())
-		if err != nil { return }
+		contents, overlaid := overlays[filePath]
+		if overlaid {
+			sourceFile = file.Load(filePath, contents)
+		} else {
+			sourceFile, err = file.Open(filePath)
+			if err != nil { return }
+		}
 
  		// parse the tokens into the module
 		err  = parser.parse(sourceFile)
@@ -83,17 +91,53 @@ func Fetch (modulePath string, skim bool) (tree SyntaxTree, err error) {
 	// cache tree
 	cache[modulePath] = cacheItem {
 		tree:    tree,
-		skimmed: false,
+		skimmed: skim,
 	}
 	
 	return
 }
 
+// moduleFiles returns the paths of the source files in a module, sorted by
+// name. Overlays are included even if they do not exist on the filesystem, so a
+// module can be made up only of overlays.
+func moduleFiles (modulePath string) (filePaths []string, err error) {
+	entries, err := os.ReadDir(modulePath)
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".arf" || entry.IsDir() {
+			continue
+		}
+		filePaths = append(filePaths, modulePath + entry.Name())
+	}
+
+	overlaid := false
+	for filePath := range overlays {
+		if filepath.Dir(filePath) + "/" != modulePath { continue }
+		overlaid = true
+		if _, statErr := os.Stat(filePath); statErr == nil { continue }
+		filePaths = append(filePaths, filePath)
+	}
+
+	if err != nil && overlaid { err = nil }
+	sort.Strings(filePaths)
+	return
+}
+
 // parse parses a file and adds it to the syntax tree.
 func (parser *parsingOperation) parse (sourceFile *file.File) (err error) {
 	var tokens []lexer.Token
 	tokens, err = lexer.Tokenize(sourceFile)
 	if err != nil { return }
+	err = parser.parseTokens(tokens)
+	return
+}
+
+// parseTokens parses the tokens of a file and adds them to the syntax tree.
+func (parser *parsingOperation) parseTokens (
+	tokens []lexer.Token,
+) (
+	err error,
+) {
+	tokens = parser.takeComments(tokens)
 
 	// reset the parser
 	if len(tokens) == 0 { return }
@@ -161,6 +205,26 @@ func (parser *parsingOperation) previousToken () {
 	return
 }
 
+// endLocation returns the location of the last token that the parser has moved
+// past, ignoring newlines and indents. Parsing methods leave the parser just
+// after the node they have parsed, so this is where that node ends.
+func (parser *parsingOperation) endLocation () (end file.Location) {
+	index := parser.tokenIndex - 1
+	if index >= len(parser.tokens) { index = len(parser.tokens) - 1 }
+	for index > 0 {
+		token := parser.tokens[index]
+		isWhitespace :=
+			token.Is(lexer.TokenKindIndent) ||
+			token.Is(lexer.TokenKindNewline)
+		if !isWhitespace { break }
+		index --
+	}
+	if index < 0 { return }
+
+	end = parser.tokens[index].Location()
+	return
+}
+
 // skipIndentLevel advances the parser, ignoring every line with an indentation
 // equal to or greater than the specified indent.
 func (parser *parsingOperation) skipIndentLevel (indent int) (err error) {
@@ -38,6 +38,41 @@ var operatorTokens = []lexer.TokenKind {
         lexer.TokenKindBinaryXorAssignment,
 }
 
+// operatorSymbols maps each operator token to how it is written.
+var operatorSymbols = map[lexer.TokenKind] string {
+	lexer.TokenKindColon:               ":",
+	lexer.TokenKindPlus:                "+",
+	lexer.TokenKindMinus:               "-",
+	lexer.TokenKindIncrement:           "++",
+	lexer.TokenKindDecrement:           "--",
+	lexer.TokenKindAsterisk:            "*",
+	lexer.TokenKindSlash:               "/",
+	lexer.TokenKindExclamation:         "!",
+	lexer.TokenKindPercent:             "%",
+	lexer.TokenKindPercentAssignment:   "%=",
+	lexer.TokenKindTilde:               "~",
+	lexer.TokenKindTildeAssignment:     "~=",
+	lexer.TokenKindAssignment:          "=",
+	lexer.TokenKindEqualTo:             "==",
+	lexer.TokenKindNotEqualTo:          "!=",
+	lexer.TokenKindLessThanEqualTo:     "<=",
+	lexer.TokenKindLessThan:            "<",
+	lexer.TokenKindLShift:              "<<",
+	lexer.TokenKindLShiftAssignment:    "<<=",
+	lexer.TokenKindGreaterThan:         ">",
+	lexer.TokenKindGreaterThanEqualTo:  ">=",
+	lexer.TokenKindRShift:              ">>",
+	lexer.TokenKindRShiftAssignment:    ">>=",
+	lexer.TokenKindBinaryOr:            "|",
+	lexer.TokenKindBinaryOrAssignment:  "|=",
+	lexer.TokenKindLogicalOr:           "||",
+	lexer.TokenKindBinaryAnd:           "&",
+	lexer.TokenKindBinaryAndAssignment: "&=",
+	lexer.TokenKindLogicalAnd:          "&&",
+	lexer.TokenKindBinaryXor:           "^",
+	lexer.TokenKindBinaryXorAssignment: "^=",
+}
+
 // isTokenOperator returns whether or not the token is an operator token.
 func isTokenOperator (token lexer.Token) (isOperator bool) {
 	for _, kind := range operatorTokens {
@@ -100,6 +135,7 @@ func (parser *parsingOperation) parseBlock (
 
 		var phrase Phrase
 		phrase, err = parser.parseBlockLevelPhrase(indent)
+		phrase.end = parser.endLocation()
 		block = append(block, phrase)
 		if err != nil { return }
 	}
@@ -118,6 +154,7 @@ func (parser *parsingOperation) parseBlockLevelPhrase (
 	if parser.token.Value().(int) != indent    { return }
 	err = parser.nextToken(validPhraseStartTokens...)
 	if err != nil { return }
+	phrase.location = parser.token.Location()
 
 	expectRightBracket := false
 	if parser.token.Is(lexer.TokenKindLBracket) {
@@ -232,6 +269,7 @@ func (parser *parsingOperation) parseArgumentLevelPhrase () (
 ) {
 	err = parser.expect(lexer.TokenKindLBracket)
 	if err != nil { return }
+	phrase.location = parser.token.Location()
 
 	// get command
 	err = parser.nextToken(validPhraseStartTokens...)
@@ -252,6 +290,7 @@ func (parser *parsingOperation) parseArgumentLevelPhrase () (
 			// this is an ending delimiter
 			err = parser.nextToken()
 			if err != nil { return }
+			phrase.end = parser.endLocation()
 			return
 			
 		} else if parser.token.Is(lexer.TokenKindNewline) {
@@ -304,7 +343,13 @@ func (parser *parsingOperation) parsePhraseCommand () (
 	command, err = parser.parseArgument()
 	if err != nil { return }
 
-	// determine semantic role of phrase
+	kind = phraseKindOf(command)
+	return
+}
+
+// phraseKindOf determines the semantic role of a phrase from its command, if
+// the command is not an operator.
+func phraseKindOf (command Argument) (kind PhraseKind) {
 	if command.kind == ArgumentKindString {
 		kind = PhraseKindArbitrary
 		
@@ -2,7 +2,9 @@ package parser
 
 import "fmt"
 import "sort"
-import "git.tebibyte.media/arf/arf/lexer"
+import "strconv"
+import "strings"
+import "unicode"
 
 func doIndent (indent int, input ...string) (output string) {
 	for index := 0; index < indent; index ++ {
@@ -14,6 +16,56 @@ func doIndent (indent int, input ...string) (output string) {
 	return
 }
 
+func docToString (indent int, doc string) (output string) {
+	if doc == "" { return }
+	for _, line := range strings.Split(doc, "\n") {
+		if line == "" {
+			output += doIndent(indent, "#\n")
+		} else {
+			output += doIndent(indent, "# ", line, "\n")
+		}
+	}
+	return
+}
+
+// quoteString puts a string in quotes, escaping any characters that cannot be
+// written in a string literal as they are.
+func quoteString (input string) (output string) {
+	output = "'"
+	for _, char := range input {
+		switch char {
+		case '\'', '\\': output += "\\" + string(char)
+		case '\a': output += "\\a"
+		case '\b': output += "\\b"
+		case '\f': output += "\\f"
+		case '\n': output += "\\n"
+		case '\r': output += "\\r"
+		case '\t': output += "\\t"
+		case '\v': output += "\\v"
+		default:
+			if unicode.IsPrint(char) {
+				output += string(char)
+			} else if char <= 0xFFFF {
+				output += fmt.Sprintf("\\u%04x", char)
+			} else {
+				output += fmt.Sprintf("\\U%08x", char)
+			}
+		}
+	}
+	output += "'"
+	return
+}
+
+// formatFloat writes a float so that it will always be read back as a float,
+// even if it has no fractional part.
+func formatFloat (number float64) (output string) {
+	output = strconv.FormatFloat(number, 'f', -1, 64)
+	if !strings.Contains(output, ".") {
+		output += ".0"
+	}
+	return
+}
+
 func sortMapKeysAlphabetically[KEY_TYPE any] (
 	unsortedMap map[string] KEY_TYPE,
 ) (
@@ -34,16 +86,22 @@ func (tree SyntaxTree) ToString (indent int) (output string) {
 	output += doIndent(indent, ":arf\n")
 
 	if tree.author != "" {
-		output += doIndent(indent, "author '", tree.author, "'\n")
+		output += doIndent (
+			indent,
+			"author ", quoteString(tree.author), "\n")
 	}
 
 	if tree.license != "" {
-		output += doIndent(indent, "license '", tree.license, "'\n")
+		output += doIndent (
+			indent,
+			"license ", quoteString(tree.license), "\n")
 	}
 
 	for _, name := range sortMapKeysAlphabetically(tree.requires) {
 		require := tree.requires[name]
-		output += doIndent(indent, "require '", require, "'\n")
+		output += doIndent (
+			indent,
+			"require ", quoteString(require), "\n")
 	}
 	
 	output += doIndent(indent, "---\n")
@@ -149,14 +207,28 @@ func (argument Argument) ToString (indent int, breakLine bool) (output string) {
 			argument.value.(Declaration).ToString())
 		if breakLine { output += "\n" }
 	
-	case ArgumentKindInt, ArgumentKindUInt, ArgumentKindFloat:
+	case ArgumentKindInt:
+		// signed zero needs a minus sign, or it would be read back as
+		// an unsigned number.
+		number := argument.value.(int64)
+		if number == 0 {
+			output += doIndent(indent, "-0")
+		} else {
+			output += doIndent(indent, fmt.Sprint(number))
+		}
+		if breakLine { output += "\n" }
+
+	case ArgumentKindUInt:
 		output += doIndent(indent, fmt.Sprint(argument.value))
 		if breakLine { output += "\n" }
+
+	case ArgumentKindFloat:
+		number := argument.value.(float64)
+		output += doIndent(indent, formatFloat(number))
+		if breakLine { output += "\n" }
 	
 	case ArgumentKindString:
-		output += doIndent (
-			indent,
-			"'" + argument.value.(string) + "'")
+		output += doIndent(indent, quoteString(argument.value.(string)))
 		if breakLine { output += "\n" }
 	}
 
@@ -164,6 +236,7 @@ func (argument Argument) ToString (indent int, breakLine bool) (output string) {
 }
 
 func (section DataSection) ToString (indent int) (output string) {
+	output += docToString(indent, section.doc)
 	output += doIndent (
 		indent,
 		"data ",
@@ -183,6 +256,7 @@ func (section DataSection) ToString (indent int) (output string) {
 }
 
 func (member TypeSectionMember) ToString (indent int) (output string) {
+	output += docToString(indent, member.doc)
 	output += doIndent(indent, member.permission.ToString())
 	output += " " + member.name
 
@@ -204,6 +278,7 @@ func (member TypeSectionMember) ToString (indent int) (output string) {
 }
 
 func (section TypeSection) ToString (indent int) (output string) {
+	output += docToString(indent, section.doc)
 	output += doIndent (
 		indent,
 		"type ",
@@ -222,6 +297,7 @@ func (section TypeSection) ToString (indent int) (output string) {
 }
 
 func (section EnumSection) ToString (indent int) (output string) {
+	output += docToString(indent, section.doc)
 	output += doIndent (
 		indent,
 		"enum ",
@@ -230,6 +306,7 @@ func (section EnumSection) ToString (indent int) (output string) {
 		section.what.ToString(), "\n")
 
 	for _, member := range section.members {
+		output += docToString(indent + 1, member.doc)
 		output += doIndent(indent + 1, "- ", member.name)
 		if member.argument.kind != ArgumentKindNil {
 			output += " " + member.argument.ToString(indent, false)
@@ -240,6 +317,7 @@ func (section EnumSection) ToString (indent int) (output string) {
 }
 
 func (section FaceSection) ToString (indent int) (output string) {
+	output += docToString(indent, section.doc)
 	output += doIndent (
 		indent,
 		"face ",
@@ -266,6 +344,7 @@ func (section FaceSection) ToString (indent int) (output string) {
 }
 
 func (behavior FaceBehavior) ToString (indent int) (output string) {
+	output += docToString(indent, behavior.doc)
 	output += doIndent(indent, behavior.name, "\n")
 	
 	for _, inputItem := range behavior.inputs {
@@ -304,71 +383,7 @@ func (phrase Phrase) ToString (indent int, ownLine bool) (output string) {
         	output += "="
 	
 	case PhraseKindOperator:
-		
-		switch phrase.operator {
-	        case lexer.TokenKindColon:
-	        	output += ":"
-	        case lexer.TokenKindPlus:
-	        	output += "+"
-	        case lexer.TokenKindMinus:
-	        	output += "-"
-	        case lexer.TokenKindIncrement:
-	        	output += "++"
-	        case lexer.TokenKindDecrement:
-	        	output += "--"
-	        case lexer.TokenKindAsterisk:
-	        	output += "*"
-	        case lexer.TokenKindSlash:
-	        	output += "/"
-	        case lexer.TokenKindExclamation:
-	        	output += "!"
-	        case lexer.TokenKindPercent:
-	        	output += "%"
-	        case lexer.TokenKindPercentAssignment:
-	        	output += "%="
-	        case lexer.TokenKindTilde:
-	        	output += "~"
-	        case lexer.TokenKindTildeAssignment:
-	        	output += "~="
-	        case lexer.TokenKindAssignment:
-	        	output += "="
-	        case lexer.TokenKindEqualTo:
-	        	output += "=="
-	        case lexer.TokenKindNotEqualTo:
-	        	output += "!="
-	        case lexer.TokenKindLessThanEqualTo:
-	        	output += "<="
-	        case lexer.TokenKindLessThan:
-	        	output += "<"
-	        case lexer.TokenKindLShift:
-	        	output += "<<"
-	        case lexer.TokenKindLShiftAssignment:
-	        	output += "<<="
-	        case lexer.TokenKindGreaterThan:
-	        	output += ">"
-	        case lexer.TokenKindGreaterThanEqualTo:
-	        	output += ">="
-	        case lexer.TokenKindRShift:
-	        	output += ">>"
-	        case lexer.TokenKindRShiftAssignment:
-	        	output += ">>="
-	        case lexer.TokenKindBinaryOr:
-	        	output += "|"
-	        case lexer.TokenKindBinaryOrAssignment:
-	        	output += "|="
-	        case lexer.TokenKindLogicalOr:
-	        	output += "||"
-	        case lexer.TokenKindBinaryAnd:
-	        	output += "&"
-	        case lexer.TokenKindBinaryAndAssignment:
-	        	output += "&="
-	        case lexer.TokenKindLogicalAnd:
-	        	output += "&&"
-	        case lexer.TokenKindBinaryXor:
-	        	output += "^"
-	        case lexer.TokenKindBinaryXorAssignment:
-	        	output += "^="
-		}
+		output += operatorSymbols[phrase.operator]
 		
 	default:
 		output += phrase.command.ToString(0, false)
@@ -415,6 +430,7 @@ func (block Block) ToString (indent int) (output string) {
 }
 
 func (section FuncSection) ToString (indent int) (output string) {
+	output += docToString(indent, section.doc)
 	output += doIndent (
 		indent,
 		"func ",
@@ -12,7 +12,8 @@ type SyntaxTree struct {
 	license string
 	author  string
 
-	requires map[string] string
+	requires         map[string] string
+	requireLocations map[string] file.Location
 	sections map[string] Section
 }
 
@@ -22,6 +23,7 @@ type Section interface {
 	Location   () (location file.Location)
 	Permission () (permission types.Permission)
 	Name       () (name string)
+	Doc        () (doc string)
 	NewError   (message string, kind infoerr.ErrorKind) (err error)
 	ToString   (indent int) (output string)
 }
@@ -139,6 +141,7 @@ type Argument struct {
 type DataSection struct {
 	locatable
 	nameable
+	documentable
 	typeable
 	permissionable
 	valuable
@@ -150,6 +153,7 @@ type DataSection struct {
 type TypeSectionMember struct {
 	locatable
 	nameable
+	documentable
 	typeable
 	permissionable
 	valuable
@@ -161,6 +165,7 @@ type TypeSectionMember struct {
 type TypeSection struct {
 	locatable
 	nameable
+	documentable
 	typeable
 	permissionable
 	valuable
@@ -173,6 +178,7 @@ type TypeSection struct {
 type EnumMember struct {
 	locatable
 	nameable
+	documentable
 	valuable
 }
 
@@ -180,6 +186,7 @@ type EnumMember struct {
 type EnumSection struct {
 	locatable
 	nameable
+	documentable
 	typeable
 	permissionable
 
@@ -200,6 +207,7 @@ const (
 type FaceBehavior struct {
 	locatable
 	nameable
+	documentable
 
 	inputs  []Declaration
 	outputs []Declaration
@@ -209,6 +217,7 @@ type FaceBehavior struct {
 type FaceSection struct {
 	locatable
 	nameable
+	documentable
 	permissionable
 	inherits Identifier
 
@@ -306,6 +315,7 @@ type FuncOutput struct {
 type FuncSection struct {
 	locatable
 	nameable
+	documentable
 	permissionable
 	
 	receiver *Declaration
@@ -76,5 +76,6 @@ func (parser *parsingOperation) parseType () (what Type, err error) {
 		}
 	}
 
+	what.end = parser.endLocation()
 	return
 }
@@ -13,6 +13,7 @@ func (parser *parsingOperation) parseTypeSection () (
 	if err != nil { return }
 	
 	section.location = parser.token.Location()
+	section.doc      = parser.documentation()
 
 	// get permission
 	err = parser.nextToken(lexer.TokenKindPermission)
@@ -67,6 +68,7 @@ func (parser *parsingOperation) parseTypeSection () (
 		if err != nil { return }
 		var member TypeSectionMember
 		member, err = parser.parseTypeSectionMember()
+		member.end      = parser.endLocation()
 		section.members = append(section.members, member)
 		if err != nil { return }
 	}
@@ -83,6 +85,7 @@ func (parser *parsingOperation) parseTypeSectionMember () (
 	member.permission = parser.token.Value().(types.Permission)
 
 	member.location = parser.token.Location()
+	member.doc      = parser.documentation()
 
 	// get name
 	err = parser.nextToken(lexer.TokenKindName)
@@ -15,6 +15,8 @@ type ro bBitFields:Obj
 type ro cInit:Obj
 	ro that:String 'hello world'
 	ro this:Int 23
+# the semantic analyzer should let these sections restrict the permissions of
+# inherited members, but it should not let the sections lessen the permissions.
 type ro dInitInherit:aBasic
 	ro that 9384
 	ro this 389
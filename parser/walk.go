@@ -0,0 +1,189 @@
+package parser
+
+// Node is any part of a syntax tree. It is one of SyntaxTree, DataSection,
+// TypeSection, TypeSectionMember, EnumSection, EnumMember, FaceSection,
+// FaceBehavior, FuncSection, FuncOutput, Declaration, Block, Phrase, Argument,
+// List, Dereference, Identifier, or Type.
+type Node any
+
+// Visitor has its Visit method called for each node encountered by Walk. If
+// the visitor it returns is not nil, Walk visits each of the children of the
+// node with it, and then calls its Visit method with nil.
+type Visitor interface {
+	Visit (node Node) (visitor Visitor)
+}
+
+// Walk traverses a syntax tree in depth first order. It starts by calling
+// visitor.Visit(node), and continues on to the children of node with the
+// visitor that it returns. Sections are visited in alphabetical order, and
+// everything else is visited in the order that it is written in. Arguments
+// and types that are nil are skipped.
+func Walk (node Node, visitor Visitor) {
+	visitor = visitor.Visit(node)
+	if visitor == nil { return }
+
+	switch node.(type) {
+	case SyntaxTree:
+		tree := node.(SyntaxTree)
+		for _, name := range sortMapKeysAlphabetically(tree.sections) {
+			Walk(tree.sections[name], visitor)
+		}
+
+	case DataSection:
+		section := node.(DataSection)
+		walkType(section.what, visitor)
+		walkArgument(section.argument, visitor)
+
+	case TypeSection:
+		section := node.(TypeSection)
+		walkType(section.what, visitor)
+		walkArgument(section.argument, visitor)
+		for _, member := range section.members {
+			Walk(member, visitor)
+		}
+
+	case TypeSectionMember:
+		member := node.(TypeSectionMember)
+		walkType(member.what, visitor)
+		walkArgument(member.argument, visitor)
+
+	case EnumSection:
+		section := node.(EnumSection)
+		walkType(section.what, visitor)
+		for _, member := range section.members {
+			Walk(member, visitor)
+		}
+
+	case EnumMember:
+		walkArgument(node.(EnumMember).argument, visitor)
+
+	case FaceSection:
+		section := node.(FaceSection)
+		Walk(section.inherits, visitor)
+		switch section.kind {
+		case FaceKindType:
+			names := sortMapKeysAlphabetically(section.behaviors)
+			for _, name := range names {
+				Walk(section.behaviors[name], visitor)
+			}
+		case FaceKindFunc:
+			walkBehavior(section.FaceBehavior, visitor)
+		}
+
+	case FaceBehavior:
+		walkBehavior(node.(FaceBehavior), visitor)
+
+	case FuncSection:
+		section := node.(FuncSection)
+		if section.receiver != nil {
+			Walk(*section.receiver, visitor)
+		}
+		for _, input := range section.inputs {
+			Walk(input, visitor)
+		}
+		for _, output := range section.outputs {
+			Walk(output, visitor)
+		}
+		if section.root != nil {
+			Walk(section.root, visitor)
+		}
+
+	case FuncOutput:
+		output := node.(FuncOutput)
+		Walk(output.Declaration, visitor)
+		walkArgument(output.argument, visitor)
+
+	case Declaration:
+		walkType(node.(Declaration).what, visitor)
+
+	case Block:
+		for _, phrase := range node.(Block) {
+			Walk(phrase, visitor)
+		}
+
+	case Phrase:
+		phrase := node.(Phrase)
+		walkArgument(phrase.command, visitor)
+		for _, argument := range phrase.arguments {
+			walkArgument(argument, visitor)
+		}
+		for _, returnee := range phrase.returnees {
+			walkArgument(returnee, visitor)
+		}
+		if phrase.block != nil {
+			Walk(phrase.block, visitor)
+		}
+
+	case Argument:
+		argument := node.(Argument)
+		switch argument.kind {
+		case
+			ArgumentKindPhrase,
+			ArgumentKindList,
+			ArgumentKindDereference,
+			ArgumentKindIdentifier,
+			ArgumentKindDeclaration:
+
+			Walk(argument.value, visitor)
+		}
+
+	case List:
+		for _, argument := range node.(List).arguments {
+			walkArgument(argument, visitor)
+		}
+
+	case Dereference:
+		walkArgument(node.(Dereference).argument, visitor)
+
+	case Type:
+		what := node.(Type)
+		if what.kind == TypeKindBasic {
+			Walk(what.name, visitor)
+		} else if what.points != nil {
+			walkType(*what.points, visitor)
+		}
+	}
+
+	visitor.Visit(nil)
+}
+
+// walkArgument walks an argument, unless it is nil.
+func walkArgument (argument Argument, visitor Visitor) {
+	if argument.Nil() { return }
+	Walk(argument, visitor)
+}
+
+// walkType walks a type, unless it is nil.
+func walkType (what Type, visitor Visitor) {
+	if what.Nil() { return }
+	Walk(what, visitor)
+}
+
+// walkBehavior walks the inputs and outputs of an interface behavior.
+func walkBehavior (behavior FaceBehavior, visitor Visitor) {
+	for _, input := range behavior.inputs {
+		Walk(input, visitor)
+	}
+	for _, output := range behavior.outputs {
+		Walk(output, visitor)
+	}
+}
+
+// inspector is a visitor that calls a function on each node.
+type inspector func (node Node) (proceed bool)
+
+// Visit calls the inspector function, and continues on to the children of the
+// node if it returns true.
+func (function inspector) Visit (node Node) (visitor Visitor) {
+	if function(node) {
+		visitor = function
+	}
+	return
+}
+
+// Inspect traverses a syntax tree in depth first order, in the same way as
+// Walk. It starts by calling function(node), and if that returns true, it
+// inspects each of the children of node, followed by calling function(nil).
+func Inspect (node Node, function func (node Node) (proceed bool)) {
+	Walk(node, inspector(function))
+}
@@ -0,0 +1,159 @@
+package parser
+
+import "os"
+import "fmt"
+import "testing"
+import "path/filepath"
+
+// nodeNamer is a visitor that writes down the type of every node it visits,
+// indented by how deep the node is.
+type nodeNamer struct {
+	depth  *int
+	output *string
+}
+
+func (namer nodeNamer) Visit (node Node) (visitor Visitor) {
+	if node == nil {
+		*namer.depth --
+		return
+	}
+
+	*namer.output += doIndent(*namer.depth, fmt.Sprintf("%T", node), "\n")
+	*namer.depth ++
+	visitor = namer
+	return
+}
+
+func walkTree (test *testing.T) (tree SyntaxTree) {
+	cwd, _ := os.Getwd()
+	tree, err := Fetch(filepath.Join(cwd, "../tests/parser/walk"), false)
+	if err != nil {
+		test.Log("returned error:")
+		test.Log(err.Error())
+		test.FailNow()
+	}
+	return
+}
+
+func TestWalk (test *testing.T) {
+	tree   := walkTree(test)
+	depth  := 0
+	output := ""
+	Walk(tree, nodeNamer { depth: &depth, output: &output })
+
+	correct :=
+`parser.SyntaxTree
+	parser.DataSection
+		parser.Type
+			parser.Type
+				parser.Identifier
+		parser.Argument
+			parser.List
+				parser.Argument
+				parser.Argument
+	parser.TypeSection
+		parser.Type
+			parser.Identifier
+		parser.TypeSectionMember
+			parser.Type
+				parser.Identifier
+			parser.Argument
+				parser.Dereference
+					parser.Argument
+						parser.Identifier
+	parser.FuncSection
+		parser.Declaration
+			parser.Type
+				parser.Type
+					parser.Identifier
+		parser.Declaration
+			parser.Type
+				parser.Identifier
+		parser.FuncOutput
+			parser.Declaration
+				parser.Type
+					parser.Identifier
+			parser.Argument
+		parser.Block
+			parser.Phrase
+				parser.Argument
+					parser.Identifier
+				parser.Argument
+					parser.Phrase
+						parser.Argument
+							parser.Identifier
+						parser.Argument
+				parser.Block
+					parser.Phrase
+						parser.Argument
+							parser.Declaration
+								parser.Type
+									parser.Identifier
+						parser.Argument
+							parser.Phrase
+								parser.Argument
+									parser.Identifier
+								parser.Argument
+									parser.Identifier
+								parser.Argument
+									parser.Identifier
+			parser.Phrase
+				parser.Argument
+					parser.Identifier
+				parser.Argument
+					parser.Identifier
+				parser.Argument
+					parser.Identifier
+	parser.EnumSection
+		parser.Type
+			parser.Identifier
+		parser.EnumMember
+		parser.EnumMember
+			parser.Argument
+	parser.FaceSection
+		parser.Identifier
+		parser.FaceBehavior
+			parser.Declaration
+				parser.Type
+					parser.Identifier
+	parser.FaceSection
+		parser.Identifier
+		parser.Declaration
+			parser.Type
+				parser.Identifier
+`
+	if output != correct {
+		test.Log("CORRECT:")
+		test.Log(correct)
+		test.Log("RESULT:")
+		test.Log(output)
+		test.Fail()
+	}
+
+	if depth != 0 {
+		test.Log("visitor was not called with nil after every node")
+		test.Fail()
+	}
+}
+
+func TestInspect (test *testing.T) {
+	tree  := walkTree(test)
+	names := []string { }
+	Inspect(tree, func (node Node) (proceed bool) {
+		switch node.(type) {
+		case FuncSection:
+			// nothing inside of functions should be inspected
+			return false
+		case Identifier:
+			names = append(names, node.(Identifier).ToString())
+		}
+		return true
+	})
+
+	correct := "[Int Obj Int aData U8 Face Int Func Int]"
+	if fmt.Sprint(names) != correct {
+		test.Log("CORRECT:", correct)
+		test.Log("RESULT: ", fmt.Sprint(names))
+		test.Fail()
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Package repl implements an interactive prompt for experimenting with the ARF
+language. The function Run reads entries from an input stream one at a time,
+and reports the type of each expression that is entered.
+
+An entry is either a section definition, a phrase, or a command. Everything
+that has been entered so far is kept in a module that only exists in memory:
+sections are added to it as they are, and phrases are added to the body of a
+function within it, so that variables declared by one phrase can be used by the
+next. An entry is only kept if the module can still be analyzed afterwards.
+
+Sections and control flow phrases span multiple lines. After the first line of
+one of these, lines are read until one is blank. The following commands are
+understood:
+
+	:type expression   prints the type of an expression, without keeping it
+	:sections          lists the sections that have been defined, along with
+	                   the sections of each module that has been loaded
+	:load module       makes a module available, the same way require does
+*/
+package repl
+
+import "io"
+import "os"
+import "fmt"
+import "bufio"
+import "strings"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/file"
+import "git.tebibyte.media/arf/arf/lexer"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/target"
+import "git.tebibyte.media/arf/arf/analyzer"
+
+// sessionName is the name of the function that holds the phrases entered into
+// a session.
+const sessionName = "replSession"
+
+// sectionKeywords lists the words that begin a section definition.
+var sectionKeywords = []string { "data", "type", "face", "enum", "func" }
+
+// blockKeywords lists the words that begin a phrase with a block beneath it.
+var blockKeywords = []string {
+	"if", "elseif", "else", "switch", "while", "for", "defer",
+}
+
+// session holds the state of a running prompt.
+type session struct {
+	reader   *bufio.Reader
+	output   io.Writer
+	platform target.Target
+
+	// modulePath is the path of the module that holds the session. It is
+	// within an empty temporary directory, so that nothing on the
+	// filesystem is mistaken for a part of it.
+	modulePath string
+
+	// current is everything that has been entered so far, and table is the
+	// result of analyzing it.
+	current state
+	table   analyzer.SectionTable
+
+	// results is how many phrases were in the body of the session function
+	// the last time it was analyzed. Any more than this are new.
+	results int
+
+	// pending holds a line that was read, but that did not belong to the
+	// entry that was being read at the time.
+	pending    string
+	hasPending bool
+	ended      bool
+}
+
+// Run reads entries from input until it ends, and writes prompts, results, and
+// errors to output. Mistakes in the entries are reported to output, and do not
+// stop the session. The platform is passed on to the analyzer.
+func Run (
+	input    io.Reader,
+	output   io.Writer,
+	platform target.Target,
+) (
+	err error,
+) {
+	directory, err := os.MkdirTemp("", "arf-repl-")
+	if err != nil { return }
+	defer os.Remove(directory)
+
+	session := session {
+		reader:     bufio.NewReader(input),
+		output:     output,
+		platform:   platform,
+		modulePath: filepath.Join(directory, "repl"),
+	}
+	defer parser.ClearOverlay(session.filePath())
+
+	for {
+		var entry []string
+		var more  bool
+		entry, more, err = session.readEntry()
+		if err != nil { return }
+		if !more      { break }
+		if len(entry) == 0 { continue }
+
+		err = session.evaluate(entry)
+		if err != nil { return }
+	}
+
+	_, err = fmt.Fprintln(output)
+	return
+}
+
+// filePath returns the path of the file that the session is kept in.
+func (session *session) filePath () (path string) {
+	path = filepath.Join(session.modulePath, "main.arf")
+	return
+}
+
+// readEntry reads the lines of the next entry. If the first line begins a
+// section or a control flow phrase, lines are read until a blank one, or until
+// one that is not indented and does not continue the entry. If the input has
+// ended, more is false.
+func (session *session) readEntry () (entry []string, more bool, err error) {
+	line, more, err := session.readLine("> ")
+	if err != nil || !more { return }
+	if strings.TrimSpace(line) == "" { return }
+
+	entry = []string { line }
+	if isCommand(line) { return }
+
+	tokens := tokenize(line)
+	section := startsWith(tokens, sectionKeywords...)
+	if !section && !startsWith(tokens, blockKeywords...) { return }
+
+	// if phrases are continued by elseif and else, and switch phrases are
+	// continued by cases. these are not indented.
+	branches := startsWith(tokens, "if")
+	cases    := startsWith(tokens, "switch")
+
+	for {
+		var read bool
+		line, read, err = session.readLine("... ")
+		if err != nil || !read { return }
+		if strings.TrimSpace(line) == "" { return }
+
+		tokens := tokenize(line)
+		continues := !isCommand(line) && len(tokens) > 0 && (
+			tokens[0].Is(lexer.TokenKindIndent) ||
+			branches && startsWith(tokens, "elseif", "else") ||
+			cases    && tokens[0].Is(lexer.TokenKindColon))
+		if !continues {
+			session.pending    = line
+			session.hasPending = true
+			return
+		}
+		entry = append(entry, line)
+	}
+}
+
+// readLine returns the next line of input, without its line break. The prompt
+// is written first, unless the line was already read. If the input has ended,
+// read is false.
+func (session *session) readLine (
+	prompt string,
+) (
+	line string,
+	read bool,
+	err  error,
+) {
+	if session.hasPending {
+		session.hasPending = false
+		line, read = session.pending, true
+		return
+	}
+	if session.ended { return }
+
+	_, err = fmt.Fprint(session.output, prompt)
+	if err != nil { return }
+
+	line, err = session.reader.ReadString('\n')
+	if err == io.EOF {
+		err = nil
+		session.ended = true
+		if line == "" { return }
+	}
+	if err != nil { return }
+
+	line = strings.TrimSuffix(line, "\n")
+	line = strings.TrimSuffix(line, "\r")
+	read = true
+	return
+}
+
+// isCommand returns whether a line is a command, such as :type. Lines that
+// begin with a colon followed by anything other than a lowercase letter are
+// cases.
+func isCommand (line string) (command bool) {
+	if len(line) < 2 || line[0] != ':' { return }
+	command = line[1] >= 'a' && line[1] <= 'z'
+	return
+}
+
+// tokenize lexes a single line of input. The line is placed after a metadata
+// header so that the lexer accepts it, and the tokens of the header are
+// removed. Lines that cannot be lexed produce no tokens.
+func tokenize (line string) (tokens []lexer.Token) {
+	header := ":arf\n---\n"
+	tokens, err := lexer.Tokenize(file.Load("input", header + line + "\n"))
+	if err != nil || len(tokens) < 2 {
+		tokens = nil
+		return
+	}
+	tokens = tokens[2:]
+	return
+}
+
+// startsWith returns whether the first token is a name that is one of the
+// specified words.
+func startsWith (tokens []lexer.Token, words ...string) (starts bool) {
+	if len(tokens) == 0 || !tokens[0].Is(lexer.TokenKindName) { return }
+	name := tokens[0].Value().(string)
+	for _, word := range words {
+		if name == word {
+			starts = true
+			return
+		}
+	}
+	return
+}
@@ -0,0 +1,147 @@
+package repl
+
+import "strings"
+import "testing"
+import "git.tebibyte.media/arf/arf/target"
+
+func checkSession (input string, correct string, test *testing.T) {
+	output := strings.Builder { }
+	err := Run(strings.NewReader(input), &output, target.Default)
+	if err != nil {
+		test.Log("session failed:", err)
+		test.Fail()
+		return
+	}
+
+	if output.String() != correct {
+		test.Log("mismatched output")
+		test.Log("- want:\n" + correct)
+		test.Log("- have:\n" + output.String())
+		test.Fail()
+	}
+}
+
+func TestPhrases (test *testing.T) {
+	checkSession (
+`= x:Int:mut 5
+[+ x 1]
+++ x
+[cast x F64]
+[== x 6]
+:type x
+:type 'hello'
+= y:Int nothing
+[+ y 1]
+:type [* x 1.5]
+`,
+`> > Int
+> > F64
+> Bool
+> Int:mut
+> String
+> = y:Int nothing
+        ------^
+can't find anything called "nothing" within current scope
+> [+ y 1]
+   ^
+can't find anything called "y" within current scope
+> [* x 1.5]
+     --^
+float literal cannot be used with integer operands of type Int:mut
+> 
+`, test)
+}
+
+func TestSections (test *testing.T) {
+	checkSession (
+`type ro Point:Obj
+	rw x:Int
+	rw y:Int
+
+func ro sum
+	@ point:{Point}
+	< result:Int
+	---
+	= result [+ point.x point.y]
+data ro origin:Point
+= point:Point:mut origin
+point.sum
+:sections
+`,
+`> ... ... ... > ... ... ... ... ... ... > Int
+> type Point:Obj
+func Point.sum
+data origin:repl.Point
+> 
+`, test)
+}
+
+func TestControlFlow (test *testing.T) {
+	checkSession (
+`= count:Int:mut 0
+if [< count 3]
+	++ count
+elseif [== count 3]
+	-- count
+else
+	= count 0
+switch count
+: 1 2
+	++ count
+:
+	-- count
+
+while [< count 10]
+	++ count
+[* count 2]
+`,
+`> > ... ... ... ... ... ... ... ... ... ... ... > ... ... Int
+> 
+`, test)
+}
+
+func TestLoad (test *testing.T) {
+	checkSession (
+`:load ../tests/repl/shapes
+:sections
+= square:shapes.Square:mut shapes.unit
+square.area
+:type [loc square]
+:load ../tests/repl/shapes
+:load nonexistent
+:unknown
+`,
+`> > type shapes.Square:Obj
+func shapes.Square.area
+data shapes.unit:shapes.Square
+> > Int
+> {shapes.Square:mut}
+> a module named shapes is already loaded
+> open /usr/local/include/arf/nonexistent/: no such file or directory
+> unknown command :unknown, expected :type, :sections, or :load
+> 
+`, test)
+}
+
+func TestPanic (test *testing.T) {
+	// a platform with no sizes in it makes the analyzer panic. the session
+	// should carry on regardless.
+	output := strings.Builder { }
+	err := Run (
+		strings.NewReader("[+ 1 2]\n:type 'hello'\n"),
+		&output, target.Target { })
+	if err != nil {
+		test.Log("session failed:", err)
+		test.Fail()
+		return
+	}
+
+	result := output.String()
+	recovered :=
+		strings.HasPrefix(result, "> internal error: ") &&
+		strings.HasSuffix(result, "\n> String\n> \n")
+	if !recovered {
+		test.Log("panic was not recovered from:\n" + result)
+		test.Fail()
+	}
+}
@@ -0,0 +1,368 @@
+package repl
+
+import "fmt"
+import "strings"
+import "path/filepath"
+import "git.tebibyte.media/arf/arf/types"
+import "git.tebibyte.media/arf/arf/parser"
+import "git.tebibyte.media/arf/arf/analyzer"
+import "git.tebibyte.media/arf/arf/infoerr"
+
+// state is everything that has been entered into a session.
+type state struct {
+	// requires holds the full path of each module that has been loaded.
+	requires []string
+
+	// sections holds the source code of each section.
+	sections []string
+
+	// phrases holds the source code of each phrase, indented so that it
+	// can be placed in the body of the session function.
+	phrases []string
+}
+
+// source returns the source code of a module that contains everything in the
+// state.
+func (current state) source () (source string) {
+	source = ":arf\n"
+	for _, modulePath := range current.requires {
+		source += "require '" + modulePath + "'\n"
+	}
+	source += "---\n"
+
+	for _, section := range current.sections {
+		source += "\n" + section + "\n"
+	}
+
+	if len(current.phrases) > 0 {
+		source += "\nfunc ro " + sessionName + "\n\t---\n"
+		for _, phrase := range current.phrases {
+			source += phrase + "\n"
+		}
+	}
+	return
+}
+
+// evaluate adds an entry to the session, or runs it if it is a command. If the
+// entry has a mistake in it, the mistake is reported and the entry is thrown
+// away. The same happens if evaluating the entry panics, so that a bug in the
+// compiler does not end the whole session.
+func (session *session) evaluate (entry []string) (err error) {
+	defer func () {
+		recovered := recover()
+		if recovered == nil { return }
+		err = session.report (
+			fmt.Errorf("internal error: %v", recovered))
+	} ()
+
+	if isCommand(entry[0]) {
+		err = session.command(entry[0])
+		return
+	}
+
+	candidate := session.current
+	if startsWith(tokenize(entry[0]), sectionKeywords...) {
+		section := strings.Join(entry, "\n")
+		candidate.sections = append(candidate.sections, section)
+	} else {
+		candidate.phrases = append(candidate.phrases, indent(entry))
+	}
+
+	table, failure := session.analyze(candidate)
+	if failure != nil {
+		err = session.report(failure)
+		return
+	}
+	session.current = candidate
+	session.table   = table
+
+	phrases := session.phrases(table)
+	for _, phrase := range phrases[session.results:] {
+		what, hasValue := result(phrase)
+		if !hasValue { continue }
+		err = session.print(what.Describe())
+		if err != nil { return }
+	}
+	session.results = len(phrases)
+	return
+}
+
+// command runs a command, such as :type.
+func (session *session) command (line string) (err error) {
+	name, argument, _ := strings.Cut(line[1:], " ")
+	argument = strings.TrimSpace(argument)
+
+	switch name {
+	case "type":
+		if argument == "" {
+			err = session.print("usage: :type expression")
+			return
+		}
+		err = session.typeOf(argument)
+
+	case "sections":
+		err = session.listSections()
+
+	case "load":
+		if argument == "" {
+			err = session.print("usage: :load module")
+			return
+		}
+		err = session.load(argument)
+
+	default:
+		err = session.print (
+			"unknown command :" + name +
+			", expected :type, :sections, or :load")
+	}
+	return
+}
+
+// typeOf prints the type of an expression. The expression is analyzed as the
+// argument of an arbitrary phrase at the end of the session function, which
+// is thrown away afterwards.
+func (session *session) typeOf (expression string) (err error) {
+	candidate := session.current
+	phrase := "\t'" + sessionName + "' " + expression
+	candidate.phrases = append(candidate.phrases, phrase)
+
+	table, failure := session.analyze(candidate)
+	if failure != nil {
+		err = session.report(failure)
+		return
+	}
+
+	phrases := session.phrases(table)
+	last    := phrases[len(phrases) - 1].(analyzer.ArbitraryPhrase)
+	if len(last.Arguments()) != 1 {
+		err = session.print(":type expects exactly one expression")
+		return
+	}
+	err = session.print(last.Arguments()[0].What().Describe())
+	return
+}
+
+// listSections prints the sections that have been entered into the session,
+// followed by the sections of each module that has been loaded. Private
+// sections of loaded modules are left out, because they cannot be used.
+func (session *session) listSections () (err error) {
+	for _, section := range session.table.Sorted() {
+		if section.ModulePath() != session.modulePath { continue }
+		if section.Name() == sessionName              { continue }
+		err = session.print(describeSection(section, ""))
+		if err != nil { return }
+	}
+
+	for _, modulePath := range session.current.requires {
+		table, failure := analyzer.AnalyzeQuietly (
+			modulePath, true,
+			session.platform)
+		if failure != nil {
+			err = session.report(failure)
+			if err != nil { return }
+			continue
+		}
+
+		prefix := filepath.Base(modulePath) + "."
+		for _, section := range table.Sorted() {
+			if section.ModulePath() != modulePath { continue }
+			if section.Permission() == types.PermissionPrivate {
+				continue
+			}
+			err = session.print(describeSection(section, prefix))
+			if err != nil { return }
+		}
+	}
+	return
+}
+
+// load makes a module available to the session. The module is found the same
+// way that it would be by a require in the metadata header, except that
+// relative paths are relative to the current directory.
+func (session *session) load (modulePath string) (err error) {
+	if modulePath[0] == '.' {
+		modulePath, err = filepath.Abs(modulePath)
+		if err != nil { return }
+	} else if modulePath[0] != '/' {
+		modulePath = filepath.Join(parser.IncludePath, modulePath)
+	}
+	modulePath = filepath.Clean(modulePath)
+
+	name := filepath.Base(modulePath)
+	for _, loaded := range session.current.requires {
+		if filepath.Base(loaded) == name {
+			err = session.print (
+				"a module named " + name + " is already loaded")
+			return
+		}
+	}
+
+	_, failure := parser.Fetch(modulePath, true)
+	if failure != nil {
+		err = session.report(failure)
+		return
+	}
+
+	candidate := session.current
+	candidate.requires = append(candidate.requires, modulePath)
+
+	table, failure := session.analyze(candidate)
+	if failure != nil {
+		err = session.report(failure)
+		return
+	}
+	session.current = candidate
+	session.table   = table
+	return
+}
+
+// analyze analyzes the module that holds a state. The session is analyzed again
+// after every entry, so warnings are not printed, or they would be printed over
+// and over.
+func (session *session) analyze (
+	candidate state,
+) (
+	table analyzer.SectionTable,
+	err   error,
+) {
+	parser.SetOverlay(session.filePath(), candidate.source())
+	table, err = analyzer.AnalyzeQuietly (
+		session.modulePath, false,
+		session.platform)
+	return
+}
+
+// phrases returns the phrases in the body of the session function.
+func (session *session) phrases (
+	table analyzer.SectionTable,
+) (
+	phrases []analyzer.Phrase,
+) {
+	for _, section := range table.Sorted() {
+		if section.ModulePath() != session.modulePath { continue }
+		if section.Name() != sessionName              { continue }
+
+		function, isFunction := section.(*analyzer.FuncSection)
+		if !isFunction { continue }
+		phrases = function.Root().Phrases()
+	}
+	return
+}
+
+// report prints an error. Errors within the session are printed along with
+// the line they are on, but not where that line is, because the module that
+// holds the session is never seen by the user.
+func (session *session) report (failure error) (err error) {
+	problem, isProblem := failure.(infoerr.Error)
+	if !isProblem {
+		err = session.print(failure.Error())
+		return
+	}
+
+	inSession :=
+		problem.File() != nil &&
+		problem.File().Path() == session.filePath()
+	if !inSession {
+		_, err = fmt.Fprint(session.output, problem.Error())
+		return
+	}
+
+	if problem.Width() > 0 {
+		line   := problem.File().GetLine(problem.Row())
+		start  := len(line) - len(strings.TrimLeft(line, "\t"))
+		column := problem.Column()
+
+		// expressions given to :type are wrapped in an arbitrary
+		// phrase, which the user never typed in. so, it is cut off,
+		// and the marker is kept within what the user did type.
+		wrapper := "'" + sessionName + "' "
+		if strings.HasPrefix(line[start:], wrapper) {
+			start += len(wrapper)
+			if column < start { column = start }
+		} else if column < start {
+			start = column
+		}
+
+		marker := ""
+		for index := start; index < column; index ++ {
+			if line[index] == '\t' {
+				marker += "\t"
+			} else {
+				marker += " "
+			}
+		}
+		marker += strings.Repeat("-", problem.Width() - 1) + "^"
+
+		err = session.print(line[start:])
+		if err != nil { return }
+		err = session.print(marker)
+		if err != nil { return }
+	}
+
+	err = session.print(problem.Message())
+	return
+}
+
+// print writes a line of text to the output.
+func (session *session) print (text string) (err error) {
+	_, err = fmt.Fprintln(session.output, text)
+	return
+}
+
+// indent indents each line of a phrase entry, so that it can be placed in the
+// body of the session function.
+func indent (entry []string) (indented string) {
+	indented = "\t" + strings.Join(entry, "\n\t")
+	return
+}
+
+// result returns the type of the value that a phrase produces. Phrases such as
+// assignments, and calls to functions that have no output, do not produce one.
+func result (phrase analyzer.Phrase) (what analyzer.Type, hasValue bool) {
+	call, isCall := phrase.(analyzer.CallPhrase)
+	if isCall && len(call.Function().Outputs()) == 0 { return }
+
+	argument, isArgument := phrase.(analyzer.Argument)
+	if !isArgument { return }
+	what     = argument.What()
+	hasValue = true
+	return
+}
+
+// describeSection describes a section in a single line, in a form similar to
+// how it is defined. Methods are named after the type they belong to.
+func describeSection (
+	section analyzer.Section,
+	prefix  string,
+) (
+	description string,
+) {
+	name := prefix + section.Name()
+	switch section.(type) {
+	case *analyzer.DataSection:
+		what := section.(*analyzer.DataSection).What()
+		description = "data " + name + ":" + what.Describe()
+
+	case *analyzer.TypeSection:
+		what := section.(*analyzer.TypeSection).What()
+		description = "type " + name + ":" + what.Describe()
+
+	case *analyzer.EnumSection:
+		what := section.(*analyzer.EnumSection).What()
+		description = "enum " + name + ":" + what.Describe()
+
+	case *analyzer.FaceSection:
+		description = "face " + name
+
+	case *analyzer.FuncSection:
+		receiver := section.(*analyzer.FuncSection).Receiver()
+		if receiver != nil {
+			owner := receiver.What().Points().Actual().Name()
+			name   =
+				prefix + owner + "." +
+				strings.TrimPrefix(section.Name(), owner + "_")
+		}
+		description = "func " + name
+	}
+	return
+}
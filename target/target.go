@@ -0,0 +1,163 @@
+/*
+Package target describes the machines that ARF code can be compiled for. A
+target determines how large a word is, how large a pointer is, which order bytes
+are stored in, and how values are aligned in memory. The analyzer uses this to
+check the range of values stored in Int and UInt, and the translator uses it to
+decide which C types to emit.
+
+Targets are plain data, so new ones can be described without changing any other
+part of the compiler.
+*/
+package target
+
+import "fmt"
+import "sort"
+
+// Endianness determines the order in which the bytes of a value are stored in
+// memory.
+type Endianness int
+
+const (
+	// EndiannessLittle means the least significant byte is stored first.
+	EndiannessLittle Endianness = iota
+
+	// EndiannessBig means the most significant byte is stored first.
+	EndiannessBig
+)
+
+// ToString returns the name of the endianness.
+func (endianness Endianness) ToString () (output string) {
+	switch endianness {
+	case EndiannessLittle:
+		output = "little"
+	case EndiannessBig:
+		output = "big"
+	}
+	return
+}
+
+// Target describes a machine that code can be compiled for. All sizes are
+// measured in bytes.
+type Target struct {
+	// Name is the name used to select the target, for example "x86_64".
+	Name string
+
+	// Triple is the target triple that is passed on to the C compiler.
+	Triple string
+
+	// WordSize is the size of Int and UInt.
+	WordSize int
+
+	// PointerSize is the size of a pointer.
+	PointerSize int
+
+	// Endianness is the order in which the bytes of a value are stored.
+	Endianness Endianness
+
+	// MaxAlignment is the largest alignment that a primitive value will
+	// ever need. Primitives are aligned to their own size, up to this
+	// amount.
+	MaxAlignment int
+}
+
+// X86_64 is a 64 bit x86 machine.
+var X86_64 = Target {
+	Name:         "x86_64",
+	Triple:       "x86_64-linux-gnu",
+	WordSize:     8,
+	PointerSize:  8,
+	Endianness:   EndiannessLittle,
+	MaxAlignment: 8,
+}
+
+// I386 is a 32 bit x86 machine. 64 bit values are only aligned to 4 bytes.
+var I386 = Target {
+	Name:         "i386",
+	Triple:       "i386-linux-gnu",
+	WordSize:     4,
+	PointerSize:  4,
+	Endianness:   EndiannessLittle,
+	MaxAlignment: 4,
+}
+
+// AArch64 is a 64 bit ARM machine.
+var AArch64 = Target {
+	Name:         "aarch64",
+	Triple:       "aarch64-linux-gnu",
+	WordSize:     8,
+	PointerSize:  8,
+	Endianness:   EndiannessLittle,
+	MaxAlignment: 8,
+}
+
+// RISCV32 is a 32 bit RISC-V machine. Unlike i386, 64 bit values are aligned
+// to 8 bytes.
+var RISCV32 = Target {
+	Name:         "riscv32",
+	Triple:       "riscv32-unknown-elf",
+	WordSize:     4,
+	PointerSize:  4,
+	Endianness:   EndiannessLittle,
+	MaxAlignment: 8,
+}
+
+// builtIn holds all of the targets that the compiler knows about by default.
+var builtIn = map[string] Target {
+	X86_64.Name:  X86_64,
+	I386.Name:    I386,
+	AArch64.Name: AArch64,
+	RISCV32.Name: RISCV32,
+}
+
+// Default is the target used when none is specified.
+var Default = X86_64
+
+// Find returns the built in target with the specified name. If there is no
+// such target, an error is returned.
+func Find (name string) (target Target, err error) {
+	target, exists := builtIn[name]
+	if !exists {
+		err = fmt.Errorf (
+			"unknown target \"%s\", expected one of: %v",
+			name, Names())
+	}
+	return
+}
+
+// Names returns the names of all built in targets, sorted alphabetically.
+func Names () (names []string) {
+	for name := range builtIn {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// WordBits returns the size of Int and UInt in bits.
+func (target Target) WordBits () (bits int) {
+	bits = target.WordSize * 8
+	return
+}
+
+// Alignment returns the alignment of a primitive value of the specified size.
+func (target Target) Alignment (size int) (alignment int) {
+	alignment = size
+	if alignment > target.MaxAlignment {
+		alignment = target.MaxAlignment
+	}
+	if alignment < 1 {
+		alignment = 1
+	}
+	return
+}
+
+// ToString returns a description of the target.
+func (target Target) ToString () (output string) {
+	output = fmt.Sprint (
+		target.Name, " (", target.Triple, "): ",
+		"word ",      target.WordSize,     ", ",
+		"pointer ",   target.PointerSize,  ", ",
+		"align ",     target.MaxAlignment, ", ",
+		target.Endianness.ToString(), " endian")
+	return
+}
@@ -0,0 +1,65 @@
+package target
+
+import "testing"
+
+func TestFind (test *testing.T) {
+	for _, name := range []string { "x86_64", "i386", "aarch64", "riscv32" } {
+		found, err := Find(name)
+		if err != nil {
+			test.Log("could not find", name + ":", err)
+			test.Fail()
+			continue
+		}
+		if found.Name != name {
+			test.Log("found", found.Name, "instead of", name)
+			test.Fail()
+		}
+	}
+
+	_, err := Find("pdp11")
+	if err == nil {
+		test.Log("found a target that does not exist")
+		test.Fail()
+	}
+}
+
+func TestSizes (test *testing.T) {
+	check := func (target Target, word, pointer int) {
+		if target.WordSize != word || target.PointerSize != pointer {
+			test.Log (
+				target.Name, "has word", target.WordSize,
+				"and pointer", target.PointerSize,
+				"but expected", word, "and", pointer)
+			test.Fail()
+		}
+	}
+
+	check(X86_64,  8, 8)
+	check(I386,    4, 4)
+	check(AArch64, 8, 8)
+	check(RISCV32, 4, 4)
+
+	if I386.WordBits() != 32 {
+		test.Log("i386 word is", I386.WordBits(), "bits")
+		test.Fail()
+	}
+}
+
+func TestAlignment (test *testing.T) {
+	check := func (target Target, size, correct int) {
+		alignment := target.Alignment(size)
+		if alignment != correct {
+			test.Log (
+				"alignment of", size, "on", target.Name, "is",
+				alignment, "but expected", correct)
+			test.Fail()
+		}
+	}
+
+	check(X86_64,  1, 1)
+	check(X86_64,  8, 8)
+	check(I386,    2, 2)
+	check(I386,    8, 4)
+	check(RISCV32, 8, 8)
+	check(AArch64, 0, 1)
+}
@@ -1,11 +1,53 @@
+/*
+Package translator translates an analyzed ARF module into C. The size of machine
+dependent types in the output is decided by the target that is passed in.
+*/
 package translator
 
+import "io"
+import "fmt"
+import "git.tebibyte.media/arf/arf/target"
 import "git.tebibyte.media/arf/arf/analyzer"
 
 // Translate takes in a path to a module and an io.Writer, and outputs the
 // corresponding C through the writer. The C code will import nothing and
 // function as a standalone translation unit.
-func Translate (modulePath string, output io.Writer) (err error) {
-	// TODO
+func Translate (
+	modulePath string,
+	platform   target.Target,
+	output     io.Writer,
+) (
+	err error,
+) {
+	_, err = analyzer.Analyze(modulePath, false, platform)
+	if err != nil { return }
+
+	_, err = io.WriteString(output, translatePrimitives(platform))
+	if err != nil { return }
+
+	// TODO: translate sections
+	return
+}
+
+// translatePrimitives returns C type definitions for every ARF primitive on the
+// specified platform. The fixed size integers map onto the same C types on
+// every supported platform, but Int and UInt are as wide as a word.
+func translatePrimitives (platform target.Target) (output string) {
+	output += fmt.Sprintf("/* target: %s */\n", platform.Triple)
+	output += "typedef signed char        I8;\n"
+	output += "typedef signed short       I16;\n"
+	output += "typedef signed int         I32;\n"
+	output += "typedef signed long long   I64;\n"
+	output += "typedef unsigned char      U8;\n"
+	output += "typedef unsigned short     U16;\n"
+	output += "typedef unsigned int       U32;\n"
+	output += "typedef unsigned long long U64;\n"
+	output += "typedef float              F32;\n"
+	output += "typedef double             F64;\n"
+	output += "typedef unsigned char      Bool;\n"
+
+	word := platform.WordBits()
+	output += fmt.Sprintf("typedef I%d Int;\n",  word)
+	output += fmt.Sprintf("typedef U%d UInt;\n", word)
 	return
 }
@@ -0,0 +1,21 @@
+package translator
+
+import "strings"
+import "testing"
+import "git.tebibyte.media/arf/arf/target"
+
+func TestWordTypes (test *testing.T) {
+	check := func (platform target.Target, correct string) {
+		output := translatePrimitives(platform)
+		if !strings.Contains(output, correct) {
+			test.Log(platform.Name, "output does not contain", correct)
+			test.Log(output)
+			test.Fail()
+		}
+	}
+
+	check(target.X86_64,  "typedef I64 Int;\n")
+	check(target.I386,    "typedef I32 Int;\n")
+	check(target.AArch64, "typedef U64 UInt;\n")
+	check(target.RISCV32, "typedef U32 UInt;\n")
+}